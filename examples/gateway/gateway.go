@@ -14,7 +14,10 @@ import (
 
 // newGateway returns a new gateway server which translates HTTP into gRPC.
 func newGateway(ctx context.Context, conn *grpc.ClientConn, opts []gwruntime.ServeMuxOption) (http.Handler, error) {
-
+	// The example services send application trailers, which are exposed unless opts say otherwise.
+	opts = append([]gwruntime.ServeMuxOption{gwruntime.WithOutgoingTrailerMatcher(func(key string) (string, bool) {
+		return gwruntime.MetadataTrailerPrefix + key, true
+	})}, opts...)
 	mux := gwruntime.NewServeMux(opts...)
 
 	for _, f := range []func(context.Context, *gwruntime.ServeMux, *grpc.ClientConn) error{
@@ -23,7 +23,6 @@ import (
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -221,7 +220,7 @@ func request_ABitOfEverythingService_Create_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "enum_value")
 	}
 
-	e, err = runtime.Enum(val, NumericEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "enum_value", err)
@@ -234,7 +233,7 @@ func request_ABitOfEverythingService_Create_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "path_enum_value")
 	}
 
-	e, err = runtime.Enum(val, pathenum.PathEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, pathenum.PathEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_enum_value", err)
@@ -247,7 +246,7 @@ func request_ABitOfEverythingService_Create_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "nested_path_enum_value")
 	}
 
-	e, err = runtime.Enum(val, pathenum.MessagePathEnum_NestedPathEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, pathenum.MessagePathEnum_NestedPathEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "nested_path_enum_value", err)
@@ -260,7 +259,7 @@ func request_ABitOfEverythingService_Create_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "enum_value_annotation")
 	}
 
-	e, err = runtime.Enum(val, NumericEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "enum_value_annotation", err)
@@ -271,11 +270,23 @@ func request_ABitOfEverythingService_Create_0(ctx context.Context, marshaler run
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_Create_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_Create_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.Create(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Create(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -462,7 +473,7 @@ func local_request_ABitOfEverythingService_Create_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "enum_value")
 	}
 
-	e, err = runtime.Enum(val, NumericEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "enum_value", err)
@@ -475,7 +486,7 @@ func local_request_ABitOfEverythingService_Create_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "path_enum_value")
 	}
 
-	e, err = runtime.Enum(val, pathenum.PathEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, pathenum.PathEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_enum_value", err)
@@ -488,7 +499,7 @@ func local_request_ABitOfEverythingService_Create_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "nested_path_enum_value")
 	}
 
-	e, err = runtime.Enum(val, pathenum.MessagePathEnum_NestedPathEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, pathenum.MessagePathEnum_NestedPathEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "nested_path_enum_value", err)
@@ -501,7 +512,7 @@ func local_request_ABitOfEverythingService_Create_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "enum_value_annotation")
 	}
 
-	e, err = runtime.Enum(val, NumericEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "enum_value_annotation", err)
@@ -509,10 +520,20 @@ func local_request_ABitOfEverythingService_Create_0(ctx context.Context, marshal
 
 	protoReq.EnumValueAnnotation = NumericEnum(e)
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_Create_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_Create_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Create(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -530,7 +551,16 @@ func request_ABitOfEverythingService_CreateBody_0(ctx context.Context, marshaler
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.CreateBody(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.CreateBody(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -547,6 +577,13 @@ func local_request_ABitOfEverythingService_CreateBody_0(ctx context.Context, mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.CreateBody(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -574,7 +611,16 @@ func request_ABitOfEverythingService_Lookup_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
-	msg, err := client.Lookup(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Lookup(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -601,6 +647,13 @@ func local_request_ABitOfEverythingService_Lookup_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Lookup(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -636,7 +689,16 @@ func request_ABitOfEverythingService_Update_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
-	msg, err := client.Update(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Update(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -671,6 +733,13 @@ func local_request_ABitOfEverythingService_Update_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Update(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -691,6 +760,9 @@ func request_ABitOfEverythingService_UpdateV2_0(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Abe); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "abe"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -713,11 +785,23 @@ func request_ABitOfEverythingService_UpdateV2_0(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_UpdateV2_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_UpdateV2_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.UpdateV2(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.UpdateV2(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -733,6 +817,9 @@ func local_request_ABitOfEverythingService_UpdateV2_0(ctx context.Context, marsh
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Abe); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "abe"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -752,10 +839,20 @@ func local_request_ABitOfEverythingService_UpdateV2_0(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "abe.uuid", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_UpdateV2_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_UpdateV2_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.UpdateV2(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -785,6 +882,13 @@ func request_ABitOfEverythingService_UpdateV2_1(ctx context.Context, marshaler r
 		}
 	}
 
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "abe", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "abe"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	var (
 		val string
 		ok  bool
@@ -806,11 +910,23 @@ func request_ABitOfEverythingService_UpdateV2_1(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_UpdateV2_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_UpdateV2_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.UpdateV2(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.UpdateV2(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -835,6 +951,13 @@ func local_request_ABitOfEverythingService_UpdateV2_1(ctx context.Context, marsh
 		}
 	}
 
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "abe", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "abe"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	var (
 		val string
 		ok  bool
@@ -853,10 +976,20 @@ func local_request_ABitOfEverythingService_UpdateV2_1(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "abe.uuid", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_UpdateV2_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_UpdateV2_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.UpdateV2(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -873,6 +1006,9 @@ func request_ABitOfEverythingService_UpdateV2_2(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "*", newReader); err != nil {
+		return nil, metadata, err
+	}
 
 	var (
 		val string
@@ -892,7 +1028,16 @@ func request_ABitOfEverythingService_UpdateV2_2(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "abe.uuid", err)
 	}
 
-	msg, err := client.UpdateV2(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.UpdateV2(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -908,6 +1053,9 @@ func local_request_ABitOfEverythingService_UpdateV2_2(ctx context.Context, marsh
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "*", newReader); err != nil {
+		return nil, metadata, err
+	}
 
 	var (
 		val string
@@ -927,6 +1075,13 @@ func local_request_ABitOfEverythingService_UpdateV2_2(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "abe.uuid", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.UpdateV2(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -954,7 +1109,16 @@ func request_ABitOfEverythingService_Delete_0(ctx context.Context, marshaler run
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
-	msg, err := client.Delete(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Delete(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -981,6 +1145,13 @@ func local_request_ABitOfEverythingService_Delete_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Delete(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1015,11 +1186,23 @@ func request_ABitOfEverythingService_GetQuery_0(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_GetQuery_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_GetQuery_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.GetQuery(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.GetQuery(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1046,9 +1229,19 @@ func local_request_ABitOfEverythingService_GetQuery_0(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "uuid", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_GetQuery_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_GetQuery_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.GetQuery(ctx, &protoReq)
 	return msg, metadata, err
@@ -1193,7 +1386,7 @@ func request_ABitOfEverythingService_GetRepeatedQuery_0(ctx context.Context, mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "path_repeated_enum_value")
 	}
 
-	es, err = runtime.EnumSlice(val, ",", NumericEnum_value)
+	es, err = runtime.EnumSliceContext(req.Context(), val, ",", NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_repeated_enum_value", err)
@@ -1249,7 +1442,16 @@ func request_ABitOfEverythingService_GetRepeatedQuery_0(ctx context.Context, mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_repeated_sint64_value", err)
 	}
 
-	msg, err := client.GetRepeatedQuery(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.GetRepeatedQuery(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1392,7 +1594,7 @@ func local_request_ABitOfEverythingService_GetRepeatedQuery_0(ctx context.Contex
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "missing parameter %s", "path_repeated_enum_value")
 	}
 
-	es, err = runtime.EnumSlice(val, ",", NumericEnum_value)
+	es, err = runtime.EnumSliceContext(req.Context(), val, ",", NumericEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_repeated_enum_value", err)
@@ -1448,6 +1650,13 @@ func local_request_ABitOfEverythingService_GetRepeatedQuery_0(ctx context.Contex
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "path_repeated_sint64_value", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.GetRepeatedQuery(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1475,7 +1684,16 @@ func request_ABitOfEverythingService_Echo_0(ctx context.Context, marshaler runti
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "value", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1502,6 +1720,13 @@ func local_request_ABitOfEverythingService_Echo_0(ctx context.Context, marshaler
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "value", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1518,8 +1743,20 @@ func request_ABitOfEverythingService_Echo_1(ctx context.Context, marshaler runti
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Value); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "value"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1535,6 +1772,16 @@ func local_request_ABitOfEverythingService_Echo_1(ctx context.Context, marshaler
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Value); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "value"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
@@ -1552,11 +1799,23 @@ func request_ABitOfEverythingService_Echo_2(ctx context.Context, marshaler runti
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_Echo_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_Echo_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1565,9 +1824,19 @@ func local_request_ABitOfEverythingService_Echo_2(ctx context.Context, marshaler
 	var protoReq sub.StringMessage
 	var metadata runtime.ServerMetadata
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_Echo_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_Echo_2); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
@@ -1604,7 +1873,16 @@ func request_ABitOfEverythingService_DeepPathEcho_0(ctx context.Context, marshal
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "single_nested.name", err)
 	}
 
-	msg, err := client.DeepPathEcho(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.DeepPathEcho(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1639,6 +1917,13 @@ func local_request_ABitOfEverythingService_DeepPathEcho_0(ctx context.Context, m
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "single_nested.name", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.DeepPathEcho(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1648,7 +1933,16 @@ func request_ABitOfEverythingService_Timeout_0(ctx context.Context, marshaler ru
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
-	msg, err := client.Timeout(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Timeout(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1657,6 +1951,13 @@ func local_request_ABitOfEverythingService_Timeout_0(ctx context.Context, marsha
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Timeout(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1666,7 +1967,16 @@ func request_ABitOfEverythingService_ErrorWithDetails_0(ctx context.Context, mar
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
-	msg, err := client.ErrorWithDetails(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.ErrorWithDetails(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1675,6 +1985,13 @@ func local_request_ABitOfEverythingService_ErrorWithDetails_0(ctx context.Contex
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.ErrorWithDetails(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1691,6 +2008,9 @@ func request_ABitOfEverythingService_GetMessageWithBody_0(ctx context.Context, m
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Data); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "data"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1710,7 +2030,16 @@ func request_ABitOfEverythingService_GetMessageWithBody_0(ctx context.Context, m
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "id", err)
 	}
 
-	msg, err := client.GetMessageWithBody(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.GetMessageWithBody(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1726,6 +2055,9 @@ func local_request_ABitOfEverythingService_GetMessageWithBody_0(ctx context.Cont
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.Data); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "data"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1745,6 +2077,13 @@ func local_request_ABitOfEverythingService_GetMessageWithBody_0(ctx context.Cont
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "id", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.GetMessageWithBody(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1780,7 +2119,16 @@ func request_ABitOfEverythingService_PostWithEmptyBody_0(ctx context.Context, ma
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "name", err)
 	}
 
-	msg, err := client.PostWithEmptyBody(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.PostWithEmptyBody(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1815,6 +2163,13 @@ func local_request_ABitOfEverythingService_PostWithEmptyBody_0(ctx context.Conte
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "name", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.PostWithEmptyBody(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1849,11 +2204,23 @@ func request_ABitOfEverythingService_CheckGetQueryParams_0(ctx context.Context,
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_CheckGetQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_CheckGetQueryParams_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.CheckGetQueryParams(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.CheckGetQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1880,10 +2247,20 @@ func local_request_ABitOfEverythingService_CheckGetQueryParams_0(ctx context.Con
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "single_nested.name", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckGetQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckGetQueryParams_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.CheckGetQueryParams(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1912,7 +2289,7 @@ func request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx context
 
 	err = runtime.PopulateFieldFromPath(&protoReq, "single_nested.ok", val)
 
-	e, err = runtime.Enum(val, ABitOfEverything_Nested_DeepEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, ABitOfEverything_Nested_DeepEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "single_nested.ok", err)
@@ -1923,11 +2300,23 @@ func request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx context
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.CheckNestedEnumGetQueryParams(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.CheckNestedEnumGetQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -1951,7 +2340,7 @@ func local_request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx c
 
 	err = runtime.PopulateFieldFromPath(&protoReq, "single_nested.ok", val)
 
-	e, err = runtime.Enum(val, ABitOfEverything_Nested_DeepEnum_value)
+	e, err = runtime.EnumContext(req.Context(), val, ABitOfEverything_Nested_DeepEnum_value)
 
 	if err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "single_nested.ok", err)
@@ -1959,10 +2348,20 @@ func local_request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx c
 
 	protoReq.SingleNested.Ok = ABitOfEverything_Nested_DeepEnum(e)
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.CheckNestedEnumGetQueryParams(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -1983,6 +2382,9 @@ func request_ABitOfEverythingService_CheckPostQueryParams_0(ctx context.Context,
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.SingleNested); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "single_nested"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -2005,11 +2407,23 @@ func request_ABitOfEverythingService_CheckPostQueryParams_0(ctx context.Context,
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_ABitOfEverythingService_CheckPostQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_ABitOfEverythingService_CheckPostQueryParams_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.CheckPostQueryParams(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.CheckPostQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -2025,6 +2439,9 @@ func local_request_ABitOfEverythingService_CheckPostQueryParams_0(ctx context.Co
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.SingleNested); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "single_nested"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -2044,9 +2461,19 @@ func local_request_ABitOfEverythingService_CheckPostQueryParams_0(ctx context.Co
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "string_value", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckPostQueryParams_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_ABitOfEverythingService_CheckPostQueryParams_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.CheckPostQueryParams(ctx, &protoReq)
 	return msg, metadata, err
@@ -2057,7 +2484,16 @@ func request_CamelCaseServiceName_Empty_0(ctx context.Context, marshaler runtime
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
-	msg, err := client.Empty(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Empty(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -2066,6 +2502,13 @@ func local_request_CamelCaseServiceName_Empty_0(ctx context.Context, marshaler r
 	var protoReq empty.Empty
 	var metadata runtime.ServerMetadata
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Empty(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -2076,7 +2519,7 @@ func local_request_CamelCaseServiceName_Empty_0(ctx context.Context, marshaler r
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux, server ABitOfEverythingServiceServer) error {
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_Create_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Create"}, pattern_ABitOfEverythingService_Create_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2085,18 +2528,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Create_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Create_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Create_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Create_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_CreateBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CreateBody"}, pattern_ABitOfEverythingService_CreateBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2105,18 +2553,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_CreateBody_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_CreateBody_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CreateBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_CreateBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Lookup_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Lookup"}, pattern_ABitOfEverythingService_Lookup_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2125,18 +2578,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Lookup_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Lookup_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Lookup_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Lookup_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PUT", pattern_ABitOfEverythingService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PUT", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Update"}, pattern_ABitOfEverythingService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2145,18 +2603,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Update_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Update_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PUT", pattern_ABitOfEverythingService_UpdateV2_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PUT", Body: "abe", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2165,18 +2628,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_UpdateV2_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_UpdateV2_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_UpdateV2_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PATCH", pattern_ABitOfEverythingService_UpdateV2_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "abe", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2185,18 +2653,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_UpdateV2_1(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_UpdateV2_1(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_UpdateV2_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PATCH", pattern_ABitOfEverythingService_UpdateV2_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2205,18 +2678,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_UpdateV2_2(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_UpdateV2_2(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_UpdateV2_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("DELETE", pattern_ABitOfEverythingService_Delete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "DELETE", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Delete"}, pattern_ABitOfEverythingService_Delete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2225,18 +2703,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Delete_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
-		if err != nil {
-			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
-		}
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Delete_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
 
-		forward_ABitOfEverythingService_Delete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+			forward_ABitOfEverythingService_Delete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 
+			return nil
+		})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+		}
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_GetQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetQuery"}, pattern_ABitOfEverythingService_GetQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2245,18 +2728,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_GetQuery_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_GetQuery_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_GetQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_GetRepeatedQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetRepeatedQuery"}, pattern_ABitOfEverythingService_GetRepeatedQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2265,18 +2753,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_GetRepeatedQuery_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_GetRepeatedQuery_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetRepeatedQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_GetRepeatedQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2285,18 +2778,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Echo_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Echo_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "value", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2305,18 +2803,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Echo_1(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Echo_1(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2325,18 +2828,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Echo_2(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Echo_2(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_DeepPathEcho_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/DeepPathEcho"}, pattern_ABitOfEverythingService_DeepPathEcho_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2345,18 +2853,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_DeepPathEcho_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_DeepPathEcho_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_DeepPathEcho_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_DeepPathEcho_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Timeout_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Timeout"}, pattern_ABitOfEverythingService_Timeout_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2365,18 +2878,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_Timeout_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_Timeout_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Timeout_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Timeout_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_ErrorWithDetails_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/ErrorWithDetails"}, pattern_ABitOfEverythingService_ErrorWithDetails_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2385,18 +2903,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_ErrorWithDetails_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_ErrorWithDetails_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_ErrorWithDetails_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_ErrorWithDetails_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_GetMessageWithBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "data", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetMessageWithBody"}, pattern_ABitOfEverythingService_GetMessageWithBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2405,18 +2928,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_GetMessageWithBody_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_GetMessageWithBody_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetMessageWithBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_GetMessageWithBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_PostWithEmptyBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/PostWithEmptyBody"}, pattern_ABitOfEverythingService_PostWithEmptyBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2425,18 +2953,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_PostWithEmptyBody_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_PostWithEmptyBody_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_PostWithEmptyBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_PostWithEmptyBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_CheckGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckGetQueryParams"}, pattern_ABitOfEverythingService_CheckGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2445,18 +2978,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_CheckGetQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_CheckGetQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_CheckGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckNestedEnumGetQueryParams"}, pattern_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2465,18 +3003,23 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_CheckPostQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "single_nested", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckPostQueryParams"}, pattern_ABitOfEverythingService_CheckPostQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2485,15 +3028,20 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ABitOfEverythingService_CheckPostQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ABitOfEverythingService_CheckPostQueryParams_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckPostQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_CheckPostQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
 	return nil
@@ -2504,7 +3052,7 @@ func RegisterABitOfEverythingServiceHandlerServer(ctx context.Context, mux *runt
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterCamelCaseServiceNameHandlerServer(ctx context.Context, mux *runtime.ServeMux, server CamelCaseServiceNameServer) error {
 
-	mux.Handle("GET", pattern_CamelCaseServiceName_Empty_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.camelCaseServiceName/Empty"}, pattern_CamelCaseServiceName_Empty_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2513,15 +3061,20 @@ func RegisterCamelCaseServiceNameHandlerServer(ctx context.Context, mux *runtime
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_CamelCaseServiceName_Empty_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_CamelCaseServiceName_Empty_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_CamelCaseServiceName_Empty_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_CamelCaseServiceName_Empty_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
 	return nil
@@ -2537,14 +3090,14 @@ func RegisterABitOfEverythingServiceHandlerFromEndpoint(ctx context.Context, mux
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -2565,7 +3118,7 @@ func RegisterABitOfEverythingServiceHandler(ctx context.Context, mux *runtime.Se
 // "ABitOfEverythingServiceClient" to call the correct interceptors.
 func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux, client ABitOfEverythingServiceClient) error {
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_Create_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Create"}, pattern_ABitOfEverythingService_Create_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2574,18 +3127,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Create_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Create_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Create_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Create_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_Create_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Create", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Create(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_CreateBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CreateBody"}, pattern_ABitOfEverythingService_CreateBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2594,18 +3166,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_CreateBody_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_CreateBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_CreateBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CreateBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/CreateBody", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
 
-		forward_ABitOfEverythingService_CreateBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.CreateBody(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Lookup_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Lookup"}, pattern_ABitOfEverythingService_Lookup_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2614,18 +3205,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Lookup_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Lookup_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Lookup_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Lookup_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Lookup", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq sub2.IdMessage
 
-		forward_ABitOfEverythingService_Lookup_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Lookup(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("PUT", pattern_ABitOfEverythingService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PUT", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Update"}, pattern_ABitOfEverythingService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2634,18 +3244,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Update", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
 
-		forward_ABitOfEverythingService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Update(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("PUT", pattern_ABitOfEverythingService_UpdateV2_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PUT", Body: "abe", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2654,18 +3283,26 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_UpdateV2_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_UpdateV2_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_UpdateV2_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_UpdateV2_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PATCH", pattern_ABitOfEverythingService_UpdateV2_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "abe", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2674,18 +3311,26 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_UpdateV2_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_UpdateV2_1(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_UpdateV2_1(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_UpdateV2_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PATCH", pattern_ABitOfEverythingService_UpdateV2_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2"}, pattern_ABitOfEverythingService_UpdateV2_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2694,18 +3339,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_UpdateV2_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_UpdateV2_2(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_UpdateV2_2(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_UpdateV2_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_UpdateV2_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/UpdateV2", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq UpdateV2Request
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.UpdateV2(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("DELETE", pattern_ABitOfEverythingService_Delete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "DELETE", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Delete"}, pattern_ABitOfEverythingService_Delete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2714,18 +3378,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Delete_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Delete_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Delete_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Delete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Delete", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq sub2.IdMessage
 
-		forward_ABitOfEverythingService_Delete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Delete(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_GetQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetQuery"}, pattern_ABitOfEverythingService_GetQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2734,18 +3417,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_GetQuery_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_GetQuery_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_GetQuery_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_GetQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetQuery", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetQuery(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_GetRepeatedQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetRepeatedQuery"}, pattern_ABitOfEverythingService_GetRepeatedQuery_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2754,18 +3456,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_GetRepeatedQuery_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_GetRepeatedQuery_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_GetRepeatedQuery_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetRepeatedQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_GetRepeatedQuery_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetRepeatedQuery", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverythingRepeated
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetRepeatedQuery(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2774,18 +3495,26 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "value", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2794,18 +3523,26 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ABitOfEverythingService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo"}, pattern_ABitOfEverythingService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2814,18 +3551,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Echo", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq sub.StringMessage
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_DeepPathEcho_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/DeepPathEcho"}, pattern_ABitOfEverythingService_DeepPathEcho_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2834,18 +3590,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_DeepPathEcho_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_DeepPathEcho_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_DeepPathEcho_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_DeepPathEcho_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/DeepPathEcho", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
 
-		forward_ABitOfEverythingService_DeepPathEcho_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.DeepPathEcho(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_Timeout_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/Timeout"}, pattern_ABitOfEverythingService_Timeout_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2854,18 +3629,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_Timeout_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_Timeout_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_Timeout_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_Timeout_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_Timeout_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/Timeout", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq empty.Empty
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Timeout(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_ErrorWithDetails_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/ErrorWithDetails"}, pattern_ABitOfEverythingService_ErrorWithDetails_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2874,18 +3668,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_ErrorWithDetails_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_ErrorWithDetails_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_ErrorWithDetails_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_ErrorWithDetails_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/ErrorWithDetails", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq empty.Empty
 
-		forward_ABitOfEverythingService_ErrorWithDetails_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ErrorWithDetails(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_GetMessageWithBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "data", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetMessageWithBody"}, pattern_ABitOfEverythingService_GetMessageWithBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2894,18 +3707,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_GetMessageWithBody_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_GetMessageWithBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_GetMessageWithBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_GetMessageWithBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/GetMessageWithBody", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq MessageWithBody
 
-		forward_ABitOfEverythingService_GetMessageWithBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetMessageWithBody(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_PostWithEmptyBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/PostWithEmptyBody"}, pattern_ABitOfEverythingService_PostWithEmptyBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2914,18 +3746,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_PostWithEmptyBody_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_PostWithEmptyBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_PostWithEmptyBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_PostWithEmptyBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_PostWithEmptyBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/PostWithEmptyBody", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq Body
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.PostWithEmptyBody(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_CheckGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckGetQueryParams"}, pattern_ABitOfEverythingService_CheckGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2934,18 +3785,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_CheckGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_CheckGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_CheckGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckGetQueryParams", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
 
-		forward_ABitOfEverythingService_CheckGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.CheckGetQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckNestedEnumGetQueryParams"}, pattern_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2954,18 +3824,37 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ABitOfEverythingService_CheckNestedEnumGetQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckNestedEnumGetQueryParams", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.CheckNestedEnumGetQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_ABitOfEverythingService_CheckPostQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "single_nested", RPCMethod: "/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckPostQueryParams"}, pattern_ABitOfEverythingService_CheckPostQueryParams_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2974,20 +3863,189 @@ func RegisterABitOfEverythingServiceHandlerClient(ctx context.Context, mux *runt
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ABitOfEverythingService_CheckPostQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ABitOfEverythingService_CheckPostQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ABitOfEverythingService_CheckPostQueryParams_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ABitOfEverythingService_CheckPostQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ABitOfEverythingService/CheckPostQueryParams", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ABitOfEverything
 
-		forward_ABitOfEverythingService_CheckPostQueryParams_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.CheckPostQueryParams(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterABitOfEverythingServiceHandlerFromMuxConn registers the http handlers for service ABitOfEverythingService to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterABitOfEverythingServiceHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterABitOfEverythingServiceHandlerClient(ctx, mux, &muxABitOfEverythingServiceHandlerClient{mux: mux})
+}
+
+// muxABitOfEverythingServiceHandlerClient is a ABitOfEverythingServiceClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxABitOfEverythingServiceHandlerClient struct {
+	ABitOfEverythingServiceClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Create(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Create(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) CreateBody(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).CreateBody(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Lookup(ctx context.Context, in *sub2.IdMessage, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Lookup(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Update(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Update(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) UpdateV2(ctx context.Context, in *UpdateV2Request, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).UpdateV2(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Delete(ctx context.Context, in *sub2.IdMessage, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Delete(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) GetQuery(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).GetQuery(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) GetRepeatedQuery(ctx context.Context, in *ABitOfEverythingRepeated, opts ...grpc.CallOption) (*ABitOfEverythingRepeated, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).GetRepeatedQuery(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Echo(ctx context.Context, in *sub.StringMessage, opts ...grpc.CallOption) (*sub.StringMessage, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Echo(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) DeepPathEcho(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).DeepPathEcho(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) Timeout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).Timeout(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) ErrorWithDetails(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).ErrorWithDetails(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) GetMessageWithBody(ctx context.Context, in *MessageWithBody, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).GetMessageWithBody(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) PostWithEmptyBody(ctx context.Context, in *Body, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).PostWithEmptyBody(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) CheckGetQueryParams(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).CheckGetQueryParams(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) CheckNestedEnumGetQueryParams(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).CheckNestedEnumGetQueryParams(ctx, in, opts...)
+}
+
+func (c *muxABitOfEverythingServiceHandlerClient) CheckPostQueryParams(ctx context.Context, in *ABitOfEverything, opts ...grpc.CallOption) (*ABitOfEverything, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewABitOfEverythingServiceClient(conn).CheckPostQueryParams(ctx, in, opts...)
+}
+
 var (
 	pattern_ABitOfEverythingService_Create_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2, 1, 0, 4, 1, 5, 3, 1, 0, 4, 1, 5, 4, 1, 0, 4, 1, 5, 5, 2, 6, 1, 0, 4, 1, 5, 7, 1, 0, 4, 1, 5, 8, 1, 0, 4, 1, 5, 9, 1, 0, 4, 1, 5, 10, 1, 0, 4, 1, 5, 11, 2, 12, 1, 0, 4, 2, 5, 13, 1, 0, 4, 1, 5, 14, 1, 0, 4, 1, 5, 15, 1, 0, 4, 1, 5, 16, 1, 0, 4, 1, 5, 17, 1, 0, 4, 1, 5, 18, 1, 0, 4, 1, 5, 19, 1, 0, 4, 1, 5, 20, 1, 0, 4, 1, 5, 21, 1, 0, 4, 1, 5, 22, 1, 0, 4, 1, 5, 23}, []string{"v1", "example", "a_bit_of_everything", "float_value", "double_value", "int64_value", "separator", "uint64_value", "int32_value", "fixed64_value", "fixed32_value", "bool_value", "strprefix", "string_value", "uint32_value", "sfixed32_value", "sfixed64_value", "sint32_value", "sint64_value", "nonConventionalNameValue", "enum_value", "path_enum_value", "nested_path_enum_value", "enum_value_annotation"}, "", runtime.AssumeColonVerbOpt(true)))
 
@@ -3086,14 +4144,14 @@ func RegisterCamelCaseServiceNameHandlerFromEndpoint(ctx context.Context, mux *r
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -3114,7 +4172,7 @@ func RegisterCamelCaseServiceNameHandler(ctx context.Context, mux *runtime.Serve
 // "CamelCaseServiceNameClient" to call the correct interceptors.
 func RegisterCamelCaseServiceNameHandlerClient(ctx context.Context, mux *runtime.ServeMux, client CamelCaseServiceNameClient) error {
 
-	mux.Handle("GET", pattern_CamelCaseServiceName_Empty_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.camelCaseServiceName/Empty"}, pattern_CamelCaseServiceName_Empty_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -3123,20 +4181,61 @@ func RegisterCamelCaseServiceNameHandlerClient(ctx context.Context, mux *runtime
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_CamelCaseServiceName_Empty_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_CamelCaseServiceName_Empty_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_CamelCaseServiceName_Empty_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_CamelCaseServiceName_Empty_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_CamelCaseServiceName_Empty_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.camelCaseServiceName/Empty", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq empty.Empty
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Empty(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterCamelCaseServiceNameHandlerFromMuxConn registers the http handlers for service CamelCaseServiceName to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterCamelCaseServiceNameHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterCamelCaseServiceNameHandlerClient(ctx, mux, &muxCamelCaseServiceNameHandlerClient{mux: mux})
+}
+
+// muxCamelCaseServiceNameHandlerClient is a CamelCaseServiceNameClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxCamelCaseServiceNameHandlerClient struct {
+	CamelCaseServiceNameClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxCamelCaseServiceNameHandlerClient) Empty(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewCamelCaseServiceNameClient(conn).Empty(ctx, in, opts...)
+}
+
 var (
 	pattern_CamelCaseServiceName_Empty_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2}, []string{"v2", "example", "empty"}, "", runtime.AssumeColonVerbOpt(true)))
 )
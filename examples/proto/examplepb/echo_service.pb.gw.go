@@ -19,7 +19,6 @@ import (
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -60,11 +59,23 @@ func request_EchoService_Echo_0(ctx context.Context, marshaler runtime.Marshaler
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_Echo_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_Echo_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -91,9 +102,19 @@ func local_request_EchoService_Echo_0(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "id", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_Echo_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_Echo_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
@@ -140,11 +161,23 @@ func request_EchoService_Echo_1(ctx context.Context, marshaler runtime.Marshaler
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_Echo_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_Echo_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -182,10 +215,20 @@ func local_request_EchoService_Echo_1(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "num", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_Echo_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_Echo_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -247,11 +290,23 @@ func request_EchoService_Echo_2(ctx context.Context, marshaler runtime.Marshaler
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_Echo_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_Echo_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -305,10 +360,20 @@ func local_request_EchoService_Echo_2(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "lang", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_Echo_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_Echo_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -370,11 +435,23 @@ func request_EchoService_Echo_3(ctx context.Context, marshaler runtime.Marshaler
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_Echo_3); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_Echo_3); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -428,10 +505,20 @@ func local_request_EchoService_Echo_3(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "status.note", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_Echo_3); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_Echo_3); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -466,11 +553,23 @@ func request_EchoService_Echo_4(ctx context.Context, marshaler runtime.Marshaler
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_Echo_4); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_Echo_4); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Echo(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -497,9 +596,19 @@ func local_request_EchoService_Echo_4(ctx context.Context, marshaler runtime.Mar
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "no.note", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_Echo_4); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_Echo_4); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.Echo(ctx, &protoReq)
 	return msg, metadata, err
@@ -518,7 +627,16 @@ func request_EchoService_EchoBody_0(ctx context.Context, marshaler runtime.Marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.EchoBody(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.EchoBody(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -535,6 +653,13 @@ func local_request_EchoService_EchoBody_0(ctx context.Context, marshaler runtime
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.EchoBody(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -551,11 +676,23 @@ func request_EchoService_EchoDelete_0(ctx context.Context, marshaler runtime.Mar
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_EchoService_EchoDelete_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_EchoService_EchoDelete_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.EchoDelete(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.EchoDelete(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -564,10 +701,20 @@ func local_request_EchoService_EchoDelete_0(ctx context.Context, marshaler runti
 	var protoReq SimpleMessage
 	var metadata runtime.ServerMetadata
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_EchoService_EchoDelete_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_EchoService_EchoDelete_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.EchoDelete(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -578,7 +725,7 @@ func local_request_EchoService_EchoDelete_0(ctx context.Context, marshaler runti
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux, server EchoServiceServer) error {
 
-	mux.Handle("POST", pattern_EchoService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -587,18 +734,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_Echo_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_Echo_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -607,18 +759,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_Echo_1(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_Echo_1(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -627,18 +784,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_Echo_2(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_Echo_2(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -647,18 +809,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_Echo_3(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_Echo_3(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -667,18 +834,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_Echo_4(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_Echo_4(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_EchoService_EchoBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/EchoBody"}, pattern_EchoService_EchoBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -687,18 +859,23 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_EchoBody_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_EchoBody_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_EchoBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_EchoBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("DELETE", pattern_EchoService_EchoDelete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "DELETE", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/EchoDelete"}, pattern_EchoService_EchoDelete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -707,15 +884,20 @@ func RegisterEchoServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_EchoService_EchoDelete_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_EchoService_EchoDelete_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_EchoDelete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_EchoDelete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
 	return nil
@@ -731,14 +913,14 @@ func RegisterEchoServiceHandlerFromEndpoint(ctx context.Context, mux *runtime.Se
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -759,7 +941,7 @@ func RegisterEchoServiceHandler(ctx context.Context, mux *runtime.ServeMux, conn
 // "EchoServiceClient" to call the correct interceptors.
 func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux, client EchoServiceClient) error {
 
-	mux.Handle("POST", pattern_EchoService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -768,18 +950,26 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_Echo_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -788,18 +978,26 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_Echo_1(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -808,18 +1006,26 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_Echo_2(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -828,18 +1034,26 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_Echo_3(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_Echo_3(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_Echo_3(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_EchoService_Echo_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_EchoService_Echo_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/Echo"}, pattern_EchoService_Echo_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -848,18 +1062,37 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_Echo_4(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_Echo_4(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_Echo_4(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_Echo_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.EchoService/Echo", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq SimpleMessage
 
-		forward_EchoService_Echo_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Echo(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_EchoService_EchoBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/EchoBody"}, pattern_EchoService_EchoBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -868,18 +1101,37 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_EchoBody_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_EchoBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_EchoBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_EchoBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_EchoService_EchoBody_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.EchoService/EchoBody", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq SimpleMessage
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.EchoBody(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("DELETE", pattern_EchoService_EchoDelete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "DELETE", RPCMethod: "/grpc.gateway.examples.examplepb.EchoService/EchoDelete"}, pattern_EchoService_EchoDelete_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -888,20 +1140,77 @@ func RegisterEchoServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_EchoService_EchoDelete_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_EchoService_EchoDelete_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_EchoService_EchoDelete_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_EchoService_EchoDelete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.EchoService/EchoDelete", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq SimpleMessage
 
-		forward_EchoService_EchoDelete_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.EchoDelete(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterEchoServiceHandlerFromMuxConn registers the http handlers for service EchoService to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterEchoServiceHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterEchoServiceHandlerClient(ctx, mux, &muxEchoServiceHandlerClient{mux: mux})
+}
+
+// muxEchoServiceHandlerClient is a EchoServiceClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxEchoServiceHandlerClient struct {
+	EchoServiceClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxEchoServiceHandlerClient) Echo(ctx context.Context, in *SimpleMessage, opts ...grpc.CallOption) (*SimpleMessage, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewEchoServiceClient(conn).Echo(ctx, in, opts...)
+}
+
+func (c *muxEchoServiceHandlerClient) EchoBody(ctx context.Context, in *SimpleMessage, opts ...grpc.CallOption) (*SimpleMessage, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewEchoServiceClient(conn).EchoBody(ctx, in, opts...)
+}
+
+func (c *muxEchoServiceHandlerClient) EchoDelete(ctx context.Context, in *SimpleMessage, opts ...grpc.CallOption) (*SimpleMessage, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewEchoServiceClient(conn).EchoDelete(ctx, in, opts...)
+}
+
 var (
 	pattern_EchoService_Echo_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2, 1, 0, 4, 1, 5, 3}, []string{"v1", "example", "echo", "id"}, "", runtime.AssumeColonVerbOpt(true)))
 
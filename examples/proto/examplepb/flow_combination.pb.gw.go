@@ -19,7 +19,6 @@ import (
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -35,7 +34,16 @@ func request_FlowCombination_RpcEmptyRpc_0(ctx context.Context, marshaler runtim
 	var protoReq EmptyProto
 	var metadata runtime.ServerMetadata
 
-	msg, err := client.RpcEmptyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcEmptyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -44,6 +52,13 @@ func local_request_FlowCombination_RpcEmptyRpc_0(ctx context.Context, marshaler
 	var protoReq EmptyProto
 	var metadata runtime.ServerMetadata
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcEmptyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -53,7 +68,14 @@ func request_FlowCombination_RpcEmptyStream_0(ctx context.Context, marshaler run
 	var protoReq EmptyProto
 	var metadata runtime.ServerMetadata
 
-	stream, err := client.RpcEmptyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcEmptyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -68,9 +90,9 @@ func request_FlowCombination_RpcEmptyStream_0(ctx context.Context, marshaler run
 
 func request_FlowCombination_StreamEmptyRpc_0(ctx context.Context, marshaler runtime.Marshaler, client FlowCombinationClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
 	var metadata runtime.ServerMetadata
-	stream, err := client.StreamEmptyRpc(ctx)
+	stream, err := client.StreamEmptyRpc(ctx, runtime.CallOptions(ctx)...)
 	if err != nil {
-		grpclog.Infof("Failed to start streaming: %v", err)
+		runtime.LoggerFromContext(ctx).Infof("Failed to start streaming: %v", err)
 		return nil, metadata, err
 	}
 	dec := marshaler.NewDecoder(req.Body)
@@ -81,25 +103,28 @@ func request_FlowCombination_StreamEmptyRpc_0(ctx context.Context, marshaler run
 			break
 		}
 		if err != nil {
-			grpclog.Infof("Failed to decode request: %v", err)
+			runtime.LoggerFromContext(ctx).Infof("Failed to decode request: %v", err)
 			return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 		}
 		if err = stream.Send(&protoReq); err != nil {
 			if err == io.EOF {
+				// The server has already terminated the stream. Stop reading
+				// the request body and surface the server's status below.
 				break
 			}
-			grpclog.Infof("Failed to send request: %v", err)
+			runtime.LoggerFromContext(ctx).Infof("Failed to send request: %v", err)
 			return nil, metadata, err
 		}
 	}
 
 	if err := stream.CloseSend(); err != nil {
-		grpclog.Infof("Failed to terminate client stream: %v", err)
+		runtime.LoggerFromContext(ctx).Infof("Failed to terminate client stream: %v", err)
 		return nil, metadata, err
 	}
 	header, err := stream.Header()
 	if err != nil {
-		grpclog.Infof("Failed to get header from client: %v", err)
+		runtime.LoggerFromContext(ctx).Infof("Failed to get header from client: %v", err)
+		metadata.TrailerMD = stream.Trailer()
 		return nil, metadata, err
 	}
 	metadata.HeaderMD = header
@@ -112,9 +137,9 @@ func request_FlowCombination_StreamEmptyRpc_0(ctx context.Context, marshaler run
 
 func request_FlowCombination_StreamEmptyStream_0(ctx context.Context, marshaler runtime.Marshaler, client FlowCombinationClient, req *http.Request, pathParams map[string]string) (FlowCombination_StreamEmptyStreamClient, runtime.ServerMetadata, error) {
 	var metadata runtime.ServerMetadata
-	stream, err := client.StreamEmptyStream(ctx)
+	stream, err := client.StreamEmptyStream(ctx, runtime.CallOptions(ctx)...)
 	if err != nil {
-		grpclog.Infof("Failed to start streaming: %v", err)
+		runtime.LoggerFromContext(ctx).Infof("Failed to start streaming: %v", err)
 		return nil, metadata, err
 	}
 	dec := marshaler.NewDecoder(req.Body)
@@ -125,18 +150,18 @@ func request_FlowCombination_StreamEmptyStream_0(ctx context.Context, marshaler
 			return err
 		}
 		if err != nil {
-			grpclog.Infof("Failed to decode request: %v", err)
+			runtime.LoggerFromContext(ctx).Infof("Failed to decode request: %v", err)
 			return err
 		}
 		if err := stream.Send(&protoReq); err != nil {
-			grpclog.Infof("Failed to send request: %v", err)
+			runtime.LoggerFromContext(ctx).Infof("Failed to send request: %v", err)
 			return err
 		}
 		return nil
 	}
 	if err := handleSend(); err != nil {
 		if cerr := stream.CloseSend(); cerr != nil {
-			grpclog.Infof("Failed to terminate client stream: %v", cerr)
+			runtime.LoggerFromContext(ctx).Infof("Failed to terminate client stream: %v", cerr)
 		}
 		if err == io.EOF {
 			return stream, metadata, nil
@@ -150,12 +175,12 @@ func request_FlowCombination_StreamEmptyStream_0(ctx context.Context, marshaler
 			}
 		}
 		if err := stream.CloseSend(); err != nil {
-			grpclog.Infof("Failed to terminate client stream: %v", err)
+			runtime.LoggerFromContext(ctx).Infof("Failed to terminate client stream: %v", err)
 		}
 	}()
 	header, err := stream.Header()
 	if err != nil {
-		grpclog.Infof("Failed to get header from client: %v", err)
+		runtime.LoggerFromContext(ctx).Infof("Failed to get header from client: %v", err)
 		return nil, metadata, err
 	}
 	metadata.HeaderMD = header
@@ -174,7 +199,16 @@ func request_FlowCombination_RpcBodyRpc_0(ctx context.Context, marshaler runtime
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -191,6 +225,13 @@ func local_request_FlowCombination_RpcBodyRpc_0(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -240,7 +281,16 @@ func request_FlowCombination_RpcBodyRpc_1(ctx context.Context, marshaler runtime
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "c", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -289,6 +339,13 @@ func local_request_FlowCombination_RpcBodyRpc_1(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "c", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -305,11 +362,23 @@ func request_FlowCombination_RpcBodyRpc_2(ctx context.Context, marshaler runtime
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -318,10 +387,20 @@ func local_request_FlowCombination_RpcBodyRpc_2(ctx context.Context, marshaler r
 	var protoReq NonEmptyProto
 	var metadata runtime.ServerMetadata
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -338,6 +417,9 @@ func request_FlowCombination_RpcBodyRpc_3(ctx context.Context, marshaler runtime
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -368,7 +450,16 @@ func request_FlowCombination_RpcBodyRpc_3(ctx context.Context, marshaler runtime
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "b", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -384,6 +475,9 @@ func local_request_FlowCombination_RpcBodyRpc_3(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -414,6 +508,13 @@ func local_request_FlowCombination_RpcBodyRpc_3(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "b", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -434,15 +535,30 @@ func request_FlowCombination_RpcBodyRpc_4(ctx context.Context, marshaler runtime
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_4); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_4); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -458,10 +574,23 @@ func local_request_FlowCombination_RpcBodyRpc_4(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_4); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_4); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
@@ -483,6 +612,9 @@ func request_FlowCombination_RpcBodyRpc_5(ctx context.Context, marshaler runtime
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -505,11 +637,23 @@ func request_FlowCombination_RpcBodyRpc_5(ctx context.Context, marshaler runtime
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_5); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_5); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -525,6 +669,9 @@ func local_request_FlowCombination_RpcBodyRpc_5(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -544,10 +691,20 @@ func local_request_FlowCombination_RpcBodyRpc_5(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "a", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_5); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_5); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -582,11 +739,23 @@ func request_FlowCombination_RpcBodyRpc_6(ctx context.Context, marshaler runtime
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_6); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyRpc_6); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcBodyRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -613,10 +782,20 @@ func local_request_FlowCombination_RpcBodyRpc_6(ctx context.Context, marshaler r
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "a", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_6); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcBodyRpc_6); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcBodyRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -651,11 +830,23 @@ func request_FlowCombination_RpcPathSingleNestedRpc_0(ctx context.Context, marsh
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathSingleNestedRpc_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathSingleNestedRpc_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcPathSingleNestedRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcPathSingleNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -682,10 +873,20 @@ func local_request_FlowCombination_RpcPathSingleNestedRpc_0(ctx context.Context,
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "a.str", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcPathSingleNestedRpc_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcPathSingleNestedRpc_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcPathSingleNestedRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -706,6 +907,9 @@ func request_FlowCombination_RpcPathNestedRpc_0(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -739,11 +943,23 @@ func request_FlowCombination_RpcPathNestedRpc_0(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcPathNestedRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcPathNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -759,6 +975,9 @@ func local_request_FlowCombination_RpcPathNestedRpc_0(ctx context.Context, marsh
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -789,9 +1008,19 @@ func local_request_FlowCombination_RpcPathNestedRpc_0(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "b", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_0); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
 	msg, err := server.RpcPathNestedRpc(ctx, &protoReq)
 	return msg, metadata, err
@@ -827,11 +1056,23 @@ func request_FlowCombination_RpcPathNestedRpc_1(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.RpcPathNestedRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcPathNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -858,10 +1099,20 @@ func local_request_FlowCombination_RpcPathNestedRpc_1(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "a.str", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcPathNestedRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -882,6 +1133,9 @@ func request_FlowCombination_RpcPathNestedRpc_2(ctx context.Context, marshaler r
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -904,11 +1158,23 @@ func request_FlowCombination_RpcPathNestedRpc_2(ctx context.Context, marshaler r
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedRpc_2); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	msg, err := client.RpcPathNestedRpc(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.RpcPathNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -924,6 +1190,9 @@ func local_request_FlowCombination_RpcPathNestedRpc_2(ctx context.Context, marsh
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -943,10 +1212,20 @@ func local_request_FlowCombination_RpcPathNestedRpc_2(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "a.str", err)
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_FlowCombination_RpcPathNestedRpc_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.RpcPathNestedRpc(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -964,7 +1243,14 @@ func request_FlowCombination_RpcBodyStream_0(ctx context.Context, marshaler runt
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1021,7 +1307,14 @@ func request_FlowCombination_RpcBodyStream_1(ctx context.Context, marshaler runt
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "c", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1045,11 +1338,21 @@ func request_FlowCombination_RpcBodyStream_2(ctx context.Context, marshaler runt
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyStream_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyStream_2); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1073,6 +1376,9 @@ func request_FlowCombination_RpcBodyStream_3(ctx context.Context, marshaler runt
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1103,7 +1409,14 @@ func request_FlowCombination_RpcBodyStream_3(ctx context.Context, marshaler runt
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "b", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1131,15 +1444,28 @@ func request_FlowCombination_RpcBodyStream_4(ctx context.Context, marshaler runt
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyStream_4); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyStream_4); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1167,6 +1493,9 @@ func request_FlowCombination_RpcBodyStream_5(ctx context.Context, marshaler runt
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1189,11 +1518,21 @@ func request_FlowCombination_RpcBodyStream_5(ctx context.Context, marshaler runt
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyStream_5); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyStream_5); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1235,11 +1574,21 @@ func request_FlowCombination_RpcBodyStream_6(ctx context.Context, marshaler runt
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcBodyStream_6); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcBodyStream_6); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcBodyStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1281,11 +1630,21 @@ func request_FlowCombination_RpcPathSingleNestedStream_0(ctx context.Context, ma
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathSingleNestedStream_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathSingleNestedStream_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcPathSingleNestedStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcPathSingleNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1313,6 +1672,9 @@ func request_FlowCombination_RpcPathNestedStream_0(ctx context.Context, marshale
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1346,11 +1708,21 @@ func request_FlowCombination_RpcPathNestedStream_0(ctx context.Context, marshale
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcPathNestedStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcPathNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1392,11 +1764,21 @@ func request_FlowCombination_RpcPathNestedStream_1(ctx context.Context, marshale
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_1); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_1); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	stream, err := client.RpcPathNestedStream(ctx, &protoReq)
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	stream, err := client.RpcPathNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1424,6 +1806,9 @@ func request_FlowCombination_RpcPathNestedStream_2(ctx context.Context, marshale
 	if err := marshaler.NewDecoder(newReader()).Decode(&protoReq.C); err != nil && err != io.EOF {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "c"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	var (
 		val string
@@ -1446,11 +1831,21 @@ func request_FlowCombination_RpcPathNestedStream_2(ctx context.Context, marshale
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_2); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_FlowCombination_RpcPathNestedStream_2); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
 
-	stream, err := client.RpcPathNestedStream(ctx, &protoReq)
+	stream, err := client.RpcPathNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
 	if err != nil {
 		return nil, metadata, err
 	}
@@ -1468,7 +1863,7 @@ func request_FlowCombination_RpcPathNestedStream_2(ctx context.Context, marshale
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.ServeMux, server FlowCombinationServer) error {
 
-	mux.Handle("POST", pattern_FlowCombination_RpcEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyRpc"}, pattern_FlowCombination_RpcEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1477,39 +1872,44 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcEmptyRpc_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcEmptyRpc_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyStream"}, pattern_FlowCombination_RpcEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_StreamEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/StreamEmptyRpc"}, pattern_FlowCombination_StreamEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_StreamEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/StreamEmptyStream"}, pattern_FlowCombination_StreamEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1518,18 +1918,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1538,18 +1943,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_1(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_1(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1558,18 +1968,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_2(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_2(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1578,18 +1993,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_3(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_3(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1598,18 +2018,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_4(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_4(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1618,18 +2043,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_5(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_5(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_5(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_5(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1638,18 +2068,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcBodyRpc_6(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcBodyRpc_6(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_6(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_6(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathSingleNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedRpc"}, pattern_FlowCombination_RpcPathSingleNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1658,18 +2093,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcPathSingleNestedRpc_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcPathSingleNestedRpc_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathSingleNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathSingleNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1678,18 +2118,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1698,18 +2143,23 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_1(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_1(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1718,88 +2168,93 @@ func RegisterFlowCombinationHandlerServer(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_2(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_FlowCombination_RpcPathNestedRpc_2(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathSingleNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedStream"}, pattern_FlowCombination_RpcPathSingleNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 		return
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
 		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
 		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
@@ -1819,14 +2274,14 @@ func RegisterFlowCombinationHandlerFromEndpoint(ctx context.Context, mux *runtim
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -1847,7 +2302,7 @@ func RegisterFlowCombinationHandler(ctx context.Context, mux *runtime.ServeMux,
 // "FlowCombinationClient" to call the correct interceptors.
 func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.ServeMux, client FlowCombinationClient) error {
 
-	mux.Handle("POST", pattern_FlowCombination_RpcEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyRpc"}, pattern_FlowCombination_RpcEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1856,18 +2311,37 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcEmptyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcEmptyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcEmptyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyRpc", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq EmptyProto
 
-		forward_FlowCombination_RpcEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RpcEmptyRpc(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyStream"}, pattern_FlowCombination_RpcEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1876,18 +2350,38 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcEmptyStream_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcEmptyStream_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcEmptyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcEmptyStream", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq EmptyProto
 
-		forward_FlowCombination_RpcEmptyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebStream(ctx, mux, w, req, &protoReq, func(ctx context.Context) (grpc.ClientStream, func() (proto.Message, error), error) {
+			stream, err := client.RpcEmptyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stream, func() (proto.Message, error) { return stream.Recv() }, nil
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_StreamEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/StreamEmptyRpc"}, pattern_FlowCombination_StreamEmptyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1896,18 +2390,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_StreamEmptyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_StreamEmptyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_StreamEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_StreamEmptyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_StreamEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/StreamEmptyStream"}, pattern_FlowCombination_StreamEmptyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1916,18 +2415,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_StreamEmptyStream_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_StreamEmptyStream_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_StreamEmptyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_StreamEmptyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1936,18 +2440,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1956,18 +2468,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_1(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_1(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1976,18 +2496,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_2(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_2(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -1996,18 +2524,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_3(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_3(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_3(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_3(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2016,18 +2552,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_4(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_4(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_4(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_4(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2036,18 +2580,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_5(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_5(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_5(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_5(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyRpc_5(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyRpc_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc"}, pattern_FlowCombination_RpcBodyRpc_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2056,18 +2608,37 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyRpc_6(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyRpc_6(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcBodyRpc_6(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyRpc_6(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_FlowCombination_RpcBodyRpc_6(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyRpc", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NonEmptyProto
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RpcBodyRpc(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathSingleNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedRpc"}, pattern_FlowCombination_RpcPathSingleNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2076,18 +2647,37 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathSingleNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathSingleNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcPathSingleNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathSingleNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_FlowCombination_RpcPathSingleNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedRpc", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq SingleNestedProto
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RpcPathSingleNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2096,18 +2686,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcPathNestedRpc_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedRpc_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2116,18 +2714,26 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedRpc_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedRpc_1(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcPathNestedRpc_1(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedRpc_1(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc"}, pattern_FlowCombination_RpcPathNestedRpc_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2136,18 +2742,37 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedRpc_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedRpc_2(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_FlowCombination_RpcPathNestedRpc_2(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedRpc", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NestedProto
 
-		forward_FlowCombination_RpcPathNestedRpc_2(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RpcPathNestedRpc(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "*", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2156,18 +2781,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2176,18 +2806,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_1(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_1(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_1(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2196,18 +2831,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_2(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_2(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_2(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_3, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2216,18 +2856,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_3(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_3(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_3(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_3(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_4, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2236,18 +2881,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_4(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_4(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_4(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_4(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_5, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2256,18 +2906,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_5(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_5(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_5(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcBodyStream_5(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcBodyStream_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream"}, pattern_FlowCombination_RpcBodyStream_6, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2276,18 +2931,38 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcBodyStream_6(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcBodyStream_6(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcBodyStream_6(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcBodyStream", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NonEmptyProto
 
-		forward_FlowCombination_RpcBodyStream_6(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebStream(ctx, mux, w, req, &protoReq, func(ctx context.Context) (grpc.ClientStream, func() (proto.Message, error), error) {
+			stream, err := client.RpcBodyStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stream, func() (proto.Message, error) { return stream.Recv() }, nil
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathSingleNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedStream"}, pattern_FlowCombination_RpcPathSingleNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2296,18 +2971,38 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathSingleNestedStream_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathSingleNestedStream_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathSingleNestedStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_FlowCombination_RpcPathSingleNestedStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcPathSingleNestedStream", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq SingleNestedProto
+
+		runtime.ServeGRPCWebStream(ctx, mux, w, req, &protoReq, func(ctx context.Context) (grpc.ClientStream, func() (proto.Message, error), error) {
+			stream, err := client.RpcPathSingleNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stream, func() (proto.Message, error) { return stream.Recv() }, nil
+		})
 
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2316,18 +3011,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedStream_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedStream_0(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedStream_0(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_1, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2336,18 +3036,23 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedStream_1(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedStream_1(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedStream_1(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_FlowCombination_RpcPathNestedStream_1(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("POST", pattern_FlowCombination_RpcPathNestedStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "c", RPCMethod: "/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream"}, pattern_FlowCombination_RpcPathNestedStream_2, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -2356,20 +3061,134 @@ func RegisterFlowCombinationHandlerClient(ctx context.Context, mux *runtime.Serv
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_FlowCombination_RpcPathNestedStream_2(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_FlowCombination_RpcPathNestedStream_2(rctx, inboundMarshaler, client, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_FlowCombination_RpcPathNestedStream_2(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.FlowCombination/RpcPathNestedStream", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NestedProto
 
-		forward_FlowCombination_RpcPathNestedStream_2(ctx, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebStream(ctx, mux, w, req, &protoReq, func(ctx context.Context) (grpc.ClientStream, func() (proto.Message, error), error) {
+			stream, err := client.RpcPathNestedStream(ctx, &protoReq, runtime.CallOptions(ctx)...)
+			if err != nil {
+				return nil, nil, err
+			}
+			return stream, func() (proto.Message, error) { return stream.Recv() }, nil
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterFlowCombinationHandlerFromMuxConn registers the http handlers for service FlowCombination to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterFlowCombinationHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterFlowCombinationHandlerClient(ctx, mux, &muxFlowCombinationHandlerClient{mux: mux})
+}
+
+// muxFlowCombinationHandlerClient is a FlowCombinationClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxFlowCombinationHandlerClient struct {
+	FlowCombinationClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcEmptyRpc(ctx context.Context, in *EmptyProto, opts ...grpc.CallOption) (*EmptyProto, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcEmptyRpc(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcEmptyStream(ctx context.Context, in *EmptyProto, opts ...grpc.CallOption) (FlowCombination_RpcEmptyStreamClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcEmptyStream(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) StreamEmptyRpc(ctx context.Context, opts ...grpc.CallOption) (FlowCombination_StreamEmptyRpcClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).StreamEmptyRpc(ctx, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) StreamEmptyStream(ctx context.Context, opts ...grpc.CallOption) (FlowCombination_StreamEmptyStreamClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).StreamEmptyStream(ctx, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcBodyRpc(ctx context.Context, in *NonEmptyProto, opts ...grpc.CallOption) (*EmptyProto, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcBodyRpc(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcPathSingleNestedRpc(ctx context.Context, in *SingleNestedProto, opts ...grpc.CallOption) (*EmptyProto, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcPathSingleNestedRpc(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcPathNestedRpc(ctx context.Context, in *NestedProto, opts ...grpc.CallOption) (*EmptyProto, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcPathNestedRpc(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcBodyStream(ctx context.Context, in *NonEmptyProto, opts ...grpc.CallOption) (FlowCombination_RpcBodyStreamClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcBodyStream(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcPathSingleNestedStream(ctx context.Context, in *SingleNestedProto, opts ...grpc.CallOption) (FlowCombination_RpcPathSingleNestedStreamClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcPathSingleNestedStream(ctx, in, opts...)
+}
+
+func (c *muxFlowCombinationHandlerClient) RpcPathNestedStream(ctx context.Context, in *NestedProto, opts ...grpc.CallOption) (FlowCombination_RpcPathNestedStreamClient, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewFlowCombinationClient(conn).RpcPathNestedStream(ctx, in, opts...)
+}
+
 var (
 	pattern_FlowCombination_RpcEmptyRpc_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 0}, []string{"rpc", "empty"}, "", runtime.AssumeColonVerbOpt(true)))
 
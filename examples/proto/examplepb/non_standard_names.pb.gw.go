@@ -19,7 +19,6 @@ import (
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -55,14 +54,33 @@ func request_NonStandardService_Update_0(ctx context.Context, marshaler runtime.
 		}
 	}
 
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "body", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "body"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_NonStandardService_Update_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_NonStandardService_Update_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.Update(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.Update(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -87,10 +105,27 @@ func local_request_NonStandardService_Update_0(ctx context.Context, marshaler ru
 		}
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_NonStandardService_Update_0); err != nil {
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "body", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "body"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_NonStandardService_Update_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.Update(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -120,14 +155,33 @@ func request_NonStandardService_UpdateWithJSONNames_0(ctx context.Context, marsh
 		}
 	}
 
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "body", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "body"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	if err := req.ParseForm(); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
-	if err := runtime.PopulateQueryParameters(&protoReq, req.Form, filter_NonStandardService_UpdateWithJSONNames_0); err != nil {
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.Form, filter_NonStandardService_UpdateWithJSONNames_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	msg, err := client.UpdateWithJSONNames(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.UpdateWithJSONNames(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -152,10 +206,27 @@ func local_request_NonStandardService_UpdateWithJSONNames_0(ctx context.Context,
 		}
 	}
 
-	if err := runtime.PopulateQueryParameters(&protoReq, req.URL.Query(), filter_NonStandardService_UpdateWithJSONNames_0); err != nil {
+	if err := runtime.PopulateFieldMask(req.Context(), &protoReq, "body", newReader); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.PopulateBodyQueryParameters(req.Context(), &protoReq, req.URL.Query(), "body"); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.PopulateQueryParametersContext(req.Context(), &protoReq, req.URL.Query(), filter_NonStandardService_UpdateWithJSONNames_0); err != nil {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := runtime.ApplyQueryDefaults(req.Context(), &protoReq); err != nil {
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.UpdateWithJSONNames(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -166,7 +237,7 @@ func local_request_NonStandardService_UpdateWithJSONNames_0(ctx context.Context,
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterNonStandardServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux, server NonStandardServiceServer) error {
 
-	mux.Handle("PATCH", pattern_NonStandardService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "body", RPCMethod: "/grpc.gateway.examples.examplepb.NonStandardService/Update"}, pattern_NonStandardService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -175,18 +246,23 @@ func RegisterNonStandardServiceHandlerServer(ctx context.Context, mux *runtime.S
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_NonStandardService_Update_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_NonStandardService_Update_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_NonStandardService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_NonStandardService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("PATCH", pattern_NonStandardService_UpdateWithJSONNames_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "body", RPCMethod: "/grpc.gateway.examples.examplepb.NonStandardService/UpdateWithJSONNames"}, pattern_NonStandardService_UpdateWithJSONNames_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -195,15 +271,20 @@ func RegisterNonStandardServiceHandlerServer(ctx context.Context, mux *runtime.S
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_NonStandardService_UpdateWithJSONNames_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_NonStandardService_UpdateWithJSONNames_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_NonStandardService_UpdateWithJSONNames_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_NonStandardService_UpdateWithJSONNames_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
-
 	})
 
 	return nil
@@ -219,14 +300,14 @@ func RegisterNonStandardServiceHandlerFromEndpoint(ctx context.Context, mux *run
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -247,7 +328,7 @@ func RegisterNonStandardServiceHandler(ctx context.Context, mux *runtime.ServeMu
 // "NonStandardServiceClient" to call the correct interceptors.
 func RegisterNonStandardServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux, client NonStandardServiceClient) error {
 
-	mux.Handle("PATCH", pattern_NonStandardService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "body", RPCMethod: "/grpc.gateway.examples.examplepb.NonStandardService/Update"}, pattern_NonStandardService_Update_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -256,18 +337,37 @@ func RegisterNonStandardServiceHandlerClient(ctx context.Context, mux *runtime.S
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_NonStandardService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_NonStandardService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_NonStandardService_Update_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_NonStandardService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_NonStandardService_Update_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.NonStandardService/Update", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NonStandardUpdateRequest
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.Update(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("PATCH", pattern_NonStandardService_UpdateWithJSONNames_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "body", RPCMethod: "/grpc.gateway.examples.examplepb.NonStandardService/UpdateWithJSONNames"}, pattern_NonStandardService_UpdateWithJSONNames_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -276,20 +376,69 @@ func RegisterNonStandardServiceHandlerClient(ctx context.Context, mux *runtime.S
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_NonStandardService_UpdateWithJSONNames_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_NonStandardService_UpdateWithJSONNames_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_NonStandardService_UpdateWithJSONNames_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_NonStandardService_UpdateWithJSONNames_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_NonStandardService_UpdateWithJSONNames_0(ctx, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.NonStandardService/UpdateWithJSONNames", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq NonStandardWithJSONNamesUpdateRequest
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.UpdateWithJSONNames(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterNonStandardServiceHandlerFromMuxConn registers the http handlers for service NonStandardService to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterNonStandardServiceHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterNonStandardServiceHandlerClient(ctx, mux, &muxNonStandardServiceHandlerClient{mux: mux})
+}
+
+// muxNonStandardServiceHandlerClient is a NonStandardServiceClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxNonStandardServiceHandlerClient struct {
+	NonStandardServiceClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxNonStandardServiceHandlerClient) Update(ctx context.Context, in *NonStandardUpdateRequest, opts ...grpc.CallOption) (*NonStandardMessage, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewNonStandardServiceClient(conn).Update(ctx, in, opts...)
+}
+
+func (c *muxNonStandardServiceHandlerClient) UpdateWithJSONNames(ctx context.Context, in *NonStandardWithJSONNamesUpdateRequest, opts ...grpc.CallOption) (*NonStandardMessageWithJSONNames, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewNonStandardServiceClient(conn).UpdateWithJSONNames(ctx, in, opts...)
+}
+
 var (
 	pattern_NonStandardService_Update_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1, 2, 2, 2, 3}, []string{"v1", "example", "non_standard", "update"}, "", runtime.AssumeColonVerbOpt(true)))
 
@@ -19,7 +19,6 @@ import (
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -53,7 +52,16 @@ func request_ResponseBodyService_GetResponseBody_0(ctx context.Context, marshale
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
-	msg, err := client.GetResponseBody(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.GetResponseBody(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -80,6 +88,13 @@ func local_request_ResponseBodyService_GetResponseBody_0(ctx context.Context, ma
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.GetResponseBody(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -107,7 +122,16 @@ func request_ResponseBodyService_ListResponseBodies_0(ctx context.Context, marsh
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
-	msg, err := client.ListResponseBodies(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.ListResponseBodies(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -134,6 +158,13 @@ func local_request_ResponseBodyService_ListResponseBodies_0(ctx context.Context,
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.ListResponseBodies(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -161,7 +192,16 @@ func request_ResponseBodyService_ListResponseStrings_0(ctx context.Context, mars
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
-	msg, err := client.ListResponseStrings(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
+	msg, err := runtime.WrapCall(ctx, req, func() (proto.Message, error) {
+		return client.ListResponseStrings(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))...)
+	})
 	return msg, metadata, err
 
 }
@@ -188,6 +228,13 @@ func local_request_ResponseBodyService_ListResponseStrings_0(ctx context.Context
 		return nil, metadata, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", "data", err)
 	}
 
+	if err := runtime.TransformRequest(ctx, req, &protoReq); err != nil {
+		return nil, metadata, err
+	}
+	if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {
+		return nil, metadata, err
+	}
+
 	msg, err := server.ListResponseStrings(ctx, &protoReq)
 	return msg, metadata, err
 
@@ -198,7 +245,7 @@ func local_request_ResponseBodyService_ListResponseStrings_0(ctx context.Context
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
 func RegisterResponseBodyServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux, server ResponseBodyServiceServer) error {
 
-	mux.Handle("GET", pattern_ResponseBodyService_GetResponseBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/GetResponseBody"}, pattern_ResponseBodyService_GetResponseBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -207,18 +254,23 @@ func RegisterResponseBodyServiceHandlerServer(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ResponseBodyService_GetResponseBody_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ResponseBodyService_GetResponseBody_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_GetResponseBody_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_GetResponseBody_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ResponseBodyService_GetResponseBody_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_GetResponseBody_0{resp}, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ResponseBodyService_ListResponseBodies_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseBodies"}, pattern_ResponseBodyService_ListResponseBodies_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -227,18 +279,23 @@ func RegisterResponseBodyServiceHandlerServer(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ResponseBodyService_ListResponseBodies_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ResponseBodyService_ListResponseBodies_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_ListResponseBodies_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseBodies_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ResponseBodyService_ListResponseBodies_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseBodies_0{resp}, mux.GetForwardResponseOptions()...)
-
 	})
 
-	mux.Handle("GET", pattern_ResponseBodyService_ListResponseStrings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseStrings"}, pattern_ResponseBodyService_ListResponseStrings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -247,15 +304,20 @@ func RegisterResponseBodyServiceHandlerServer(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_ResponseBodyService_ListResponseStrings_0(rctx, inboundMarshaler, server, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := local_request_ResponseBodyService_ListResponseStrings_0(rctx, inboundMarshaler, server, req, pathParams)
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_ListResponseStrings_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseStrings_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
-
-		forward_ResponseBodyService_ListResponseStrings_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseStrings_0{resp}, mux.GetForwardResponseOptions()...)
-
 	})
 
 	return nil
@@ -271,14 +333,14 @@ func RegisterResponseBodyServiceHandlerFromEndpoint(ctx context.Context, mux *ru
 	defer func() {
 		if err != nil {
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 			return
 		}
 		go func() {
 			<-ctx.Done()
 			if cerr := conn.Close(); cerr != nil {
-				grpclog.Infof("Failed to close conn to %s: %v", endpoint, cerr)
+				mux.Logger().Infof("Failed to close conn to %s: %v", endpoint, cerr)
 			}
 		}()
 	}()
@@ -299,7 +361,7 @@ func RegisterResponseBodyServiceHandler(ctx context.Context, mux *runtime.ServeM
 // "ResponseBodyServiceClient" to call the correct interceptors.
 func RegisterResponseBodyServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux, client ResponseBodyServiceClient) error {
 
-	mux.Handle("GET", pattern_ResponseBodyService_GetResponseBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/GetResponseBody"}, pattern_ResponseBodyService_GetResponseBody_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -308,18 +370,37 @@ func RegisterResponseBodyServiceHandlerClient(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ResponseBodyService_GetResponseBody_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ResponseBodyService_GetResponseBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ResponseBodyService_GetResponseBody_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_GetResponseBody_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_GetResponseBody_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ResponseBodyService_GetResponseBody_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_GetResponseBody_0{resp}, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ResponseBodyService/GetResponseBody", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ResponseBodyIn
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetResponseBody(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ResponseBodyService_ListResponseBodies_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseBodies"}, pattern_ResponseBodyService_ListResponseBodies_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -328,18 +409,37 @@ func RegisterResponseBodyServiceHandlerClient(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ResponseBodyService_ListResponseBodies_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ResponseBodyService_ListResponseBodies_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ResponseBodyService_ListResponseBodies_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_ListResponseBodies_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseBodies_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
+
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseBodies", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ResponseBodyIn
 
-		forward_ResponseBodyService_ListResponseBodies_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseBodies_0{resp}, mux.GetForwardResponseOptions()...)
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ListResponseBodies(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
-	mux.Handle("GET", pattern_ResponseBodyService_ListResponseStrings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+	mux.HandleRoute(runtime.RouteInfo{Method: "GET", RPCMethod: "/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseStrings"}, pattern_ResponseBodyService_ListResponseStrings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
@@ -348,20 +448,77 @@ func RegisterResponseBodyServiceHandlerClient(ctx context.Context, mux *runtime.
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_ResponseBodyService_ListResponseStrings_0(rctx, inboundMarshaler, client, req, pathParams)
-		ctx = runtime.NewServerMetadataContext(ctx, md)
+		err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			resp, md, err := request_ResponseBodyService_ListResponseStrings_0(rctx, inboundMarshaler, client, req, pathParams)
+			for attempt := 1; runtime.ShouldRetry(rctx, req, attempt, err); attempt++ {
+				resp, md, err = request_ResponseBodyService_ListResponseStrings_0(rctx, inboundMarshaler, client, req, pathParams)
+			}
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				return err
+			}
+
+			forward_ResponseBodyService_ListResponseStrings_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseStrings_0{resp}, mux.GetForwardResponseOptions()...)
+
+			return nil
+		})
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
-			return
 		}
+	})
 
-		forward_ResponseBodyService_ListResponseStrings_0(ctx, mux, outboundMarshaler, w, req, response_ResponseBodyService_ListResponseStrings_0{resp}, mux.GetForwardResponseOptions()...)
+	mux.HandleGRPCWeb("/grpc.gateway.examples.examplepb.ResponseBodyService/ListResponseStrings", func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var protoReq ResponseBodyIn
+
+		runtime.ServeGRPCWebUnary(ctx, mux, w, req, &protoReq, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ListResponseStrings(ctx, &protoReq, runtime.CallOptions(ctx, opts...)...)
+		})
 
 	})
 
 	return nil
 }
 
+// RegisterResponseBodyServiceHandlerFromMuxConn registers the http handlers for service ResponseBodyService to "mux".
+// Each request is forwarded over the connection most recently set with mux.SetClientConn when the request is received,
+// so that the connection can be replaced without registering the handlers again.
+func RegisterResponseBodyServiceHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {
+	return RegisterResponseBodyServiceHandlerClient(ctx, mux, &muxResponseBodyServiceHandlerClient{mux: mux})
+}
+
+// muxResponseBodyServiceHandlerClient is a ResponseBodyServiceClient which calls over the connection set on "mux".
+// Only the methods with http bindings are implemented, since the handlers call no others.
+type muxResponseBodyServiceHandlerClient struct {
+	ResponseBodyServiceClient
+	mux *runtime.ServeMux
+}
+
+func (c *muxResponseBodyServiceHandlerClient) GetResponseBody(ctx context.Context, in *ResponseBodyIn, opts ...grpc.CallOption) (*ResponseBodyOut, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewResponseBodyServiceClient(conn).GetResponseBody(ctx, in, opts...)
+}
+
+func (c *muxResponseBodyServiceHandlerClient) ListResponseBodies(ctx context.Context, in *ResponseBodyIn, opts ...grpc.CallOption) (*RepeatedResponseBodyOut, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewResponseBodyServiceClient(conn).ListResponseBodies(ctx, in, opts...)
+}
+
+func (c *muxResponseBodyServiceHandlerClient) ListResponseStrings(ctx context.Context, in *ResponseBodyIn, opts ...grpc.CallOption) (*RepeatedResponseStrings, error) {
+	conn, err := c.mux.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	return NewResponseBodyServiceClient(conn).ListResponseStrings(ctx, in, opts...)
+}
+
 type response_ResponseBodyService_GetResponseBody_0 struct {
 	proto.Message
 }
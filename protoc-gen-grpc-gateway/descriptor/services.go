@@ -152,23 +152,29 @@ func (r *Registry) newMethod(svc *Service, md *descriptor.MethodDescriptorProto,
 	}
 
 	applyOpts := func(opts *options.HttpRule) error {
-		b, err := newBinding(opts, len(meth.Bindings))
-		if err != nil {
-			return err
-		}
+		for _, rule := range splitHTTPMethods(opts) {
+			b, err := newBinding(rule, len(meth.Bindings))
+			if err != nil {
+				return err
+			}
 
-		if b != nil {
-			meth.Bindings = append(meth.Bindings, b)
+			if b != nil {
+				meth.Bindings = append(meth.Bindings, b)
+			}
 		}
 		for _, additional := range opts.GetAdditionalBindings() {
 			if len(additional.AdditionalBindings) > 0 {
 				return fmt.Errorf("additional_binding in additional_binding not allowed: %s.%s", svc.GetName(), meth.GetName())
 			}
-			b, err := newBinding(additional, len(meth.Bindings))
-			if err != nil {
-				return err
+			for _, rule := range splitHTTPMethods(additional) {
+				b, err := newBinding(rule, len(meth.Bindings))
+				if err != nil {
+					return err
+				}
+				if b != nil {
+					meth.Bindings = append(meth.Bindings, b)
+				}
 			}
-			meth.Bindings = append(meth.Bindings, b)
 		}
 
 		return nil
@@ -183,6 +189,46 @@ func (r *Registry) newMethod(svc *Service, md *descriptor.MethodDescriptorProto,
 	return meth, nil
 }
 
+// splitHTTPMethods returns the rules opts stands for.
+// A custom pattern whose kind lists several HTTP methods separated by commas, e.g. "GET,POST",
+// stands for one rule per method with the same path, so that the RPC is bound to all of them.
+// The body of the rule is dropped for GET, whose fields are taken from the query instead.
+// Any other rule stands for itself.
+func splitHTTPMethods(opts *options.HttpRule) []*options.HttpRule {
+	custom := opts.GetCustom()
+	if custom == nil || !strings.Contains(custom.Kind, ",") {
+		return []*options.HttpRule{opts}
+	}
+	var rules []*options.HttpRule
+	for _, kind := range strings.Split(custom.Kind, ",") {
+		kind = strings.ToUpper(strings.TrimSpace(kind))
+		if kind == "" {
+			continue
+		}
+		rule := &options.HttpRule{
+			Body:         opts.Body,
+			ResponseBody: opts.ResponseBody,
+		}
+		switch kind {
+		case "GET":
+			rule.Pattern = &options.HttpRule_Get{Get: custom.Path}
+			rule.Body = ""
+		case "PUT":
+			rule.Pattern = &options.HttpRule_Put{Put: custom.Path}
+		case "POST":
+			rule.Pattern = &options.HttpRule_Post{Post: custom.Path}
+		case "DELETE":
+			rule.Pattern = &options.HttpRule_Delete{Delete: custom.Path}
+		case "PATCH":
+			rule.Pattern = &options.HttpRule_Patch{Patch: custom.Path}
+		default:
+			rule.Pattern = &options.HttpRule_Custom{Custom: &options.CustomHttpPattern{Kind: kind, Path: custom.Path}}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
 func extractAPIOptions(meth *descriptor.MethodDescriptorProto) (*options.HttpRule, error) {
 	if meth.Options == nil {
 		return nil, nil
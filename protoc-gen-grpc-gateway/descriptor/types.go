@@ -378,7 +378,7 @@ var (
 		// FieldDescriptorProto_TYPE_MESSAGE
 		descriptor.FieldDescriptorProto_TYPE_BYTES:    "runtime.Bytes",
 		descriptor.FieldDescriptorProto_TYPE_UINT32:   "runtime.Uint32",
-		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.Enum",
+		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.EnumContext",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED32: "runtime.Int32",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED64: "runtime.Int64",
 		descriptor.FieldDescriptorProto_TYPE_SINT32:   "runtime.Int32",
@@ -399,7 +399,7 @@ var (
 		// FieldDescriptorProto_TYPE_MESSAGE
 		descriptor.FieldDescriptorProto_TYPE_BYTES:    "runtime.BytesSlice",
 		descriptor.FieldDescriptorProto_TYPE_UINT32:   "runtime.Uint32Slice",
-		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.EnumSlice",
+		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.EnumSliceContext",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED32: "runtime.Int32Slice",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED64: "runtime.Int64Slice",
 		descriptor.FieldDescriptorProto_TYPE_SINT32:   "runtime.Int32Slice",
@@ -443,7 +443,7 @@ var (
 		// FieldDescriptorProto_TYPE_BYTES
 		// TODO(maros7) Handle bytes
 		descriptor.FieldDescriptorProto_TYPE_UINT32:   "runtime.Uint32Slice",
-		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.EnumSlice",
+		descriptor.FieldDescriptorProto_TYPE_ENUM:     "runtime.EnumSliceContext",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED32: "runtime.Int32Slice",
 		descriptor.FieldDescriptorProto_TYPE_SFIXED64: "runtime.Int64Slice",
 		descriptor.FieldDescriptorProto_TYPE_SINT32:   "runtime.Int32Slice",
@@ -48,7 +48,6 @@ func New(reg *descriptor.Registry, useRequestContext bool, registerFuncSuffix, p
 		"github.com/golang/protobuf/proto",
 		"google.golang.org/grpc",
 		"google.golang.org/grpc/codes",
-		"google.golang.org/grpc/grpclog",
 		"google.golang.org/grpc/status",
 	} {
 		pkg := descriptor.GoPackage{
@@ -239,6 +239,27 @@ func TestApplyTemplateRequestWithoutClientStreaming(t *testing.T) {
 		if want := `protoReq.GetNested().Int32, err = runtime.Int32P(val)`; !strings.Contains(got, want) {
 			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
 		}
+		if want := `if err := runtime.ValidateRequest(req.Context(), &protoReq); err != nil {`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := `func RegisterExampleServiceHandlerFromMuxConn(ctx context.Context, mux *runtime.ServeMux) error {`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := `mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "nested.bool", RPCMethod: "/example.ExampleService/Echo"}, pattern_ExampleService_Echo_0,`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := `err = runtime.Intercept(rctx, mux, w, req, func(rctx context.Context, w http.ResponseWriter, req *http.Request) error {`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := `runtime.ShouldRetry(rctx, req, attempt, err)`; strings.Contains(got, want) == spec.serverStreaming {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s only for unary methods", file, got, want)
+		}
+		if want := `mux.HandleGRPCWeb("/example.ExampleService/Echo", func(w http.ResponseWriter, req *http.Request) {`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := `runtime.ServeGRPCWebStream(ctx, mux, w, req, &protoReq,`; strings.Contains(got, want) != spec.serverStreaming {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s only for server-streaming methods", file, got, want)
+		}
 		if want := `func RegisterExampleServiceHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {`; !strings.Contains(got, want) {
 			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
 		}
@@ -397,6 +418,15 @@ func TestApplyTemplateRequestWithClientStreaming(t *testing.T) {
 		if want := `pattern_ExampleService_Echo_0 = runtime.MustPattern(runtime.NewPattern(1, []int{0, 0}, []string(nil), "", runtime.AssumeColonVerbOpt(true)))`; !strings.Contains(got, want) {
 			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
 		}
+		if spec.serverStreaming {
+			continue
+		}
+		if want := `dec := marshaler.NewDecoder(req.Body)`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
+		if want := "\t\truntime.LoggerFromContext(ctx).Infof(\"Failed to get header from client: %v\", err)\n\t\tmetadata.TrailerMD = stream.Trailer()\n"; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
 	}
 }
 
@@ -655,6 +685,9 @@ func TestAllowPatchFeature(t *testing.T) {
 				t.Errorf("applyTemplate(%#v) = %s; want to _not_ contain %s", file, got, want)
 			}
 		}
+		if want := `runtime.PopulateFieldMask(req.Context(), &protoReq, "abe", newReader)`; !strings.Contains(got, want) {
+			t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
+		}
 	}
 }
 
@@ -739,10 +772,10 @@ func TestIdentifierCapitalization(t *testing.T) {
 		t.Errorf("applyTemplate(%#v) failed with %v; want success", file, err)
 		return
 	}
-	if want := `msg, err := client.ExampleGe2T(ctx, &protoReq, grpc.Header(&metadata.HeaderMD)`; !strings.Contains(got, want) {
+	if want := `return client.ExampleGe2T(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD)`; !strings.Contains(got, want) {
 		t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
 	}
-	if want := `msg, err := client.ExamplEGet(ctx, &protoReq, grpc.Header(&metadata.HeaderMD)`; !strings.Contains(got, want) {
+	if want := `return client.ExamplEGet(ctx, &protoReq, runtime.CallOptions(ctx, grpc.Header(&metadata.HeaderMD)`; !strings.Contains(got, want) {
 		t.Errorf("applyTemplate(%#v) = %s; want to contain %s", file, got, want)
 	}
 	if want := `var protoReq ExamPleRequest`; !strings.Contains(got, want) {
@@ -7,14 +7,17 @@ import (
 	"net"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
@@ -24,7 +27,8 @@ import (
 const MetadataHeaderPrefix = "Grpc-Metadata-"
 
 // MetadataPrefix is prepended to permanent HTTP header keys (as specified
-// by the IANA) when added to the gRPC context.
+// by the IANA) when added to the gRPC context, unless the mux was created
+// with WithMetadataPrefix.
 const MetadataPrefix = "grpcgateway-"
 
 // MetadataTrailerPrefix is prepended to gRPC metadata as it is converted to
@@ -36,10 +40,17 @@ const metadataHeaderBinarySuffix = "-Bin"
 
 const xForwardedFor = "X-Forwarded-For"
 const xForwardedHost = "X-Forwarded-Host"
+const xForwardedProto = "X-Forwarded-Proto"
+const forwardedHeader = "Forwarded"
+const xRequestID = "X-Request-Id"
+const xHTTPMethod = "X-Http-Method"
+const xHTTPPathPattern = "X-Http-Path-Pattern"
 
 var (
 	// DefaultContextTimeout is used for gRPC call context.WithTimeout whenever a Grpc-Timeout inbound
 	// header isn't present. If the value is 0 the sent `context` will not have a timeout.
+	// Neither it nor the header extends a deadline already set on the context.
+	// A ServeMux created with WithDefaultTimeout uses its own timeout instead.
 	DefaultContextTimeout = 0 * time.Second
 )
 
@@ -84,44 +95,193 @@ func AnnotateIncomingContext(ctx context.Context, mux *ServeMux, req *http.Reque
 	return metadata.NewIncomingContext(ctx, md), nil
 }
 
-func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request) (context.Context, metadata.MD, error) {
+// NewTestContext returns the context AnnotateContext would pass to the gRPC call for req on a
+// ServeMux created with opts, for unit tests of code which reads the gateway metadata from the
+// outgoing context, such as gRPC client interceptors. It panics if req cannot be annotated.
+func NewTestContext(req *http.Request, opts ...ServeMuxOption) context.Context {
+	ctx, err := AnnotateContext(req.Context(), NewServeMux(opts...), req)
+	if err != nil {
+		panic(fmt.Sprintf("runtime: cannot annotate test request: %v", err))
+	}
+	return ctx
+}
+
+// NewTestIncomingContext is like NewTestContext, but returns the context AnnotateIncomingContext
+// would pass to a local server, with the gateway metadata as incoming metadata.
+func NewTestIncomingContext(req *http.Request, opts ...ServeMuxOption) context.Context {
+	ctx, err := AnnotateIncomingContext(req.Context(), NewServeMux(opts...), req)
+	if err != nil {
+		panic(fmt.Sprintf("runtime: cannot annotate test request: %v", err))
+	}
+	return ctx
+}
+
+// serverSpanKey is the context key of the *serverSpan of a request served by a ServeMux.
+type serverSpanKey struct{}
+
+// serverSpan holds the tracing span of a request, which the ServeMux serving it finishes once the
+// request is done, so that it covers every attempt of the gRPC call.
+type serverSpan struct {
+	span     opentracing.Span
+	attempts int
+}
+
+func serverSpanFromContext(ctx context.Context) *serverSpan {
+	s, _ := ctx.Value(serverSpanKey{}).(*serverSpan)
+	return s
+}
+
+func (s *serverSpan) finish() {
+	if s.span != nil {
+		s.span.Finish()
+	}
+}
+
+// startAttempt starts the child span of the next attempt of the gRPC call, tagged with its number.
+func (s *serverSpan) startAttempt() opentracing.Span {
+	s.attempts++
+	return opentracing.StartSpan(
+		"attempt",
+		opentracing.ChildOf(s.span.Context()),
+		opentracing.Tag{Key: "attempt", Value: s.attempts})
+}
+
+// requestServerSpan returns the server span of req and a function to call once annotateContext is done
+// with it. The span of a request served by a ServeMux is started once and finished by the ServeMux.
+func requestServerSpan(req *http.Request) (opentracing.Span, func(), error) {
+	holder := serverSpanFromContext(req.Context())
+	if holder != nil && holder.span != nil {
+		return holder.span, func() {}, nil
+	}
 	wireContext, err := opentracing.GlobalTracer().Extract(
 		opentracing.HTTPHeaders,
 		opentracing.HTTPHeadersCarrier(req.Header))
-	if err != nil {
+	// A request without tracing headers simply starts a new trace.
+	if err != nil && err != opentracing.ErrSpanContextNotFound {
 		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid HTTP request parameters: %s", err)
 	}
 
 	serverSpan := opentracing.StartSpan(
 		req.URL.Path,
 		ext.RPCServerOption(wireContext))
+	if holder == nil {
+		return serverSpan, serverSpan.Finish, nil
+	}
+	holder.span = serverSpan
+	return serverSpan, func() {}, nil
+}
 
-	defer serverSpan.Finish()
+// AnnotatedContext returns the context AnnotateContext returns for r, which the ServeMux dispatching r
+// passes to the gRPC call, with its metadata, deadline and tracing span, e.g. for a middleware set with
+// WithMiddleware to inspect. Such middlewares share the annotation with the handler: the request is
+// annotated only once, so that annotators and rate limiters run once, whichever calls first.
+// It returns an error if r was not dispatched by a ServeMux, or if it cannot be annotated.
+func AnnotatedContext(r *http.Request) (context.Context, error) {
+	mux, _ := r.Context().Value(serveMuxKey{}).(*ServeMux)
+	if mux == nil {
+		return nil, status.Error(codes.Internal, "request was not dispatched by a ServeMux")
+	}
+	return AnnotateContext(r.Context(), mux, r)
+}
+
+// annotationKey is the context key of the *annotation of a request whose ServeMux has middlewares.
+type annotationKey struct{}
+
+// annotation is the result of annotating a request, shared by the calls of annotateContext for it.
+type annotation struct {
+	done        bool
+	err         error
+	md          metadata.MD
+	span        opentracing.Span
+	callOptions interface{}
+	deadline    time.Time
+	hasDeadline bool
+	source      interface{}
+}
+
+// apply returns ctx annotated like the context the annotation was taken from.
+func (a *annotation) apply(ctx context.Context, req *http.Request) context.Context {
+	if a.span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, a.span)
+	}
+	if a.callOptions != nil {
+		ctx = context.WithValue(ctx, callOptionsKey{}, a.callOptions)
+	}
+	if d, ok := ctx.Deadline(); a.hasDeadline && (!ok || a.deadline.Before(d)) {
+		ctx = withDeadline(ctx, req, a.deadline)
+	}
+	if a.source != nil {
+		ctx = context.WithValue(ctx, deadlineSourceKey{}, a.source)
+	}
+	return ctx
+}
+
+func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request) (context.Context, metadata.MD, error) {
+	a, _ := req.Context().Value(annotationKey{}).(*annotation)
+	if a == nil {
+		return annotateRequest(ctx, mux, req)
+	}
+	if !a.done {
+		annotated, md, err := annotateRequest(ctx, mux, req)
+		a.done, a.err, a.md = true, err, md
+		if err == nil {
+			a.span = opentracing.SpanFromContext(annotated)
+			a.callOptions = annotated.Value(callOptionsKey{})
+			a.deadline, a.hasDeadline = annotated.Deadline()
+			a.source = annotated.Value(deadlineSourceKey{})
+		}
+		return annotated, md, err
+	}
+	if a.err != nil {
+		return nil, nil, a.err
+	}
+	return a.apply(ctx, req), a.md.Copy(), nil
+}
+
+func annotateRequest(ctx context.Context, mux *ServeMux, req *http.Request) (context.Context, metadata.MD, error) {
+	serverSpan, finish, err := requestServerSpan(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer finish()
 
 	ctx = opentracing.ContextWithSpan(ctx, serverSpan)
+	if mux.callOptions != nil || mux.authority != nil {
+		var opts []grpc.CallOption
+		if mux.callOptions != nil {
+			opts = mux.callOptions(ctx, req)
+		}
+		if mux.authority != nil {
+			if authority := mux.authority(req); authority != "" {
+				opts = append(opts, AuthorityCallOption{Authority: authority})
+			}
+		}
+		ctx = context.WithValue(ctx, callOptionsKey{}, opts)
+	}
 
 	var pairs []string
-	timeout := DefaultContextTimeout
-	if tm := req.Header.Get(metadataGrpcTimeout); tm != "" {
-		var err error
-		timeout, err = timeoutDecode(tm)
-		if err != nil {
-			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid grpc-timeout: %s", tm)
-		}
+	timeout, fromClient, err := requestTimeout(mux, req)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	pat, _ := HTTPPathPattern(req.Context())
+	stripAuthorization := mux.authorizationStripped[pat]
 	for key, vals := range req.Header {
 		for _, val := range vals {
 			key = textproto.CanonicalMIMEHeaderKey(key)
 			// For backwards-compatibility, pass through 'authorization' header with no prefix.
 			if key == "Authorization" {
+				if stripAuthorization {
+					continue
+				}
 				pairs = append(pairs, "authorization", val)
 			}
 			if h, ok := mux.incomingHeaderMatcher(key); ok {
 				// Handles "-bin" metadata in grpc, since grpc will do another base64
 				// encode before sending to server, we need to decode it first.
 				if strings.HasSuffix(key, metadataHeaderBinarySuffix) {
-					b, err := decodeBinHeader(val)
+					b, err := mux.binaryHeaderDecoder(val)
 					if err != nil {
 						return nil, nil, status.Errorf(codes.InvalidArgument, "invalid binary header %s: %s", key, err)
 					}
@@ -132,37 +292,560 @@ func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request) (con
 			}
 		}
 	}
-	if host := req.Header.Get(xForwardedHost); host != "" {
-		pairs = append(pairs, strings.ToLower(xForwardedHost), host)
+	if mux.decodeBasicAuth && !stripAuthorization {
+		if user, pass, ok, err := basicAuth(req); err != nil {
+			return nil, nil, err
+		} else if ok {
+			pairs = append(pairs, "x-basic-user", user, "x-basic-pass", pass)
+		}
+	}
+	if name := mux.authorizationCookie; name != "" && req.Header.Get("Authorization") == "" && !stripAuthorization {
+		if c, err := req.Cookie(name); err == nil && c.Value != "" {
+			pairs = append(pairs, "authorization", "Bearer "+c.Value)
+		}
+	}
+	if mux.cookieMatcher != nil {
+		for _, c := range req.Cookies() {
+			key, ok := mux.cookieMatcher(c.Name)
+			if !ok {
+				continue
+			}
+			val := c.Value
+			if v, err := url.QueryUnescape(val); err == nil {
+				val = v
+			}
+			pairs = append(pairs, key, val)
+		}
+	}
+	fwdFor, fwdHost, fwdProto := forwardedHeaders(mux, req)
+	if fwdHost != "" {
+		pairs = append(pairs, strings.ToLower(xForwardedHost), fwdHost)
 	} else if req.Host != "" {
 		pairs = append(pairs, strings.ToLower(xForwardedHost), req.Host)
 	}
 
 	if addr := req.RemoteAddr; addr != "" {
-		if remoteIP, _, err := net.SplitHostPort(addr); err == nil {
-			if fwd := req.Header.Get(xForwardedFor); fwd == "" {
+		remoteIP, port, err := net.SplitHostPort(addr)
+		if err != nil && net.ParseIP(addr) != nil {
+			// Some transports report the address of the client without a port.
+			remoteIP, port, err = addr, "", nil
+		}
+		if err == nil {
+			if mux.forwardSourcePort && port != "" {
+				// JoinHostPort brackets IPv6 addresses, e.g. "[2001:db8::1]:12345".
+				remoteIP = net.JoinHostPort(remoteIP, port)
+			}
+			if fwdFor == "" {
 				pairs = append(pairs, strings.ToLower(xForwardedFor), remoteIP)
 			} else {
-				pairs = append(pairs, strings.ToLower(xForwardedFor), fmt.Sprintf("%s, %s", fwd, remoteIP))
+				pairs = append(pairs, strings.ToLower(xForwardedFor), fmt.Sprintf("%s, %s", fwdFor, remoteIP))
 			}
 		} else {
-			grpclog.Infof("invalid remote addr: %s", addr)
+			loggerOf(mux).Infof("invalid remote addr: %s", addr)
+		}
+	}
+	if fwdProto != "" {
+		pairs = append(pairs, strings.ToLower(xForwardedProto), fwdProto)
+	}
+
+	if id, ok := RequestID(req.Context()); ok {
+		pairs = append(pairs, strings.ToLower(xRequestID), id)
+	}
+
+	if !mux.disableHTTPRequestMetadata {
+		pairs = append(pairs, strings.ToLower(xHTTPMethod), req.Method)
+		if pat, ok := HTTPPathPattern(req.Context()); ok {
+			pairs = append(pairs, strings.ToLower(xHTTPPathPattern), pat)
+		}
+	}
+
+	if mux.rawBodyMetadataKey != "" {
+		if raw, ok := req.Context().Value(rawRequestBodyKey{}).([]byte); ok {
+			pairs = append(pairs, mux.rawBodyMetadataKey, string(raw))
+		}
+	}
+
+	if mux.rateLimiter != nil {
+		pat, _ := HTTPPathPattern(req.Context())
+		if err := mux.rateLimiter(ctx, pat, clientIP(mux, req)); err != nil {
+			if _, ok := status.FromError(err); !ok {
+				err = status.Error(codes.ResourceExhausted, err.Error())
+			}
+			return nil, nil, err
 		}
 	}
 
-	if timeout != 0 {
-		ctx, _ = context.WithTimeout(ctx, timeout)
+	// Measure the deadline from when the mux received the request, so that time spent
+	// before the call, e.g. in annotators, counts against it.
+	start, ok := req.Context().Value(requestStartKey{}).(time.Time)
+	if !ok {
+		start = time.Now()
 	}
-	if len(pairs) == 0 {
-		return ctx, nil, nil
+	var call *callDeadline
+	if d, ok := ctx.Deadline(); ok {
+		call = &callDeadline{source: DeadlineFromContext, timeout: d.Sub(start)}
+	}
+	// A deadline the caller already set on ctx is only ever tightened, never extended.
+	if d, ok := ctx.Deadline(); timeout != 0 && (!ok || start.Add(timeout).Before(d)) {
+		ctx = withDeadline(ctx, req, start.Add(timeout))
+		call = &callDeadline{source: DeadlineFromDefault, timeout: timeout}
+		if fromClient {
+			call.source = DeadlineFromHeader
+		}
 	}
-	md := metadata.Pairs(pairs...)
-	for _, mda := range mux.metadataAnnotators {
-		md = metadata.Join(md, mda(ctx, req))
+	if call != nil {
+		ctx = withCallDeadline(ctx, req, call)
+	}
+	var md metadata.MD
+	if len(pairs) != 0 {
+		md = metadata.Pairs(pairs...)
+		for _, mda := range mux.metadataAnnotators {
+			md = metadata.Join(md, mda(ctx, req, md.Copy()))
+		}
+	}
+	if mux.preserveIncomingMetadata {
+		if incoming, ok := metadata.FromIncomingContext(ctx); ok {
+			md = metadata.Join(incoming, md)
+		}
+	}
+	md = lowercaseKeys(md)
+	// The limits apply to all of the forwarded metadata, preserved incoming pairs included.
+	if err := checkMetadataLimits(mux, md); err != nil {
+		return nil, nil, err
 	}
 	return ctx, md, nil
 }
 
+// checkMetadataLimits returns an error if md, the metadata forwarded for a request, exceeds the
+// limits set on mux.
+func checkMetadataLimits(mux *ServeMux, md metadata.MD) error {
+	if mux.maxMetadataPairs <= 0 && mux.maxMetadataBytes <= 0 {
+		return nil
+	}
+	var pairs, size int
+	for k, vs := range md {
+		pairs += len(vs)
+		for _, v := range vs {
+			size += len(k) + len(v)
+		}
+	}
+	if mux.maxMetadataPairs > 0 && pairs > mux.maxMetadataPairs {
+		return status.Errorf(codes.InvalidArgument, "too many metadata pairs: %d, limit %d", pairs, mux.maxMetadataPairs)
+	}
+	if mux.maxMetadataBytes > 0 && size > mux.maxMetadataBytes {
+		return status.Errorf(codes.InvalidArgument, "metadata too large: %d bytes, limit %d", size, mux.maxMetadataBytes)
+	}
+	return nil
+}
+
+// lowercaseKeys returns md with its keys lowercased as gRPC requires, merging the values
+// of keys which differ only in case. Keys from metadata.Pairs already are lowercase, but
+// those of metadata built by annotators or carried by the context may not be.
+func lowercaseKeys(md metadata.MD) metadata.MD {
+	var keys []string
+	for k := range md {
+		if k != strings.ToLower(k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return md
+	}
+	// Merge in a deterministic order so that the values of keys differing in case keep a stable order.
+	sort.Strings(keys)
+	for _, k := range keys {
+		lk := strings.ToLower(k)
+		md[lk] = append(md[lk], md[k]...)
+		delete(md, k)
+	}
+	return md
+}
+
+// requestTimeout returns the timeout of the gRPC call for req, or 0 if it has none.
+// fromClient reports whether the timeout was requested with a Grpc-Timeout header,
+// or the header set with WithTimeoutHeader, rather than being the default timeout of mux.
+func requestTimeout(mux *ServeMux, req *http.Request) (timeout time.Duration, fromClient bool, err error) {
+	if mux.timeoutHeaderPrecedence == TimeoutHeaderFirst {
+		if timeout, ok, err := headerTimeout(mux, req); ok || err != nil {
+			return timeout, ok, err
+		}
+	}
+	if values := req.Header[metadataGrpcTimeout]; len(values) > 1 {
+		// An ambiguous deadline is an error rather than a choice between the values.
+		for _, v := range values[1:] {
+			if v != values[0] {
+				return 0, false, status.Errorf(codes.InvalidArgument, "conflicting grpc-timeout values: %q", values)
+			}
+		}
+	}
+	if tm := req.Header.Get(metadataGrpcTimeout); tm != "" {
+		timeout, err = timeoutDecode(tm)
+		if err != nil {
+			return 0, false, status.Errorf(codes.InvalidArgument, "invalid grpc-timeout: %s", tm)
+		}
+		return timeout, true, nil
+	}
+	if timeout, ok, err := headerTimeout(mux, req); ok || err != nil {
+		return timeout, ok, err
+	}
+	if pat, ok := HTTPPathPattern(req.Context()); ok && mux.streamingDeadlineExempt[pat] {
+		return 0, false, nil
+	}
+	return mux.DefaultTimeout(), false, nil
+}
+
+// headerTimeout returns the timeout requested with the header set with WithTimeoutHeader, and
+// whether req has the header.
+func headerTimeout(mux *ServeMux, req *http.Request) (time.Duration, bool, error) {
+	if mux.timeoutHeader == "" {
+		return 0, false, nil
+	}
+	v := req.Header.Get(mux.timeoutHeader)
+	if v == "" {
+		return 0, false, nil
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout < 0 {
+		return 0, false, status.Errorf(codes.InvalidArgument, "invalid %s: %s", strings.ToLower(mux.timeoutHeader), v)
+	}
+	return timeout, true, nil
+}
+
+// clientIP returns the address of the client which originated req: the first address
+// forwarded by its X-Forwarded-For or Forwarded header if present, and the host of its
+// RemoteAddr otherwise.
+func clientIP(mux *ServeMux, req *http.Request) string {
+	if fwd, _, _ := forwardedHeaders(mux, req); fwd != "" {
+		if ip := strings.TrimSpace(strings.Split(fwd, ",")[0]); ip != "" {
+			return ip
+		}
+	}
+	if ip, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return ip
+	}
+	return req.RemoteAddr
+}
+
+// forwardedHeaders returns the forwarded client addresses, host and protocol of req. They come from
+// its X-Forwarded-For and X-Forwarded-Host headers, or from its Forwarded header where those are
+// missing or where mux was created with ForwardedFirst precedence.
+func forwardedHeaders(mux *ServeMux, req *http.Request) (fwdFor, fwdHost, fwdProto string) {
+	fwdFor, fwdHost = req.Header.Get(xForwardedFor), req.Header.Get(xForwardedHost)
+	values := req.Header[forwardedHeader]
+	if len(values) == 0 {
+		return fwdFor, fwdHost, ""
+	}
+	fors, host, proto := parseForwarded(values)
+	preferForwarded := mux.forwardedHeaderPrecedence == ForwardedFirst
+	if len(fors) != 0 && (preferForwarded || fwdFor == "") {
+		fwdFor = strings.Join(fors, ", ")
+	}
+	if host != "" && (preferForwarded || fwdHost == "") {
+		fwdHost = host
+	}
+	return fwdFor, fwdHost, proto
+}
+
+// parseForwarded parses the values of a Forwarded header as specified by RFC 7239. It returns the
+// "for" parameters of its elements, with the ports of IP addresses removed like in X-Forwarded-For,
+// and the "host" and "proto" parameters of its first element, set by the proxy closest to the client.
+func parseForwarded(values []string) (fors []string, host, proto string) {
+	first := true
+	for _, value := range values {
+		for _, elem := range splitQuoted(value, ',') {
+			for _, pair := range splitQuoted(elem, ';') {
+				i := strings.IndexByte(pair, '=')
+				if i < 0 {
+					continue
+				}
+				key, val := strings.ToLower(strings.TrimSpace(pair[:i])), unquoteForwarded(strings.TrimSpace(pair[i+1:]))
+				switch {
+				case key == "for" && val != "":
+					fors = append(fors, forwardedNode(val))
+				case key == "host" && first:
+					host = val
+				case key == "proto" && first:
+					proto = strings.ToLower(val)
+				}
+			}
+			first = false
+		}
+	}
+	return fors, host, proto
+}
+
+// splitQuoted splits s at each sep which is not within a quoted string.
+func splitQuoted(s string, sep byte) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquoteForwarded returns the value of a Forwarded parameter, which may be a quoted string.
+func unquoteForwarded(v string) string {
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return v
+	}
+	v = v[1 : len(v)-1]
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' && i+1 < len(v) {
+			i++
+		}
+		b.WriteByte(v[i])
+	}
+	return b.String()
+}
+
+// forwardedNode returns the address in the node identifier n of a "for" parameter without its
+// port, e.g. "2001:db8::1" for "[2001:db8::1]:4711". Obfuscated identifiers and "unknown" are
+// returned as they are.
+func forwardedNode(n string) string {
+	if strings.HasPrefix(n, "[") {
+		if i := strings.IndexByte(n, ']'); i > 0 {
+			return n[1:i]
+		}
+		return n
+	}
+	if host, _, err := net.SplitHostPort(n); err == nil {
+		return host
+	}
+	return n
+}
+
+// basicAuth decodes the credentials of an "Authorization: Basic" header of req.
+// ok is false if req does not use Basic authentication.
+func basicAuth(req *http.Request) (user, pass string, ok bool, err error) {
+	const prefix = "basic "
+	auth := req.Header.Get("Authorization")
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", "", false, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(auth[len(prefix):]))
+	if err != nil {
+		return "", "", false, status.Errorf(codes.Unauthenticated, "invalid basic authorization: %s", err)
+	}
+	creds := string(b)
+	i := strings.IndexByte(creds, ':')
+	if i < 0 {
+		return "", "", false, status.Error(codes.Unauthenticated, "invalid basic authorization: missing password separator")
+	}
+	return creds[:i], creds[i+1:], true, nil
+}
+
+type httpPatternKey struct{}
+
+// withHTTPPattern returns a copy of ctx carrying the path template of the
+// route the request was dispatched to.
+func withHTTPPattern(ctx context.Context, pat Pattern) context.Context {
+	return context.WithValue(ctx, httpPatternKey{}, pat)
+}
+
+// HTTPPathPattern returns the path template (e.g. "/v1/{name=*}") of the route
+// which ServeMux dispatched the request to. It returns false if the context
+// does not come from a request dispatched by ServeMux.
+func HTTPPathPattern(ctx context.Context) (string, bool) {
+	pat, ok := ctx.Value(httpPatternKey{}).(Pattern)
+	if !ok {
+		return "", false
+	}
+	return pat.String(), true
+}
+
+// rawRequestBodyKey is the context key of the request body captured for WithRawBodyMetadata.
+type rawRequestBodyKey struct{}
+
+type rpcMethodKey struct{}
+
+// RPCMethod returns the full name of the gRPC method (e.g. "/pkg.Service/Method") of the route
+// which ServeMux dispatched the request to. It returns false if the context does not come from
+// a request dispatched by ServeMux, or if the route was registered without RouteInfo.RPCMethod.
+func RPCMethod(ctx context.Context) (string, bool) {
+	m, ok := ctx.Value(rpcMethodKey{}).(string)
+	return m, ok
+}
+
+type inboundContentTypeKey struct{}
+
+// InboundMarshalerContentType returns the content type of the inbound marshaler which ServeMux
+// selected to decode the body of the request, from its Content-Type header, or its gRPC-Web content
+// type. It is the content type the marshaler reports, e.g. "application/json" for JSONPb, rather
+// than the header itself. It returns false if the context does not come from a request dispatched
+// by ServeMux.
+func InboundMarshalerContentType(ctx context.Context) (string, bool) {
+	ct, ok := ctx.Value(inboundContentTypeKey{}).(string)
+	return ct, ok
+}
+
+type outboundContentTypeKey struct{}
+
+// OutboundMarshalerContentType returns the content type of the outbound marshaler which ServeMux
+// selected for the request, from its Accept header, alt parameter or Content-Type, or its gRPC-Web
+// content type. It returns false if the context does not come from a request dispatched by ServeMux.
+//
+// Marshalers such as HTTPBodyMarshaler may still pick the content type of each response message.
+func OutboundMarshalerContentType(ctx context.Context) (string, bool) {
+	ct, ok := ctx.Value(outboundContentTypeKey{}).(string)
+	return ct, ok
+}
+
+type callOptionsKey struct{}
+
+// CallOptions returns the grpc.CallOptions computed for the request by the hook configured with
+// WithCallOptions, followed by opts.
+func CallOptions(ctx context.Context, opts ...grpc.CallOption) []grpc.CallOption {
+	perRequest, _ := ctx.Value(callOptionsKey{}).([]grpc.CallOption)
+	if len(perRequest) == 0 {
+		return opts
+	}
+	return append(perRequest[:len(perRequest):len(perRequest)], opts...)
+}
+
+// AuthorityCallOption is a grpc.CallOption carrying the :authority a ServeMux configured with
+// WithAuthority selected for a call.
+//
+// grpc-go sends the authority of the ClientConn with every call, so the option takes effect only
+// where a client interceptor or a custom connection reads it, e.g. to invoke the call on a
+// connection dialed with grpc.WithAuthority.
+type AuthorityCallOption struct {
+	grpc.EmptyCallOption
+	Authority string
+}
+
+// AuthorityFromCallOptions returns the authority carried by an AuthorityCallOption in opts.
+func AuthorityFromCallOptions(opts []grpc.CallOption) (string, bool) {
+	for i := len(opts) - 1; i >= 0; i-- {
+		if o, ok := opts[i].(AuthorityCallOption); ok {
+			return o.Authority, true
+		}
+	}
+	return "", false
+}
+
+type requestIDKey struct{}
+
+// requestStartKey is the context key of the time a ServeMux received a request.
+type requestStartKey struct{}
+
+// requestDeadlineKey is the context key of the *requestDeadline of a request served by a ServeMux.
+type requestDeadlineKey struct{}
+
+// requestDeadline holds the deadline of the gRPC call of a request, for the error handlers which are
+// not given the annotated context, and the cancel functions of the deadlines set on the contexts of
+// the request, which the ServeMux serving it calls once the request is done.
+type requestDeadline struct {
+	mu      sync.Mutex
+	call    *callDeadline
+	cancels []context.CancelFunc
+}
+
+func (d *requestDeadline) cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, f := range d.cancels {
+		f()
+	}
+	d.cancels = nil
+}
+
+// callDeadline is where the deadline of a gRPC call came from, and how long after the start of the
+// request it is.
+type callDeadline struct {
+	source  string
+	timeout time.Duration
+}
+
+// withCallDeadline returns ctx carrying call, which is also recorded on req if a ServeMux serves it.
+func withCallDeadline(ctx context.Context, req *http.Request, call *callDeadline) context.Context {
+	if d, ok := req.Context().Value(requestDeadlineKey{}).(*requestDeadline); ok {
+		d.mu.Lock()
+		d.call = call
+		d.mu.Unlock()
+	}
+	return context.WithValue(ctx, deadlineSourceKey{}, call)
+}
+
+// deadlineOfCall returns the deadline of the gRPC call of req, from ctx if it was annotated or else
+// from what annotating req recorded.
+func deadlineOfCall(ctx context.Context, req *http.Request) (*callDeadline, bool) {
+	if call, ok := ctx.Value(deadlineSourceKey{}).(*callDeadline); ok {
+		return call, true
+	}
+	if d, ok := req.Context().Value(requestDeadlineKey{}).(*requestDeadline); ok {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.call, d.call != nil
+	}
+	return nil, false
+}
+
+// withDeadline returns ctx with the deadline d, whose timer is released once req is done: by the
+// ServeMux serving req, or otherwise once ctx or the context of req is done.
+func withDeadline(ctx context.Context, req *http.Request, d time.Time) context.Context {
+	ctx, cancel := context.WithDeadline(ctx, d)
+	if rd, ok := req.Context().Value(requestDeadlineKey{}).(*requestDeadline); ok {
+		rd.mu.Lock()
+		rd.cancels = append(rd.cancels, cancel)
+		rd.mu.Unlock()
+		return ctx
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-req.Context().Done():
+		}
+		cancel()
+	}()
+	return ctx
+}
+
+// deadlineSourceKey is the context key of where the deadline of the gRPC call came from.
+type deadlineSourceKey struct{}
+
+// The sources of the deadline of a gRPC call reported by DeadlineSource.
+const (
+	// DeadlineFromHeader is the source of a deadline set by the Grpc-Timeout header of the request.
+	DeadlineFromHeader = "header"
+	// DeadlineFromDefault is the source of a deadline set by the default timeout of the ServeMux,
+	// see ServeMux.DefaultTimeout.
+	DeadlineFromDefault = "default"
+	// DeadlineFromContext is the source of a deadline already on the context passed to AnnotateContext,
+	// which neither of the others tightened.
+	DeadlineFromContext = "context"
+)
+
+// DeadlineSource returns where the deadline of a context returned by AnnotateContext or
+// AnnotateIncomingContext came from: DeadlineFromHeader, DeadlineFromDefault or DeadlineFromContext.
+// ok is false if the context has no deadline.
+func DeadlineSource(ctx context.Context) (source string, ok bool) {
+	call, ok := ctx.Value(deadlineSourceKey{}).(*callDeadline)
+	if !ok {
+		return "", false
+	}
+	return call.source, true
+}
+
+// RequestID returns the ID assigned to the request by a ServeMux configured
+// with WithRequestID.
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
 // ServerMetadata consists of metadata sent from gRPC server.
 type ServerMetadata struct {
 	HeaderMD  metadata.MD
@@ -198,6 +881,38 @@ func timeoutDecode(s string) (time.Duration, error) {
 	return d * time.Duration(t), nil
 }
 
+// maxTimeoutValue is the largest value of a Grpc-Timeout header, which has at most 8 digits.
+const maxTimeoutValue = 1e8 - 1
+
+// EncodeGrpcTimeout encodes d as the value of a Grpc-Timeout header, e.g. to propagate the remaining
+// deadline of a request to a further gRPC server. It uses the largest unit in which d is a whole number
+// of at most 8 digits. If there is none, d is rounded up in the smallest unit which fits it.
+// Non-positive durations are encoded as "0n".
+func EncodeGrpcTimeout(d time.Duration) string {
+	if d <= 0 {
+		return "0n"
+	}
+	units := []uint8{'H', 'M', 'S', 'm', 'u', 'n'}
+	for _, u := range units {
+		unit, _ := timeoutUnitToDuration(u)
+		if d%unit == 0 && d/unit <= maxTimeoutValue {
+			return strconv.FormatInt(int64(d/unit), 10) + string(u)
+		}
+	}
+	for i := len(units) - 1; i >= 0; i-- {
+		unit, _ := timeoutUnitToDuration(units[i])
+		v := d / unit
+		if d%unit != 0 {
+			v++
+		}
+		if v <= maxTimeoutValue {
+			return strconv.FormatInt(int64(v), 10) + string(units[i])
+		}
+	}
+	// Unreachable: the largest time.Duration is much less than maxTimeoutValue hours.
+	return strconv.FormatInt(maxTimeoutValue, 10) + "H"
+}
+
 func timeoutUnitToDuration(u uint8) (d time.Duration, ok bool) {
 	switch u {
 	case 'H':
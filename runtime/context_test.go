@@ -4,16 +4,24 @@ import (
 	"context"
 	"encoding/base64"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ninnemana/grpc-gateway/runtime"
+	"github.com/ninnemana/grpc-gateway/utilities"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
-	emptyForwardMetaCount = 1
+	emptyForwardMetaCount = 2
 )
 
 func TestAnnotateContext_WorksWithEmpty(t *testing.T) {
@@ -120,6 +128,114 @@ func TestAnnotateContext_XForwardedFor(t *testing.T) {
 	}
 }
 
+func TestAnnotateContext_Forwarded(t *testing.T) {
+	for _, spec := range []struct {
+		name       string
+		precedence runtime.ForwardedHeaderPrecedence
+		headers    map[string]string
+		wantFor    string
+		wantHost   string
+		wantProto  string
+	}{
+		{
+			name:      "forwarded only",
+			headers:   map[string]string{"Forwarded": `for=192.0.2.60:4711;proto=HTTPS;host=api.example.com, for="[2001:db8::1]:80";host=inner`},
+			wantFor:   "192.0.2.60, 2001:db8::1, 192.0.2.200",
+			wantHost:  "api.example.com",
+			wantProto: "https",
+		},
+		{
+			name:      "x-forwarded first",
+			headers:   map[string]string{"Forwarded": `for=192.0.2.60;host="a.example.com";proto=http`, "X-Forwarded-For": "192.0.2.100", "X-Forwarded-Host": "b.example.com"},
+			wantFor:   "192.0.2.100, 192.0.2.200",
+			wantHost:  "b.example.com",
+			wantProto: "http",
+		},
+		{
+			name:       "forwarded first",
+			precedence: runtime.ForwardedFirst,
+			headers:    map[string]string{"Forwarded": `For=_hidden;Host="a.example.com"`, "X-Forwarded-For": "192.0.2.100", "X-Forwarded-Host": "b.example.com"},
+			wantFor:    "_hidden, 192.0.2.200",
+			wantHost:   "a.example.com",
+		},
+	} {
+		request := httptest.NewRequest("GET", "http://www.example.com", nil)
+		request.RemoteAddr = "192.0.2.200:12345"
+		for k, v := range spec.headers {
+			request.Header.Set(k, v)
+		}
+		annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithForwardedHeaderPrecedence(spec.precedence)), request)
+		if err != nil {
+			t.Errorf("%s: runtime.AnnotateContext(ctx, %#v) failed with %v; want success", spec.name, request, err)
+			continue
+		}
+		md, _ := metadata.FromOutgoingContext(annotated)
+		if got, want := md["x-forwarded-for"], []string{spec.wantFor}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`%s: md["x-forwarded-for"] = %q; want %q`, spec.name, got, want)
+		}
+		if got, want := md["x-forwarded-host"], []string{spec.wantHost}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`%s: md["x-forwarded-host"] = %q; want %q`, spec.name, got, want)
+		}
+		var wantProto []string
+		if spec.wantProto != "" {
+			wantProto = []string{spec.wantProto}
+		}
+		if got := md["x-forwarded-proto"]; !reflect.DeepEqual(got, wantProto) {
+			t.Errorf(`%s: md["x-forwarded-proto"] = %q; want %q`, spec.name, got, wantProto)
+		}
+	}
+}
+
+func TestAnnotateContext_RemoteAddrWithoutPort(t *testing.T) {
+	for _, spec := range []struct {
+		remoteAddr string
+		want       []string
+	}{
+		{remoteAddr: "192.0.2.200", want: []string{"192.0.2.200"}},
+		{remoteAddr: "2001:db8::1", want: []string{"2001:db8::1"}},
+		{remoteAddr: "@", want: nil},
+	} {
+		request := httptest.NewRequest("GET", "http://www.example.com", nil)
+		request.RemoteAddr = spec.remoteAddr
+		annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithForwardSourcePort()), request)
+		if err != nil {
+			t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			continue
+		}
+		md, _ := metadata.FromOutgoingContext(annotated)
+		if got := md["x-forwarded-for"]; !reflect.DeepEqual(got, spec.want) {
+			t.Errorf(`md["x-forwarded-for"] = %v want %v; remote addr %q`, got, spec.want, spec.remoteAddr)
+		}
+	}
+}
+
+func TestAnnotateContext_ForwardSourcePort(t *testing.T) {
+	for _, spec := range []struct {
+		remoteAddr string
+		fwd        string
+		want       string
+	}{
+		{remoteAddr: "192.0.2.200:12345", want: "192.0.2.200:12345"},
+		{remoteAddr: "[2001:db8::1]:12345", want: "[2001:db8::1]:12345"},
+		{remoteAddr: "192.0.2.200:12345", fwd: "192.0.2.100", want: "192.0.2.100, 192.0.2.200:12345"},
+	} {
+		request := httptest.NewRequest("GET", "http://www.example.com", nil)
+		request.RemoteAddr = spec.remoteAddr
+		if spec.fwd != "" {
+			request.Header.Set("X-Forwarded-For", spec.fwd)
+		}
+		annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithForwardSourcePort()), request)
+		if err != nil {
+			t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			continue
+		}
+		md, _ := metadata.FromOutgoingContext(annotated)
+		if got, want := md["x-forwarded-for"], []string{spec.want}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`md["x-forwarded-for"] = %v want %v; remote addr %q`, got, want, spec.remoteAddr)
+		}
+	}
+}
+
 func TestAnnotateContext_SupportsTimeouts(t *testing.T) {
 	ctx := context.Background()
 	request, err := http.NewRequest("GET", "http://example.com", nil)
@@ -195,6 +311,169 @@ func TestAnnotateContext_SupportsTimeouts(t *testing.T) {
 		}
 	}
 }
+
+func TestAnnotateContext_KeepsEarlierDeadline(t *testing.T) {
+	request, err := http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatalf(`http.NewRequest("GET", "http://example.com", nil failed with %v; want success`, err)
+	}
+	const acceptableError = 50 * time.Millisecond
+	for _, spec := range []struct {
+		deadline time.Duration
+		timeout  string
+		want     time.Duration
+	}{
+		{deadline: time.Second, timeout: "1H", want: time.Second},
+		{deadline: time.Hour, timeout: "1S", want: time.Second},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), spec.deadline)
+		request.Header.Set("Grpc-Timeout", spec.timeout)
+		annotated, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(), request)
+		cancel()
+		if err != nil {
+			t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			continue
+		}
+		deadline, ok := annotated.Deadline()
+		if !ok {
+			t.Errorf("annotated.Deadline() = _, false; want _, true; timeout = %q", spec.timeout)
+		}
+		if got, want := deadline.Sub(time.Now()), spec.want; got-want > acceptableError || got-want < -acceptableError {
+			t.Errorf("deadline.Sub(time.Now()) = %v; want %v; with error %v; deadline = %v, timeout = %q", got, want, acceptableError, spec.deadline, spec.timeout)
+		}
+	}
+}
+
+func TestAnnotateContext_DeadlineSource(t *testing.T) {
+	defer func(d time.Duration) { runtime.DefaultContextTimeout = d }(runtime.DefaultContextTimeout)
+	runtime.DefaultContextTimeout = 0
+
+	for _, spec := range []struct {
+		name           string
+		deadline       time.Duration
+		defaultTimeout time.Duration
+		timeout        string
+		want           string
+	}{
+		{name: "none"},
+		{name: "header", timeout: "1S", want: runtime.DeadlineFromHeader},
+		{name: "default", defaultTimeout: time.Second, want: runtime.DeadlineFromDefault},
+		{name: "context", deadline: time.Second, want: runtime.DeadlineFromContext},
+		{name: "context before header", deadline: time.Second, timeout: "1H", want: runtime.DeadlineFromContext},
+		{name: "header before context", deadline: time.Hour, timeout: "1S", want: runtime.DeadlineFromHeader},
+	} {
+		ctx := context.Background()
+		if spec.deadline != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, spec.deadline)
+			defer cancel()
+		}
+		runtime.DefaultContextTimeout = spec.defaultTimeout
+		request := httptest.NewRequest("GET", "http://example.com", nil)
+		if spec.timeout != "" {
+			request.Header.Set("Grpc-Timeout", spec.timeout)
+		}
+		annotated, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(), request)
+		if err != nil {
+			t.Errorf("%s: runtime.AnnotateContext(ctx, %#v) failed with %v; want success", spec.name, request, err)
+			continue
+		}
+		got, ok := runtime.DeadlineSource(annotated)
+		if got != spec.want || ok != (spec.want != "") {
+			t.Errorf("%s: runtime.DeadlineSource(annotated) = %q, %v; want %q", spec.name, got, ok, spec.want)
+		}
+	}
+}
+
+func TestAnnotateContext_MaxMetadataPairs(t *testing.T) {
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest("GET", "http://example.com", nil)
+		request.Header.Add("Grpc-Metadata-Foo", "bar")
+		request.Header.Add("Grpc-Metadata-Foo", "baz")
+		return request
+	}
+	annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), newRequest())
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, mux, req) failed with %v; want success", err)
+	}
+	md, _ := metadata.FromOutgoingContext(annotated)
+	var n int
+	for _, vs := range md {
+		n += len(vs)
+	}
+	extra := func(context.Context, *http.Request) metadata.MD { return metadata.Pairs("extra", "1") }
+
+	for _, spec := range []struct {
+		name     string
+		opts     []runtime.ServeMuxOption
+		incoming bool
+		wantErr  bool
+	}{
+		{name: "at the limit", opts: []runtime.ServeMuxOption{runtime.WithMaxMetadataPairs(n)}},
+		{name: "headers over the limit", opts: []runtime.ServeMuxOption{runtime.WithMaxMetadataPairs(n - 1)}, wantErr: true},
+		{name: "annotators over the limit", opts: []runtime.ServeMuxOption{runtime.WithMaxMetadataPairs(n), runtime.WithMetadata(extra)}, wantErr: true},
+		{name: "incoming metadata not preserved", opts: []runtime.ServeMuxOption{runtime.WithMaxMetadataPairs(n)}, incoming: true},
+		{
+			name:     "preserved incoming metadata over the limit",
+			opts:     []runtime.ServeMuxOption{runtime.WithMaxMetadataPairs(n), runtime.WithPreserveIncomingMetadata()},
+			incoming: true,
+			wantErr:  true,
+		},
+	} {
+		ctx := context.Background()
+		if spec.incoming {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-upstream", "hop1"))
+		}
+		_, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(spec.opts...), newRequest())
+		if !spec.wantErr {
+			if err != nil {
+				t.Errorf("%s: runtime.AnnotateContext(ctx, mux, req) failed with %v; want success", spec.name, err)
+			}
+			continue
+		}
+		if got, want := status.Code(err), codes.InvalidArgument; got != want {
+			t.Errorf("%s: runtime.AnnotateContext(ctx, mux, req) failed with %v; want code %v", spec.name, err, want)
+		}
+	}
+}
+
+func TestAnnotateContext_MaxMetadataBytes(t *testing.T) {
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest("GET", "http://example.com", nil)
+		// "AAAA" decodes to 3 bytes.
+		request.Header.Add("Grpc-Metadata-Foo-Bin", "AAAA")
+		return request
+	}
+	annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), newRequest())
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, mux, req) failed with %v; want success", err)
+	}
+	md, _ := metadata.FromOutgoingContext(annotated)
+	var size int
+	for k, vs := range md {
+		for _, v := range vs {
+			size += len(k) + len(v)
+		}
+	}
+	if got, want := md.Get("foo-bin"), []string{"\x00\x00\x00"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf(`md.Get("foo-bin") = %q; want %q`, got, want)
+	}
+
+	if _, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithMaxMetadataBytes(size)), newRequest()); err != nil {
+		t.Errorf("runtime.AnnotateContext(ctx, mux, req) failed with %v at the limit; want success", err)
+	}
+	_, err = runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithMaxMetadataBytes(size-1)), newRequest())
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("runtime.AnnotateContext(ctx, mux, req) failed with %v over the limit; want code %v", err, want)
+	}
+
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-upstream", "hop1"))
+	_, err = runtime.AnnotateContext(incoming, runtime.NewServeMux(runtime.WithMaxMetadataBytes(size), runtime.WithPreserveIncomingMetadata()), newRequest())
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("runtime.AnnotateContext(ctx, mux, req) failed with %v over the limit with preserved incoming metadata; want code %v", err, want)
+	}
+}
+
 func TestAnnotateContext_SupportsCustomAnnotators(t *testing.T) {
 	md1 := func(context.Context, *http.Request) metadata.MD { return metadata.New(map[string]string{"foo": "bar"}) }
 	md2 := func(context.Context, *http.Request) metadata.MD { return metadata.New(map[string]string{"baz": "qux"}) }
@@ -216,6 +495,29 @@ func TestAnnotateContext_SupportsCustomAnnotators(t *testing.T) {
 	}
 }
 
+func TestAnnotateContext_SupportsChainedAnnotators(t *testing.T) {
+	user := func(context.Context, *http.Request) metadata.MD { return metadata.Pairs("x-user", "alice") }
+	sign := func(_ context.Context, _ *http.Request, acc metadata.MD) metadata.MD {
+		return metadata.Pairs("x-signature", "signed:"+strings.Join(acc["x-user"], ","))
+	}
+	request, err := http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatalf(`http.NewRequest("GET", "http://example.com", nil failed with %v; want success`, err)
+	}
+	annotated, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(runtime.WithMetadata(user), runtime.WithChainedMetadata(sign)), request)
+	if err != nil {
+		t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+		return
+	}
+	md, _ := metadata.FromOutgoingContext(annotated)
+	if got, want := md["x-signature"], []string{"signed:alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`md["x-signature"] = %q; want %q`, got, want)
+	}
+	if got, want := md["x-user"], []string{"alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`md["x-user"] = %q; want %q`, got, want)
+	}
+}
+
 func TestAnnotateIncomingContext_WorksWithEmpty(t *testing.T) {
 	ctx := context.Background()
 
@@ -320,6 +622,50 @@ func TestAnnotateIncomingContext_XForwardedFor(t *testing.T) {
 	}
 }
 
+func TestNewTestContext(t *testing.T) {
+	matcher := func(key string) (string, bool) {
+		if key == "X-User" {
+			return "x-user", true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}
+	request := httptest.NewRequest("GET", "http://www.example.com", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	request.Header.Set("X-User", "alice")
+
+	for _, spec := range []struct {
+		name     string
+		ctx      context.Context
+		metadata func(context.Context) (metadata.MD, bool)
+	}{
+		{
+			name:     "outgoing",
+			ctx:      runtime.NewTestContext(request, runtime.WithIncomingHeaderMatcher(matcher)),
+			metadata: metadata.FromOutgoingContext,
+		},
+		{
+			name:     "incoming",
+			ctx:      runtime.NewTestIncomingContext(request, runtime.WithIncomingHeaderMatcher(matcher)),
+			metadata: metadata.FromIncomingContext,
+		},
+	} {
+		md, ok := spec.metadata(spec.ctx)
+		if !ok {
+			t.Errorf("%s: no metadata in context", spec.name)
+			continue
+		}
+		if got, want := md["authorization"], []string{"Bearer token"}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`%s: md["authorization"] = %q; want %q`, spec.name, got, want)
+		}
+		if got, want := md["x-user"], []string{"alice"}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`%s: md["x-user"] = %q; want %q`, spec.name, got, want)
+		}
+		if got, want := md["x-forwarded-host"], []string{"www.example.com"}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`%s: md["x-forwarded-host"] = %q; want %q`, spec.name, got, want)
+		}
+	}
+}
+
 func TestAnnotateIncomingContext_SupportsTimeouts(t *testing.T) {
 	// While run all test, TestAnnotateContext_SupportsTimeouts() will change the DefaultContextTimeout, so reset it to zero.
 	runtime.DefaultContextTimeout = 0 * time.Second
@@ -418,3 +764,719 @@ func TestAnnotateIncomingContext_SupportsCustomAnnotators(t *testing.T) {
 		}
 	}
 }
+
+// annotateThroughMux dispatches request through a ServeMux built with opts and
+// returns the context annotated by the handler of the route "/foo".
+func annotateThroughMux(t *testing.T, request *http.Request, opts ...runtime.ServeMuxOption) (context.Context, error) {
+	t.Helper()
+	mux := runtime.NewServeMux(opts...)
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	var (
+		annotated context.Context
+		aerr      error
+	)
+	mux.Handle(request.Method, pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		annotated, aerr = runtime.AnnotateContext(r.Context(), mux, r)
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), request)
+	if annotated == nil && aerr == nil {
+		t.Fatalf("handler for %q was not invoked", request.URL.Path)
+	}
+	return annotated, aerr
+}
+
+func TestAnnotateContext_StreamingDeadlineExempt(t *testing.T) {
+	defer func(d time.Duration) { runtime.DefaultContextTimeout = d }(runtime.DefaultContextTimeout)
+	runtime.DefaultContextTimeout = 10 * time.Second
+
+	request, err := http.NewRequest("GET", "http://example.com/foo", nil)
+	if err != nil {
+		t.Fatalf(`http.NewRequest("GET", "http://example.com/foo", nil) failed with %v; want success`, err)
+	}
+	annotated, err := annotateThroughMux(t, request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	if _, ok := annotated.Deadline(); !ok {
+		t.Errorf("annotated.Deadline() = _, false; want _, true")
+	}
+
+	annotated, err = annotateThroughMux(t, request, runtime.WithStreamingDeadlineExempt("/foo"))
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	if _, ok := annotated.Deadline(); ok {
+		t.Errorf("annotated.Deadline() = _, true; want _, false for an exempt route")
+	}
+
+	request.Header.Set("Grpc-Timeout", "1S")
+	annotated, err = annotateThroughMux(t, request, runtime.WithStreamingDeadlineExempt("/foo"))
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	deadline, ok := annotated.Deadline()
+	if !ok {
+		t.Fatalf("annotated.Deadline() = _, false; want _, true with an explicit Grpc-Timeout")
+	}
+	if got := time.Until(deadline); got > time.Second {
+		t.Errorf("time.Until(deadline) = %v; want at most %v", got, time.Second)
+	}
+}
+
+func TestAnnotateContext_TimeoutHeader(t *testing.T) {
+	defer func(d time.Duration) { runtime.DefaultContextTimeout = d }(runtime.DefaultContextTimeout)
+	runtime.DefaultContextTimeout = time.Hour
+
+	for _, spec := range []struct {
+		name        string
+		precedence  runtime.TimeoutHeaderPrecedence
+		grpcTimeout string
+		header      string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{name: "header only", header: "5s", want: 5 * time.Second},
+		{name: "grpc-timeout first", grpcTimeout: "2S", header: "5s", want: 2 * time.Second},
+		{name: "header first", precedence: runtime.TimeoutHeaderFirst, grpcTimeout: "2S", header: "5s", want: 5 * time.Second},
+		{name: "header first without header", precedence: runtime.TimeoutHeaderFirst, grpcTimeout: "2S", want: 2 * time.Second},
+		{name: "neither", want: time.Hour},
+		{name: "invalid", header: "5 seconds", wantErr: true},
+		{name: "negative", header: "-5s", wantErr: true},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://example.com/foo", nil)
+			if err != nil {
+				t.Fatalf(`http.NewRequest("GET", "http://example.com/foo", nil) failed with %v; want success`, err)
+			}
+			if spec.grpcTimeout != "" {
+				request.Header.Set("Grpc-Timeout", spec.grpcTimeout)
+			}
+			if spec.header != "" {
+				request.Header.Set("X-Request-Timeout", spec.header)
+			}
+			annotated, err := annotateThroughMux(t, request, runtime.WithTimeoutHeader("X-Request-Timeout", spec.precedence))
+			if spec.wantErr {
+				if got, want := status.Code(err), codes.InvalidArgument; got != want {
+					t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want code %v", request, err, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+			deadline, ok := annotated.Deadline()
+			if !ok {
+				t.Fatalf("annotated.Deadline() = _, false; want _, true")
+			}
+			if got := time.Until(deadline); got > spec.want || got < spec.want-time.Second {
+				t.Errorf("time.Until(deadline) = %v; want about %v", got, spec.want)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_CallOptions(t *testing.T) {
+	ctx := context.Background()
+	request, err := http.NewRequest("GET", "http://www.example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+	}
+	request.Header.Add("X-Tenant", "big")
+
+	perRequest := grpc.MaxCallRecvMsgSize(1 << 24)
+	mux := runtime.NewServeMux(runtime.WithCallOptions(func(_ context.Context, req *http.Request) []grpc.CallOption {
+		if req.Header.Get("X-Tenant") == "big" {
+			return []grpc.CallOption{perRequest}
+		}
+		return nil
+	}))
+	annotated, err := runtime.AnnotateContext(ctx, mux, request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+
+	extra := grpc.WaitForReady(true)
+	got := runtime.CallOptions(annotated, extra)
+	if want := []grpc.CallOption{perRequest, extra}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runtime.CallOptions(annotated, extra) = %v; want %v", got, want)
+	}
+	if got := runtime.CallOptions(ctx, extra); len(got) != 1 {
+		t.Errorf("runtime.CallOptions(ctx, extra) = %v; want only extra", got)
+	}
+}
+
+func TestAnnotateContext_Authority(t *testing.T) {
+	ctx := context.Background()
+	request, err := http.NewRequest("GET", "http://tenant.example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://tenant.example.com", err)
+	}
+
+	mux := runtime.NewServeMux(runtime.WithAuthority(nil))
+	annotated, err := runtime.AnnotateContext(ctx, mux, request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	got, ok := runtime.AuthorityFromCallOptions(runtime.CallOptions(annotated))
+	if want := "tenant.example.com"; !ok || got != want {
+		t.Errorf("runtime.AuthorityFromCallOptions(opts) = %q, %v; want %q, true", got, ok, want)
+	}
+}
+
+func TestAnnotateContext_CookieToAuthorization(t *testing.T) {
+	ctx := context.Background()
+	for _, spec := range []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{
+			name: "cookie",
+			want: []string{"Bearer secret"},
+		},
+		{
+			name:   "header takes precedence",
+			header: "Bearer explicit",
+			want:   []string{"Bearer explicit"},
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://www.example.com", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+			}
+			request.AddCookie(&http.Cookie{Name: "token", Value: "secret"})
+			if spec.header != "" {
+				request.Header.Set("Authorization", spec.header)
+			}
+			mux := runtime.NewServeMux(runtime.WithCookieToAuthorization("token"))
+			annotated, err := runtime.AnnotateContext(ctx, mux, request)
+			if err != nil {
+				t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+			md, _ := metadata.FromOutgoingContext(annotated)
+			if got := md["authorization"]; !reflect.DeepEqual(got, spec.want) {
+				t.Errorf(`md["authorization"] = %q; want %q`, got, spec.want)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_WithoutAuthorizationForwarding(t *testing.T) {
+	opts := []runtime.ServeMuxOption{
+		runtime.WithoutAuthorizationForwarding("/foo"),
+		runtime.WithBasicAuthMetadata(),
+	}
+	for _, spec := range []struct {
+		path string
+		want []string
+	}{
+		{path: "/foo", want: nil},
+		{path: "/foo/bar", want: []string{"Basic dXNlcjpwYXNz"}},
+	} {
+		request, err := http.NewRequest("GET", "http://example.com"+spec.path, nil)
+		if err != nil {
+			t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://example.com"+spec.path, err)
+		}
+		request.SetBasicAuth("user", "pass")
+		mux := runtime.NewServeMux(opts...)
+		var invoked bool
+		for _, pat := range []runtime.Pattern{
+			runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")),
+			runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1}, []string{"foo", "bar"}, "")),
+		} {
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				invoked = true
+				annotated, err := runtime.AnnotateContext(r.Context(), mux, r)
+				if err != nil {
+					t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", r, err)
+				}
+				md, _ := metadata.FromOutgoingContext(annotated)
+				if got := md["authorization"]; !reflect.DeepEqual(got, spec.want) {
+					t.Errorf(`md["authorization"] = %q for %s; want %q`, got, spec.path, spec.want)
+				}
+				if got, want := len(md["grpcgateway-authorization"]), len(spec.want); got != want {
+					t.Errorf(`len(md["grpcgateway-authorization"]) = %d for %s; want %d`, got, spec.path, want)
+				}
+				if got, want := len(md["x-basic-user"]), len(spec.want); got != want {
+					t.Errorf(`len(md["x-basic-user"]) = %d for %s; want %d`, got, spec.path, want)
+				}
+			})
+		}
+		mux.ServeHTTP(httptest.NewRecorder(), request)
+		if !invoked {
+			t.Errorf("handler for %q was not invoked", spec.path)
+		}
+	}
+}
+
+func TestAnnotateContext_MetadataPrefix(t *testing.T) {
+	ctx := context.Background()
+	request, err := http.NewRequest("GET", "http://www.example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+	}
+	request.Header.Add("Authorization", "Token 1234567890")
+	request.Header.Add("Accept-Language", "en")
+	request.Header.Add("Grpc-Metadata-Foo", "bar")
+
+	annotated, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(runtime.WithMetadataPrefix("edge-")), request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	md, _ := metadata.FromOutgoingContext(annotated)
+	for key, want := range map[string][]string{
+		"edge-authorization":          {"Token 1234567890"},
+		"edge-accept-language":        {"en"},
+		"foo":                         {"bar"},
+		"authorization":               {"Token 1234567890"},
+		"grpcgateway-authorization":   nil,
+		"grpcgateway-accept-language": nil,
+	} {
+		if got := md[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("md[%q] = %q; want %q", key, got, want)
+		}
+	}
+}
+
+func TestAnnotateContext_CookieMatcher(t *testing.T) {
+	ctx := context.Background()
+	request, err := http.NewRequest("GET", "http://www.example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+	}
+	request.Header.Add("Cookie", "session=abc; bucket=a%2Fb; bucket=c; other=x")
+
+	mux := runtime.NewServeMux(runtime.WithCookieMatcher(func(name string) (string, bool) {
+		switch name {
+		case "session", "bucket":
+			return "cookie-" + name, true
+		}
+		return "", false
+	}))
+	annotated, err := runtime.AnnotateContext(ctx, mux, request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	md, _ := metadata.FromOutgoingContext(annotated)
+	for key, want := range map[string][]string{
+		"cookie-session": {"abc"},
+		"cookie-bucket":  {"a/b", "c"},
+		"cookie-other":   nil,
+	} {
+		if got := md[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("md[%q] = %q; want %q", key, got, want)
+		}
+	}
+}
+
+func TestAnnotateContext_BasicAuthMetadata(t *testing.T) {
+	ctx := context.Background()
+	for _, spec := range []struct {
+		name     string
+		auth     string
+		wantUser []string
+		wantPass []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			auth:     "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:s3cr:et")),
+			wantUser: []string{"alice"},
+			wantPass: []string{"s3cr:et"},
+		},
+		{
+			name: "bearer",
+			auth: "Bearer token",
+		},
+		{
+			name:    "invalid base64",
+			auth:    "Basic !!!",
+			wantErr: true,
+		},
+		{
+			name:    "missing separator",
+			auth:    "Basic " + base64.StdEncoding.EncodeToString([]byte("alice")),
+			wantErr: true,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://www.example.com", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+			}
+			request.Header.Set("Authorization", spec.auth)
+			mux := runtime.NewServeMux(runtime.WithBasicAuthMetadata())
+			annotated, err := runtime.AnnotateContext(ctx, mux, request)
+			if spec.wantErr {
+				if status.Code(err) != codes.Unauthenticated {
+					t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want Unauthenticated", request, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+			md, _ := metadata.FromOutgoingContext(annotated)
+			if got := md["x-basic-user"]; !reflect.DeepEqual(got, spec.wantUser) {
+				t.Errorf(`md["x-basic-user"] = %q; want %q`, got, spec.wantUser)
+			}
+			if got := md["x-basic-pass"]; !reflect.DeepEqual(got, spec.wantPass) {
+				t.Errorf(`md["x-basic-pass"] = %q; want %q`, got, spec.wantPass)
+			}
+			if got := md["authorization"]; len(got) != 1 || got[0] != spec.auth {
+				t.Errorf(`md["authorization"] = %q; want [%q]`, got, spec.auth)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_PreserveIncomingMetadata(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-upstream", "hop1", "foobar", "upstream"))
+	for _, spec := range []struct {
+		name         string
+		opts         []runtime.ServeMuxOption
+		wantUpstream []string
+		wantFoobar   []string
+	}{
+		{
+			name:       "default",
+			wantFoobar: []string{"Value1"},
+		},
+		{
+			name:         "preserve",
+			opts:         []runtime.ServeMuxOption{runtime.WithPreserveIncomingMetadata()},
+			wantUpstream: []string{"hop1"},
+			wantFoobar:   []string{"upstream", "Value1"},
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://www.example.com", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+			}
+			request.Header.Add("Grpc-Metadata-FooBar", "Value1")
+			for name, annotate := range map[string]func(context.Context, *runtime.ServeMux, *http.Request) (context.Context, error){
+				"AnnotateContext":         runtime.AnnotateContext,
+				"AnnotateIncomingContext": runtime.AnnotateIncomingContext,
+			} {
+				annotated, err := annotate(ctx, runtime.NewServeMux(spec.opts...), request)
+				if err != nil {
+					t.Fatalf("runtime.%s(ctx, %#v) failed with %v; want success", name, request, err)
+				}
+				var md metadata.MD
+				if name == "AnnotateContext" {
+					md, _ = metadata.FromOutgoingContext(annotated)
+				} else {
+					md, _ = metadata.FromIncomingContext(annotated)
+				}
+				if got := md["x-upstream"]; !reflect.DeepEqual(got, spec.wantUpstream) {
+					t.Errorf(`runtime.%s: md["x-upstream"] = %q; want %q`, name, got, spec.wantUpstream)
+				}
+				if got := md["foobar"]; !reflect.DeepEqual(got, spec.wantFoobar) {
+					t.Errorf(`runtime.%s: md["foobar"] = %q; want %q`, name, got, spec.wantFoobar)
+				}
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_LowercasesMetadataKeys(t *testing.T) {
+	ctx := context.Background()
+	matcher := func(key string) (string, bool) {
+		if strings.HasPrefix(key, "X-Custom-") {
+			return key, true
+		}
+		return "", false
+	}
+	annotator := func(context.Context, *http.Request) metadata.MD {
+		return metadata.MD{"X-Custom-Key": []string{"annotated"}}
+	}
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(matcher),
+		runtime.WithMetadata(annotator),
+	)
+	for _, key := range []string{"x-custom-key", "X-CUSTOM-KEY", "X-Custom-Key"} {
+		request, err := http.NewRequest("GET", "http://www.example.com", nil)
+		if err != nil {
+			t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+		}
+		request.Header[key] = []string{"header"}
+		annotated, err := runtime.AnnotateContext(ctx, mux, request)
+		if err != nil {
+			t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+		}
+		md, _ := metadata.FromOutgoingContext(annotated)
+		for k := range md {
+			if k != strings.ToLower(k) {
+				t.Errorf("md has key %q; want lowercase keys only", k)
+			}
+		}
+		if got, want := md["x-custom-key"], []string{"header", "annotated"}; !reflect.DeepEqual(got, want) {
+			t.Errorf(`header %q: md["x-custom-key"] = %q; want %q`, key, got, want)
+		}
+	}
+}
+
+func TestAnnotateContext_HTTPRequestMetadata(t *testing.T) {
+	for _, spec := range []struct {
+		name        string
+		opts        []runtime.ServeMuxOption
+		wantMethod  []string
+		wantPattern []string
+	}{
+		{
+			name:        "default",
+			wantMethod:  []string{"DELETE"},
+			wantPattern: []string{"/v1/{name=*}"},
+		},
+		{
+			name: "disabled",
+			opts: []runtime.ServeMuxOption{runtime.WithDisableHTTPRequestMetadata()},
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(spec.opts...)
+			pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 1}, []string{"v1", "name"}, ""))
+			var md metadata.MD
+			mux.Handle("DELETE", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				annotated, err := runtime.AnnotateContext(r.Context(), mux, r)
+				if err != nil {
+					t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", r, err)
+				}
+				md, _ = metadata.FromOutgoingContext(annotated)
+			})
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "http://www.example.com/v1/foo", nil))
+
+			if got := md["x-http-method"]; !reflect.DeepEqual(got, spec.wantMethod) {
+				t.Errorf(`md["x-http-method"] = %q; want %q`, got, spec.wantMethod)
+			}
+			if got := md["x-http-path-pattern"]; !reflect.DeepEqual(got, spec.wantPattern) {
+				t.Errorf(`md["x-http-path-pattern"] = %q; want %q`, got, spec.wantPattern)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_BinaryHeaderDecoder(t *testing.T) {
+	ctx := context.Background()
+	binData := []byte("\xfb\xff-binary-data")
+	for _, spec := range []struct {
+		name    string
+		opts    []runtime.ServeMuxOption
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			value: base64.StdEncoding.EncodeToString(binData),
+		},
+		{
+			name:    "default rejects URL alphabet",
+			value:   base64.URLEncoding.EncodeToString(binData),
+			wantErr: true,
+		},
+		{
+			name:  "custom",
+			opts:  []runtime.ServeMuxOption{runtime.WithBinaryHeaderDecoder(base64.URLEncoding.DecodeString)},
+			value: base64.URLEncoding.EncodeToString(binData),
+		},
+		{
+			name:  "nil restores default",
+			opts:  []runtime.ServeMuxOption{runtime.WithBinaryHeaderDecoder(base64.URLEncoding.DecodeString), runtime.WithBinaryHeaderDecoder(nil)},
+			value: base64.RawStdEncoding.EncodeToString(binData),
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://www.example.com", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+			}
+			request.Header.Add("Grpc-Metadata-Test-Bin", spec.value)
+			annotated, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(spec.opts...), request)
+			if spec.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want InvalidArgument", request, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+			md, _ := metadata.FromOutgoingContext(annotated)
+			if got, want := md["test-bin"], []string{string(binData)}; !reflect.DeepEqual(got, want) {
+				t.Errorf(`md["test-bin"] = %q want %q`, got, want)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_DuplicateTimeouts(t *testing.T) {
+	ctx := context.Background()
+	for _, spec := range []struct {
+		name     string
+		timeouts []string
+		wantErr  bool
+	}{
+		{
+			name:     "identical",
+			timeouts: []string{"1S", "1S"},
+		},
+		{
+			name:     "conflicting",
+			timeouts: []string{"1S", "2S"},
+			wantErr:  true,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "http://www.example.com", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest(%q, %q, nil) failed with %v; want success", "GET", "http://www.example.com", err)
+			}
+			for _, tm := range spec.timeouts {
+				request.Header.Add("Grpc-Timeout", tm)
+			}
+			annotated, err := runtime.AnnotateContext(ctx, runtime.NewServeMux(), request)
+			if spec.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want InvalidArgument", request, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+			if _, ok := annotated.Deadline(); !ok {
+				t.Errorf("annotated.Deadline() = _, false; want _, true")
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_DeadlineFromRequestStart(t *testing.T) {
+	const (
+		timeout = time.Second
+		delay   = 100 * time.Millisecond
+	)
+	for _, spec := range []struct {
+		name   string
+		opts   []runtime.ServeMuxOption
+		header string
+		value  string
+	}{
+		{name: "grpc-timeout", header: "Grpc-Timeout", value: "1S"},
+		{
+			name:   "timeout header",
+			opts:   []runtime.ServeMuxOption{runtime.WithTimeoutHeader("X-Request-Timeout", runtime.GrpcTimeoutFirst)},
+			header: "X-Request-Timeout",
+			value:  "1s",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(spec.opts...)
+			pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+			var remaining time.Duration
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				// Work done before the context is annotated.
+				time.Sleep(delay)
+				annotated, err := runtime.AnnotateContext(r.Context(), mux, r)
+				if err != nil {
+					t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", r, err)
+				}
+				deadline, ok := annotated.Deadline()
+				if !ok {
+					t.Fatalf("annotated.Deadline() = _, false; want _, true")
+				}
+				remaining = time.Until(deadline)
+			})
+			req := httptest.NewRequest("GET", "http://www.example.com/foo", nil)
+			req.Header.Set(spec.header, spec.value)
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if remaining > timeout-delay {
+				t.Errorf("remaining time until the deadline = %v; want at most %v", remaining, timeout-delay)
+			}
+		})
+	}
+}
+
+func TestAnnotateContext_DeadlineReleasedWithRequest(t *testing.T) {
+	request, err := http.NewRequest("GET", "http://example.com/foo", nil)
+	if err != nil {
+		t.Fatalf(`http.NewRequest("GET", "http://example.com/foo", nil) failed with %v; want success`, err)
+	}
+	request.Header.Set("Grpc-Timeout", "10S")
+	annotated, err := annotateThroughMux(t, request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	if got, want := annotated.Err(), context.Canceled; got != want {
+		t.Errorf("annotated.Err() = %v once the mux served the request; want %v", got, want)
+	}
+
+	// Outside of a ServeMux, the deadline is released with the context of the request.
+	ctx, cancel := context.WithCancel(context.Background())
+	request = request.WithContext(ctx)
+	annotated, err = runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), request)
+	if err != nil {
+		t.Fatalf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+	}
+	cancel()
+	select {
+	case <-annotated.Done():
+	case <-time.After(time.Second):
+		t.Errorf("annotated.Done() not closed once the request context was canceled")
+	}
+}
+
+// corruptedSpanTracer fails to extract the span context of every request.
+type corruptedSpanTracer struct {
+	opentracing.NoopTracer
+}
+
+func (corruptedSpanTracer) Extract(interface{}, interface{}) (opentracing.SpanContext, error) {
+	return nil, opentracing.ErrSpanContextCorrupted
+}
+
+func TestAnnotateContext_SpanContext(t *testing.T) {
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	for _, spec := range []struct {
+		name    string
+		tracer  opentracing.Tracer
+		headers map[string]string
+		wantErr bool
+	}{
+		{name: "noop tracer", tracer: opentracing.NoopTracer{}},
+		{name: "no tracing headers", tracer: mocktracer.New()},
+		{
+			name:    "tracing headers",
+			tracer:  mocktracer.New(),
+			headers: map[string]string{"Mockpfx-Ids-Traceid": "1", "Mockpfx-Ids-Spanid": "2"},
+		},
+		{name: "corrupted span context", tracer: corruptedSpanTracer{}, wantErr: true},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			opentracing.SetGlobalTracer(spec.tracer)
+			request, err := http.NewRequest("GET", "http://example.com/foo", nil)
+			if err != nil {
+				t.Fatalf(`http.NewRequest("GET", "http://example.com/foo", nil) failed with %v; want success`, err)
+			}
+			for k, v := range spec.headers {
+				request.Header.Set(k, v)
+			}
+			_, err = runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), request)
+			if spec.wantErr {
+				if got, want := status.Code(err), codes.InvalidArgument; got != want {
+					t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want code %v", request, err, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("runtime.AnnotateContext(ctx, %#v) failed with %v; want success", request, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeGrpcTimeout(t *testing.T) {
+	for _, spec := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "0n"},
+		{d: -time.Second, want: "0n"},
+		{d: time.Nanosecond, want: "1n"},
+		{d: 2 * time.Hour, want: "2H"},
+		{d: 90 * time.Minute, want: "90M"},
+		{d: 1500 * time.Millisecond, want: "1500m"},
+		{d: 10 * time.Second, want: "10S"},
+		{d: 1234567 * time.Microsecond, want: "1234567u"},
+		{d: 99999999 * time.Nanosecond, want: "99999999n"},
+		{d: 100000001 * time.Nanosecond, want: "100001u"},
+		{d: 200 * 24 * time.Hour, want: "4800H"},
+		{d: 100000000*time.Second + time.Nanosecond, want: "1666667M"},
+	} {
+		if got := EncodeGrpcTimeout(spec.d); got != spec.want {
+			t.Errorf("EncodeGrpcTimeout(%v) = %q; want %q", spec.d, got, spec.want)
+		}
+	}
+}
+
+func TestEncodeGrpcTimeoutRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		time.Nanosecond,
+		999 * time.Nanosecond,
+		time.Microsecond,
+		17 * time.Millisecond,
+		time.Second,
+		61 * time.Second,
+		19 * time.Minute,
+		17 * time.Hour,
+		99999999 * time.Nanosecond,
+		123456789 * time.Nanosecond,
+		100000000*time.Second + time.Nanosecond,
+	} {
+		s := EncodeGrpcTimeout(d)
+		if len(s) > 9 {
+			t.Errorf("EncodeGrpcTimeout(%v) = %q; want at most 8 digits and a unit", d, s)
+		}
+		got, err := timeoutDecode(s)
+		if err != nil {
+			t.Errorf("timeoutDecode(%q) failed with %v; want success", s, err)
+			continue
+		}
+		if got < d {
+			t.Errorf("timeoutDecode(EncodeGrpcTimeout(%v)) = %v; want at least %v", d, got, d)
+		}
+		if d <= 99999999*time.Nanosecond && got != d {
+			t.Errorf("timeoutDecode(EncodeGrpcTimeout(%v)) = %v; want %v", d, got, d)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package runtime
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -227,21 +229,52 @@ func Duration(val string) (*duration.Duration, error) {
 // Enum converts the given string into an int32 that should be type casted into the
 // correct enum proto type.
 func Enum(val string, enumValMap map[string]int32) (int32, error) {
-	e, ok := enumValMap[val]
-	if ok {
+	return resolveEnum(val, enumValMap, false)
+}
+
+// EnumContext is like Enum, but also accepts the names of the enum values regardless of case
+// if the mux of the request ctx comes from was created with WithCaseInsensitiveEnums.
+func EnumContext(ctx context.Context, val string, enumValMap map[string]int32) (int32, error) {
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	return resolveEnum(val, enumValMap, mux != nil && mux.caseInsensitiveEnums)
+}
+
+// resolveEnum returns the number of the enum value in enumValMap which val is the name or the
+// number of. It compares the names regardless of case if caseInsensitive is set.
+func resolveEnum(val string, enumValMap map[string]int32, caseInsensitive bool) (int32, error) {
+	if e, ok := enumValMap[val]; ok {
 		return e, nil
 	}
-
-	i, err := Int32(val)
-	if err != nil {
-		return 0, fmt.Errorf("%s is not valid", val)
+	if caseInsensitive {
+		for name, e := range enumValMap {
+			if strings.EqualFold(name, val) {
+				return e, nil
+			}
+		}
 	}
-	for _, v := range enumValMap {
-		if v == i {
-			return i, nil
+	if i, err := strconv.ParseInt(val, 10, 32); err == nil {
+		for _, v := range enumValMap {
+			if v == int32(i) {
+				return v, nil
+			}
 		}
 	}
-	return 0, fmt.Errorf("%s is not valid", val)
+	return 0, fmt.Errorf("%s is not valid, want one of %s or their numbers", val, strings.Join(enumNames(enumValMap), ", "))
+}
+
+// enumNames returns the names in enumValMap in the order of their numbers.
+func enumNames(enumValMap map[string]int32) []string {
+	names := make([]string, 0, len(enumValMap))
+	for name := range enumValMap {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if vi, vj := enumValMap[names[i]], enumValMap[names[j]]; vi != vj {
+			return vi < vj
+		}
+		return names[i] < names[j]
+	})
+	return names
 }
 
 // EnumSlice converts 'val' where individual enums are separated by 'sep'
@@ -260,6 +293,20 @@ func EnumSlice(val, sep string, enumValMap map[string]int32) ([]int32, error) {
 	return values, nil
 }
 
+// EnumSliceContext is like EnumSlice, but resolves the individual enums like EnumContext.
+func EnumSliceContext(ctx context.Context, val, sep string, enumValMap map[string]int32) ([]int32, error) {
+	s := strings.Split(val, sep)
+	values := make([]int32, len(s))
+	for i, v := range s {
+		value, err := EnumContext(ctx, v, enumValMap)
+		if err != nil {
+			return values, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
 /*
 	Support fot google.protobuf.wrappers on top of primitive types
 */
@@ -2,19 +2,28 @@ package runtime
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
 // HTTPStatusFromCode converts a gRPC error code into the corresponding HTTP response status.
 // See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
 func HTTPStatusFromCode(code codes.Code) int {
+	return httpStatusFromCode(nil, code)
+}
+
+// httpStatusFromCode is HTTPStatusFromCode, logging unknown codes to the Logger of mux.
+func httpStatusFromCode(mux *ServeMux, code codes.Code) int {
 	switch code {
 	case codes.OK:
 		return http.StatusOK
@@ -53,7 +62,7 @@ func HTTPStatusFromCode(code codes.Code) int {
 		return http.StatusInternalServerError
 	}
 
-	grpclog.Infof("Unknown gRPC error code: %v", code)
+	loggerOf(mux).Infof("Unknown gRPC error code: %v", code)
 	return http.StatusInternalServerError
 }
 
@@ -86,7 +95,7 @@ func (*errorBody) ProtoMessage()    {}
 //
 // The response body returned by this function is a JSON object,
 // which contains a member whose key is "error" and whose value is err.Error().
-func DefaultHTTPError(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+func DefaultHTTPError(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, r *http.Request, err error) {
 	const fallback = `{"error": "failed to marshal error message"}`
 
 	s, ok := status.FromError(err)
@@ -115,28 +124,32 @@ func DefaultHTTPError(ctx context.Context, mux *ServeMux, marshaler Marshaler, w
 
 	buf, merr := marshaler.Marshal(body)
 	if merr != nil {
-		grpclog.Infof("Failed to marshal error message %q: %v", body, merr)
+		loggerOf(mux).Infof("Failed to marshal error message %q: %v", body, merr)
 		w.WriteHeader(http.StatusInternalServerError)
 		if _, err := io.WriteString(w, fallback); err != nil {
-			grpclog.Infof("Failed to write response: %v", err)
+			loggerOf(mux).Infof("Failed to write response: %v", err)
 		}
 		return
 	}
 
 	md, ok := ServerMetadataFromContext(ctx)
 	if !ok {
-		grpclog.Infof("Failed to extract ServerMetadata from context")
+		loggerOf(mux).Infof("Failed to extract ServerMetadata from context")
 	}
 
 	handleForwardResponseServerMetadata(w, mux, md)
-	handleForwardResponseTrailerHeader(w, md)
-	st := HTTPStatusFromCode(s.Code())
+	handleForwardResponseTrailerHeader(w, mux, md)
+	handleRetryAfter(w, s)
+	handleStatusDetails(w, mux, s)
+	handleDeadlineDiagnostics(ctx, w, mux, r, s.Code())
+	recordCode(r, s.Code())
+	st := httpStatusForError(mux, r, s.Code())
 	w.WriteHeader(st)
 	if _, err := w.Write(buf); err != nil {
-		grpclog.Infof("Failed to write response: %v", err)
+		loggerOf(mux).Infof("Failed to write response: %v", err)
 	}
 
-	handleForwardResponseTrailer(w, md)
+	handleForwardResponseTrailer(w, mux, md)
 }
 
 // DefaultOtherErrorHandler is the default implementation of OtherErrorHandler.
@@ -144,3 +157,95 @@ func DefaultHTTPError(ctx context.Context, mux *ServeMux, marshaler Marshaler, w
 func DefaultOtherErrorHandler(w http.ResponseWriter, _ *http.Request, msg string, code int) {
 	http.Error(w, msg, code)
 }
+
+// RateLimitExceeded returns a codes.ResourceExhausted error asking the client to retry after d.
+// The default error handlers reply to it with a Retry-After header.
+func RateLimitExceeded(d time.Duration) error {
+	s, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(d),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return s.Err()
+}
+
+// handleRetryAfter sets the Retry-After header from the google.rpc.RetryInfo detail of s, if any,
+// rounding the delay up to whole seconds.
+func handleRetryAfter(w http.ResponseWriter, s *status.Status) {
+	for _, detail := range s.Details() {
+		info, ok := detail.(*errdetails.RetryInfo)
+		if !ok || info.GetRetryDelay() == nil {
+			continue
+		}
+		d, err := ptypes.Duration(info.GetRetryDelay())
+		if err != nil || d < 0 {
+			continue
+		}
+		w.Header().Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+		return
+	}
+}
+
+// statusDetailsHeader is the header set by handleStatusDetails.
+const statusDetailsHeader = "Grpc-Status-Details-Bin"
+
+// handleStatusDetails sets the statusDetailsHeader to the binary encoding of s in base64 if the mux
+// forwards status details, see WithStatusDetailsHeader, and s has details.
+func handleStatusDetails(w http.ResponseWriter, mux *ServeMux, s *status.Status) {
+	if mux == nil || !mux.forwardStatusDetails || len(s.Proto().GetDetails()) == 0 {
+		return
+	}
+	buf, err := proto.Marshal(s.Proto())
+	if err != nil {
+		loggerOf(mux).Infof("Failed to marshal status details: %v", err)
+		return
+	}
+	w.Header().Set(statusDetailsHeader, base64.RawStdEncoding.EncodeToString(buf))
+}
+
+// handleDeadlineDiagnostics sets the headers described in WithDeadlineDiagnostics if the mux enables them
+// and code is codes.DeadlineExceeded. The deadline is the one annotating r gave to the call, as reported by
+// DeadlineSource; if r was not annotated, it is the one its headers and the default timeout of mux would give.
+func handleDeadlineDiagnostics(ctx context.Context, w http.ResponseWriter, mux *ServeMux, r *http.Request, code codes.Code) {
+	if code != codes.DeadlineExceeded || mux == nil || !mux.deadlineDiagnostics || r == nil {
+		return
+	}
+	call, ok := deadlineOfCall(ctx, r)
+	if !ok {
+		timeout, fromClient, err := requestTimeout(mux, r)
+		if err != nil || timeout == 0 {
+			return
+		}
+		call = &callDeadline{source: DeadlineFromDefault, timeout: timeout}
+		if fromClient {
+			call.source = DeadlineFromHeader
+		}
+	}
+	w.Header().Set("Grpc-Gateway-Deadline-Source", call.source)
+	w.Header().Set("Grpc-Gateway-Deadline-Timeout", call.timeout.String())
+}
+
+// RecoveryHandlerFunc converts a value recovered from a panic while serving a request into the error
+// replied to the client.
+type RecoveryHandlerFunc func(ctx context.Context, p interface{}) error
+
+// DefaultRecoveryHandler replies with a codes.Internal error without exposing the recovered value to the client.
+func DefaultRecoveryHandler(_ context.Context, _ interface{}) error {
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// httpStatusForError is HTTPStatusFromCode, except that a codes.FailedPrecondition reply to a request with
+// an If-Match header maps to http.StatusPreconditionFailed when the mux is configured to do so, and that
+// the codes in the table set with WithErrorStatusTable map to their statuses there.
+func httpStatusForError(mux *ServeMux, r *http.Request, code codes.Code) int {
+	if code == codes.FailedPrecondition && mux != nil && mux.ifMatchPreconditionFailed && r != nil && r.Header.Get("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	if mux != nil {
+		if st, ok := mux.errorStatusTable[code]; ok {
+			return st
+		}
+	}
+	return httpStatusFromCode(mux, code)
+}
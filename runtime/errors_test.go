@@ -2,15 +2,20 @@ package runtime_test
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/ninnemana/grpc-gateway/runtime"
+	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -85,3 +90,217 @@ func TestDefaultHTTPError(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultHTTPErrorIfMatchPreconditionFailed(t *testing.T) {
+	ctx := context.Background()
+	err := status.Error(codes.FailedPrecondition, "etag mismatch")
+	for _, spec := range []struct {
+		name    string
+		opts    []runtime.ServeMuxOption
+		ifMatch string
+		status  int
+	}{
+		{
+			name:    "disabled",
+			ifMatch: `"v1"`,
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:   "no If-Match",
+			opts:   []runtime.ServeMuxOption{runtime.WithIfMatchPreconditionFailed()},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "If-Match",
+			opts:    []runtime.ServeMuxOption{runtime.WithIfMatchPreconditionFailed()},
+			ifMatch: `"v1"`,
+			status:  http.StatusPreconditionFailed,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "http://example.com/foo", nil)
+			if spec.ifMatch != "" {
+				req.Header.Set("If-Match", spec.ifMatch)
+			}
+			runtime.DefaultHTTPError(ctx, runtime.NewServeMux(spec.opts...), &runtime.JSONPb{}, w, req, err)
+			if got, want := w.Code, spec.status; got != want {
+				t.Errorf("w.Code = %d; want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestErrorStatusTable(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithErrorStatusTable(map[codes.Code]int{codes.NotFound: http.StatusGone, codes.Unavailable: http.StatusBadGateway}),
+		runtime.WithErrorStatusTable(map[codes.Code]int{codes.Unavailable: http.StatusServiceUnavailable}),
+	)
+	for _, spec := range []struct {
+		code   codes.Code
+		status int
+	}{
+		{code: codes.NotFound, status: http.StatusGone},
+		{code: codes.Unavailable, status: http.StatusServiceUnavailable},
+		{code: codes.PermissionDenied, status: http.StatusForbidden},
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		runtime.DefaultHTTPError(context.Background(), mux, &runtime.JSONPb{}, w, req, status.Error(spec.code, "error"))
+		if got, want := w.Code, spec.status; got != want {
+			t.Errorf("w.Code = %d for %v; want %d", got, spec.code, want)
+		}
+	}
+
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := runtime.NewServerMetadataContext(r.Context(), runtime.ServerMetadata{})
+		recv := func() (proto.Message, error) { return nil, status.Error(codes.NotFound, "gone") }
+		runtime.ForwardResponseStream(ctx, mux, &runtime.JSONPb{OrigName: true}, w, r, recv)
+	})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/foo", nil))
+	if got, want := w.Body.String(), `{"error":{"grpc_code":5,"http_code":410,"message":"gone","http_status":"Gone"}}`+"\n"; got != want {
+		t.Errorf("w.Body = %q for a stream error; want %q", got, want)
+	}
+}
+
+func TestStatusDetailsHeader(t *testing.T) {
+	s, err := status.New(codes.FailedPrecondition, "failed precondition").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "TOS", Subject: "user"}},
+	})
+	if err != nil {
+		t.Fatalf("status.WithDetails failed with %v; want success", err)
+	}
+	decode := func(v string) *spb.Status {
+		b, err := base64.RawStdEncoding.DecodeString(v)
+		if err != nil {
+			t.Fatalf("base64.RawStdEncoding.DecodeString(%q) failed with %v; want success", v, err)
+		}
+		got := new(spb.Status)
+		if err := proto.Unmarshal(b, got); err != nil {
+			t.Fatalf("proto.Unmarshal failed with %v; want success", err)
+		}
+		return got
+	}
+
+	mux := runtime.NewServeMux(runtime.WithStatusDetailsHeader())
+	for _, handler := range []runtime.ProtoErrorHandlerFunc{runtime.DefaultHTTPError, runtime.DefaultHTTPProtoErrorHandler} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		handler(context.Background(), mux, &runtime.JSONPb{}, w, req, s.Err())
+		if got := decode(w.Header().Get("Grpc-Status-Details-Bin")); !proto.Equal(got, s.Proto()) {
+			t.Errorf("Grpc-Status-Details-Bin = %v; want %v", got, s.Proto())
+		}
+
+		w = httptest.NewRecorder()
+		handler(context.Background(), mux, &runtime.JSONPb{}, w, req, status.Error(codes.NotFound, "not found"))
+		if got := w.Header().Get("Grpc-Status-Details-Bin"); got != "" {
+			t.Errorf("Grpc-Status-Details-Bin = %q for an error without details; want none", got)
+		}
+
+		w = httptest.NewRecorder()
+		handler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, req, s.Err())
+		if got := w.Header().Get("Grpc-Status-Details-Bin"); got != "" {
+			t.Errorf("Grpc-Status-Details-Bin = %q without WithStatusDetailsHeader; want none", got)
+		}
+	}
+
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := runtime.NewServerMetadataContext(r.Context(), runtime.ServerMetadata{})
+		recv := func() (proto.Message, error) { return nil, s.Err() }
+		runtime.ForwardResponseStream(ctx, mux, &runtime.JSONPb{OrigName: true}, w, r, recv)
+	})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/foo", nil))
+	if got := decode(w.Result().Trailer.Get("Grpc-Status-Details-Bin")); !proto.Equal(got, s.Proto()) {
+		t.Errorf("Grpc-Status-Details-Bin trailer = %v; want %v", got, s.Proto())
+	}
+}
+
+func TestDefaultHTTPErrorDeadlineDiagnostics(t *testing.T) {
+	defer func(d time.Duration) { runtime.DefaultContextTimeout = d }(runtime.DefaultContextTimeout)
+	runtime.DefaultContextTimeout = 10 * time.Second
+
+	ctx := context.Background()
+	for _, spec := range []struct {
+		name        string
+		opts        []runtime.ServeMuxOption
+		err         error
+		grpcTimeout string
+		wantSource  string
+		wantTimeout string
+	}{
+		{
+			name: "disabled",
+			err:  status.Error(codes.DeadlineExceeded, "too slow"),
+		},
+		{
+			name:        "server default",
+			opts:        []runtime.ServeMuxOption{runtime.WithDeadlineDiagnostics()},
+			err:         status.Error(codes.DeadlineExceeded, "too slow"),
+			wantSource:  runtime.DeadlineFromDefault,
+			wantTimeout: "10s",
+		},
+		{
+			name:        "client timeout",
+			opts:        []runtime.ServeMuxOption{runtime.WithDeadlineDiagnostics()},
+			err:         status.Error(codes.DeadlineExceeded, "too slow"),
+			grpcTimeout: "500m",
+			wantSource:  runtime.DeadlineFromHeader,
+			wantTimeout: "500ms",
+		},
+		{
+			name:        "other code",
+			opts:        []runtime.ServeMuxOption{runtime.WithDeadlineDiagnostics()},
+			err:         status.Error(codes.Unavailable, "down"),
+			grpcTimeout: "500m",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+			if spec.grpcTimeout != "" {
+				req.Header.Set("Grpc-Timeout", spec.grpcTimeout)
+			}
+			runtime.DefaultHTTPError(ctx, runtime.NewServeMux(spec.opts...), &runtime.JSONPb{}, w, req, spec.err)
+			if got := w.Header().Get("Grpc-Gateway-Deadline-Source"); got != spec.wantSource {
+				t.Errorf("Grpc-Gateway-Deadline-Source = %q; want %q", got, spec.wantSource)
+			}
+			if got := w.Header().Get("Grpc-Gateway-Deadline-Timeout"); got != spec.wantTimeout {
+				t.Errorf("Grpc-Gateway-Deadline-Timeout = %q; want %q", got, spec.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestDeadlineDiagnosticsTighterContextDeadline(t *testing.T) {
+	const tighter = 300 * time.Millisecond
+	mux := runtime.NewServeMux(runtime.WithDeadlineDiagnostics())
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx, cancel := context.WithTimeout(r.Context(), tighter)
+		defer cancel()
+		if _, err := runtime.AnnotateContext(ctx, mux, r); err != nil {
+			t.Fatalf("runtime.AnnotateContext failed with %v; want success", err)
+		}
+		// Like generated handlers, reply with the unannotated context.
+		runtime.DefaultHTTPError(r.Context(), mux, &runtime.JSONPb{}, w, r, status.Error(codes.DeadlineExceeded, "too slow"))
+	})
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	req.Header.Set("Grpc-Timeout", "10S")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if got, want := w.Header().Get("Grpc-Gateway-Deadline-Source"), runtime.DeadlineFromContext; got != want {
+		t.Errorf("Grpc-Gateway-Deadline-Source = %q; want %q", got, want)
+	}
+	timeout, err := time.ParseDuration(w.Header().Get("Grpc-Gateway-Deadline-Timeout"))
+	if err != nil || timeout < tighter || timeout > tighter+time.Second {
+		t.Errorf("Grpc-Gateway-Deadline-Timeout = %q; want about %v", w.Header().Get("Grpc-Gateway-Deadline-Timeout"), tighter)
+	}
+}
@@ -1,13 +1,18 @@
 package runtime
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"reflect"
 	"strings"
 
 	descriptor2 "github.com/golang/protobuf/descriptor"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func translateName(name string, md *descriptor.DescriptorProto) (string, *descriptor.DescriptorProto) {
@@ -79,4 +84,130 @@ type fieldMaskPathItem struct {
 
 	// descriptor for parent message
 	md *descriptor.DescriptorProto
+
+	// Go type of the message node is decoded into, used by fieldMaskFromBody
+	typ reflect.Type
+}
+
+// PopulateFieldMask sets the google.protobuf.FieldMask field of msg named with WithAutoFieldMask on the
+// ServeMux which dispatched the request whose context is ctx, to the paths of the fields present in the
+// JSON request body returned by newReader. bodyField is the name of the field of msg the body is bound
+// to, or "*" if it is bound to msg itself; in that case the mask field itself is left out of the paths.
+//
+// It does nothing if the mux has no such option, msg has no such field, the field is already set to a
+// non-empty mask, or the body is bound to a field which is not a message.
+//
+// This is used by generated code for PATCH requests.
+func PopulateFieldMask(ctx context.Context, msg proto.Message, bodyField string, newReader func() io.Reader) error {
+	mux, ok := ctx.Value(serveMuxKey{}).(*ServeMux)
+	if !ok || mux.autoFieldMask == "" {
+		return nil
+	}
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	mask, ok := protoField(v, mux.autoFieldMask)
+	if !ok || !isFieldMask(mask.Type()) {
+		return nil
+	}
+	if !mask.IsNil() && mask.Elem().FieldByName("Paths").Len() != 0 {
+		return nil
+	}
+
+	body := msg
+	if bodyField != "*" {
+		f, ok := protoField(v, bodyField)
+		if !ok || f.Kind() != reflect.Ptr {
+			return nil
+		}
+		if f.IsNil() {
+			body, ok = reflect.New(f.Type().Elem()).Interface().(proto.Message)
+		} else {
+			body, ok = f.Interface().(proto.Message)
+		}
+		if !ok {
+			return nil
+		}
+	}
+	fm, err := fieldMaskFromBody(newReader(), reflect.TypeOf(body))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	paths := fm.GetPaths()
+	if bodyField == "*" {
+		paths = paths[:0]
+		for _, p := range fm.GetPaths() {
+			if p != mux.autoFieldMask && !strings.HasPrefix(p, mux.autoFieldMask+".") {
+				paths = append(paths, p)
+			}
+		}
+	}
+	m := reflect.New(mask.Type().Elem())
+	m.Elem().FieldByName("Paths").Set(reflect.ValueOf(paths))
+	mask.Set(m)
+	return nil
+}
+
+// fieldMaskFromBody is like FieldMaskFromRequestBody but resolves each JSON key to its proto
+// field name with the Go type t of the message the body is decoded into, so that message fields
+// whose types are defined outside of the body's message are translated too.
+// Map fields are leaves since their keys are not field names.
+func fieldMaskFromBody(r io.Reader, t reflect.Type) (*field_mask.FieldMask, error) {
+	fm := &field_mask.FieldMask{}
+	var root interface{}
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		if err == io.EOF {
+			return fm, nil
+		}
+		return nil, err
+	}
+
+	queue := []fieldMaskPathItem{{node: root, typ: t}}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		m, ok := item.node.(map[string]interface{})
+		if ok && (item.typ == nil || item.typ.Kind() != reflect.Map) {
+			for k, v := range m {
+				name, typ := lookupField(item.typ, k)
+				if name == "" {
+					name = k
+				}
+				// Copy the path so that siblings do not share its backing array.
+				path := append(append([]string(nil), item.path...), name)
+				queue = append(queue, fieldMaskPathItem{path: path, node: v, typ: typ})
+			}
+		} else if len(item.path) > 0 {
+			fm.Paths = append(fm.Paths, strings.Join(item.path, "."))
+		}
+	}
+	return fm, nil
+}
+
+// protoField returns the field of the generated message struct v whose proto name is name.
+func protoField(v reflect.Value, name string) (reflect.Value, bool) {
+	props := proto.GetProperties(v.Type())
+	for i, p := range props.Prop {
+		if p.OrigName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// isFieldMask reports whether t is a pointer to a google.protobuf.FieldMask message type,
+// whichever Go package it was generated into.
+func isFieldMask(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	m, ok := reflect.Zero(t).Interface().(proto.Message)
+	if !ok || proto.MessageName(m) != "google.protobuf.FieldMask" {
+		return false
+	}
+	f, ok := t.Elem().FieldByName("Paths")
+	return ok && f.Type == reflect.TypeOf([]string(nil))
 }
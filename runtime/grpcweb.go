@@ -0,0 +1,366 @@
+package runtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	grpcWebContentType = "application/grpc-web"
+
+	// grpcWebTrailerFlag marks a frame which holds the trailers of the response instead of a message.
+	grpcWebTrailerFlag = 0x80
+	// grpcWebCompressedFlag marks a frame whose message is compressed.
+	grpcWebCompressedFlag = 0x01
+
+	// defaultGRPCWebMaxMessageBytes is the largest gRPC-Web request message accepted from a mux
+	// without WithMaxRequestBodyBytes, like the default limit of a gRPC server.
+	defaultGRPCWebMaxMessageBytes = 4 << 20
+)
+
+// WithGRPCWeb returns a ServeMuxOption that serves gRPC-Web requests, as sent by browser gRPC-Web
+// clients, for the unary and server-streaming methods registered by generated code. They are served
+// at the path of the gRPC method, e.g. "/pkg.Service/Method", next to the HTTP bindings.
+//
+// Messages of "application/grpc-web" and "application/grpc-web+proto" requests are in the protobuf
+// format. Those of "application/grpc-web+json" requests are marshaled with the marshaler registered
+// for "application/json", or for "*" if there is none. The base64 "application/grpc-web-text" variant
+// and compressed messages are not supported.
+//
+// Request bodies are limited by WithMaxRequestBodyBytes like other requests, and a request message
+// longer than that limit, or than 4 MiB without it, is rejected with codes.InvalidArgument.
+func WithGRPCWeb() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.grpcWeb = true
+	}
+}
+
+// HandleGRPCWeb registers h to serve the gRPC-Web requests for fullMethod, the name of a gRPC method
+// such as "/pkg.Service/Method", on a ServeMux created with WithGRPCWeb.
+//
+// This is used by generated code.
+func (s *ServeMux) HandleGRPCWeb(fullMethod string, h http.HandlerFunc) {
+	if s.grpcWebHandlers == nil {
+		s.grpcWebHandlers = make(map[string]http.HandlerFunc)
+	}
+	s.grpcWebHandlers[fullMethod] = h
+}
+
+// serveGRPCWeb serves r if it is a gRPC-Web request, and reports whether it was.
+func (s *ServeMux) serveGRPCWeb(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+	if !s.grpcWeb || r.Method != "POST" {
+		return false
+	}
+	if _, ok := grpcWebMarshaler(s, r); !ok {
+		return false
+	}
+	if st := requestStateFromContext(ctx); st != nil {
+		st.pattern = r.URL.Path
+	}
+	ct, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	ctx = context.WithValue(ctx, inboundContentTypeKey{}, ct)
+	ctx = context.WithValue(ctx, outboundContentTypeKey{}, ct)
+	r = r.WithContext(context.WithValue(ctx, serveMuxKey{}, s))
+	if s.maxRequestBodyBytes > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBodyBytes)
+	}
+	h, ok := s.grpcWebHandlers[r.URL.Path]
+	if !ok {
+		gw := newGRPCWebWriter(s, w, r)
+		gw.writeHeader(nil)
+		gw.writeTrailer(status.Errorf(codes.Unimplemented, "unknown method %s", r.URL.Path), nil)
+		return true
+	}
+	h(w, r)
+	return true
+}
+
+// grpcWebMarshaler returns the marshaler for the messages of the gRPC-Web request r, or false if r
+// is not a gRPC-Web request which the ServeMux supports.
+func grpcWebMarshaler(mux *ServeMux, r *http.Request) (Marshaler, bool) {
+	ct, _, err := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	if err != nil {
+		return nil, false
+	}
+	switch ct {
+	case grpcWebContentType, grpcWebContentType + "+proto":
+		return &ProtoMarshaller{}, true
+	case grpcWebContentType + "+json":
+		if m, ok := mux.marshalers.mimeMap["application/json"]; ok {
+			return m, true
+		}
+		return mux.marshalers.mimeMap[MIMEWildcard], true
+	}
+	return nil, false
+}
+
+// ServeGRPCWebUnary serves the gRPC-Web request req for a unary method. It decodes the request message
+// into protoReq and makes the gRPC call with call, which must pass opts on to the client. Like the
+// HTTP bindings, the request goes through the interceptors set with WithGatewayInterceptor and the
+// call through WrapCall.
+//
+// This is used by generated code.
+func ServeGRPCWebUnary(ctx context.Context, mux *ServeMux, w http.ResponseWriter, req *http.Request, protoReq proto.Message, call func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error)) {
+	serveGRPCWeb(ctx, mux, w, req, func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+		gw := newGRPCWebWriter(mux, w, req)
+		if err := decodeGRPCWeb(ctx, mux, req, gw.marshaler, protoReq); err != nil {
+			return err
+		}
+		var md ServerMetadata
+		resp, err := WrapCall(ctx, req, func() (proto.Message, error) {
+			return call(ctx, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+		})
+		gw.writeHeader(md.HeaderMD)
+		if err == nil {
+			err = gw.writeMessage(ctx, resp)
+		}
+		gw.writeTrailer(err, md.TrailerMD)
+		return nil
+	})
+}
+
+// ServeGRPCWebStream serves the gRPC-Web request req for a server-streaming method. It decodes the
+// request message into protoReq and starts the gRPC call with call, which returns the stream and a
+// function receiving its next message. Like the HTTP bindings, the request goes through the
+// interceptors set with WithGatewayInterceptor.
+//
+// This is used by generated code.
+func ServeGRPCWebStream(ctx context.Context, mux *ServeMux, w http.ResponseWriter, req *http.Request, protoReq proto.Message, call func(ctx context.Context) (grpc.ClientStream, func() (proto.Message, error), error)) {
+	serveGRPCWeb(ctx, mux, w, req, func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+		gw := newGRPCWebWriter(mux, w, req)
+		if err := decodeGRPCWeb(ctx, mux, req, gw.marshaler, protoReq); err != nil {
+			return err
+		}
+		stream, recv, err := call(ctx)
+		if err != nil {
+			return err
+		}
+		header, err := stream.Header()
+		gw.writeHeader(header)
+		for err == nil {
+			var msg proto.Message
+			if msg, err = recv(); err == nil {
+				err = gw.writeMessage(ctx, msg)
+			}
+		}
+		if err == io.EOF {
+			err = nil
+		}
+		gw.writeTrailer(err, stream.Trailer())
+		return nil
+	})
+}
+
+// serveGRPCWeb annotates ctx for the gRPC call of the gRPC-Web request req and runs handler
+// through the interceptors of mux. An error from either is replied as the status of the call.
+func serveGRPCWeb(ctx context.Context, mux *ServeMux, w http.ResponseWriter, req *http.Request, handler GatewayHandler) {
+	ctx, err := AnnotateContext(ctx, mux, req)
+	if err == nil {
+		// The request validator and the interceptors find the mux in the context of the call.
+		ctx = context.WithValue(ctx, serveMuxKey{}, mux)
+		err = Intercept(ctx, mux, w, req, handler)
+	}
+	if err != nil {
+		gw := newGRPCWebWriter(mux, w, req)
+		gw.writeHeader(nil)
+		gw.writeTrailer(err, nil)
+	}
+}
+
+// decodeGRPCWeb decodes the message of the gRPC-Web request req into protoReq, then transforms
+// and validates it with the hooks of mux.
+func decodeGRPCWeb(ctx context.Context, mux *ServeMux, req *http.Request, marshaler Marshaler, protoReq proto.Message) error {
+	var hdr [5]byte
+	if _, err := io.ReadFull(req.Body, hdr[:]); err != nil {
+		return status.Errorf(codes.InvalidArgument, "malformed gRPC-Web request: %v", err)
+	}
+	if hdr[0]&grpcWebCompressedFlag != 0 {
+		return status.Error(codes.Unimplemented, "compressed gRPC-Web messages are not supported")
+	}
+	// Check the length the client declares before allocating the message.
+	limit := int64(defaultGRPCWebMaxMessageBytes)
+	if mux.maxRequestBodyBytes > 0 {
+		limit = mux.maxRequestBodyBytes
+	}
+	n := int64(binary.BigEndian.Uint32(hdr[1:]))
+	if n > limit {
+		return status.Errorf(codes.InvalidArgument, "gRPC-Web message of %d bytes exceeds the limit of %d bytes", n, limit)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(req.Body, buf); err != nil {
+		return status.Errorf(codes.InvalidArgument, "malformed gRPC-Web request: %v", err)
+	}
+	if err := marshaler.Unmarshal(buf, protoReq); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := TransformRequest(ctx, req, protoReq); err != nil {
+		return err
+	}
+	return ValidateRequest(ctx, protoReq)
+}
+
+// grpcWebWriter writes the frames of a gRPC-Web response.
+type grpcWebWriter struct {
+	mux         *ServeMux
+	w           http.ResponseWriter
+	r           *http.Request
+	marshaler   Marshaler
+	contentType string
+}
+
+func newGRPCWebWriter(mux *ServeMux, w http.ResponseWriter, r *http.Request) *grpcWebWriter {
+	marshaler, _ := grpcWebMarshaler(mux, r)
+	ct, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	return &grpcWebWriter{mux: mux, w: w, r: r, marshaler: marshaler, contentType: ct}
+}
+
+// writeHeader writes the response header with the header metadata md. gRPC-Web always replies with
+// http.StatusOK and reports the status of the call in the trailers.
+func (g *grpcWebWriter) writeHeader(md metadata.MD) {
+	h := g.w.Header()
+	for k, vs := range md {
+		for _, v := range vs {
+			h.Add(k, grpcWebMetadataValue(k, v))
+		}
+	}
+	h.Set("Content-Type", g.contentType)
+	g.w.WriteHeader(http.StatusOK)
+}
+
+func (g *grpcWebWriter) writeMessage(ctx context.Context, msg proto.Message) error {
+	msg, err := transformResponse(ctx, g.mux, msg)
+	if err != nil {
+		return err
+	}
+	buf, err := g.marshaler.Marshal(msg)
+	if err != nil {
+		loggerOf(g.mux).Infof("Marshal error: %v", err)
+		return status.Errorf(codes.Internal, "failed to marshal response: %v", err)
+	}
+	return g.writeFrame(0, buf)
+}
+
+// writeTrailer writes the frame with the status of the call, from err, and the trailer metadata md.
+func (g *grpcWebWriter) writeTrailer(err error, md metadata.MD) {
+	s, ok := status.FromError(err)
+	if !ok {
+		s = status.New(codes.Unknown, err.Error())
+	}
+	recordCode(g.r, s.Code())
+
+	trailers := DefaultGRPCWebTrailers
+	if g.mux != nil && g.mux.grpcWebTrailers != nil {
+		trailers = g.mux.grpcWebTrailers
+	}
+	if err := g.writeFrame(grpcWebTrailerFlag, encodeGRPCWebTrailers(trailers(g.r, s, md))); err != nil {
+		loggerOf(g.mux).Infof("Failed to write response: %v", err)
+	}
+}
+
+func (g *grpcWebWriter) writeFrame(flag byte, buf []byte) error {
+	var hdr [5]byte
+	hdr[0] = flag
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(buf)))
+	if _, err := g.w.Write(append(hdr[:], buf...)); err != nil {
+		return err
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// GRPCWebTrailerFunc returns the trailers of the gRPC-Web response to req, whose gRPC call ended with
+// the status s and the trailer metadata md. The values of "-bin" keys are sent in base64.
+type GRPCWebTrailerFunc func(req *http.Request, s *status.Status, md metadata.MD) metadata.MD
+
+// WithGRPCWebTrailers returns a ServeMuxOption that sets the function computing the trailers of the gRPC-Web
+// responses of the mux, e.g. to add diagnostic trailers to those of DefaultGRPCWebTrailers.
+func WithGRPCWebTrailers(fn GRPCWebTrailerFunc) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.grpcWebTrailers = fn
+	}
+}
+
+// DefaultGRPCWebTrailers is the default GRPCWebTrailerFunc. It returns "grpc-status", "grpc-message" if s has a
+// message, "grpc-status-details-bin" if s has details, and md.
+func DefaultGRPCWebTrailers(_ *http.Request, s *status.Status, md metadata.MD) metadata.MD {
+	trailers := metadata.Pairs("grpc-status", strconv.Itoa(int(s.Code())))
+	if msg := s.Message(); msg != "" {
+		trailers.Set("grpc-message", encodeGrpcMessage(msg))
+	}
+	if len(s.Proto().GetDetails()) != 0 {
+		if buf, err := proto.Marshal(s.Proto()); err == nil {
+			trailers.Set("grpc-status-details-bin", string(buf))
+		}
+	}
+	return metadata.Join(trailers, md)
+}
+
+// grpcWebStatusTrailers are the trailers which encodeGRPCWebTrailers puts first, in this order.
+var grpcWebStatusTrailers = []string{"grpc-status", "grpc-message", "grpc-status-details-bin"}
+
+// encodeGRPCWebTrailers encodes md as the body of a gRPC-Web trailer frame: a "key: value" line for
+// each value, with lowercase keys, the status trailers first and the others sorted.
+func encodeGRPCWebTrailers(md metadata.MD) []byte {
+	md = lowercaseKeys(md)
+	var b strings.Builder
+	write := func(k string) {
+		for _, v := range md[k] {
+			fmt.Fprintf(&b, "%s: %s\r\n", k, grpcWebMetadataValue(k, v))
+		}
+	}
+	var keys []string
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range grpcWebStatusTrailers {
+		write(k)
+	}
+	for _, k := range keys {
+		switch k {
+		case "grpc-status", "grpc-message", "grpc-status-details-bin":
+			continue
+		}
+		write(k)
+	}
+	return []byte(b.String())
+}
+
+// grpcWebMetadataValue returns the value v of the metadata key k as sent to gRPC-Web clients, which
+// receive the values of binary keys in base64.
+func grpcWebMetadataValue(k, v string) string {
+	if strings.HasSuffix(strings.ToLower(k), "-bin") {
+		return base64.RawStdEncoding.EncodeToString([]byte(v))
+	}
+	return v
+}
+
+// encodeGrpcMessage percent-encodes the bytes of msg which the gRPC protocol does not allow in the
+// grpc-message trailer.
+func encodeGrpcMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if c := msg[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
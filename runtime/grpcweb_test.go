@@ -0,0 +1,321 @@
+package runtime_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	pb "github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
+	"github.com/ninnemana/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcWebFrame returns buf as a gRPC-Web frame with flag.
+func grpcWebFrame(flag byte, buf []byte) []byte {
+	hdr := []byte{flag, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(buf)))
+	return append(hdr, buf...)
+}
+
+// readGRPCWebFrames splits a gRPC-Web response body into its message frames and its trailer frame.
+func readGRPCWebFrames(t *testing.T, body []byte) (msgs [][]byte, trailer string) {
+	for len(body) > 0 {
+		if len(body) < 5 {
+			t.Fatalf("truncated frame header %q", body)
+		}
+		n := int(binary.BigEndian.Uint32(body[1:5]))
+		if len(body) < 5+n {
+			t.Fatalf("truncated frame %q", body)
+		}
+		if body[0]&0x80 != 0 {
+			trailer = string(body[5 : 5+n])
+		} else {
+			msgs = append(msgs, body[5:5+n])
+		}
+		body = body[5+n:]
+	}
+	return msgs, trailer
+}
+
+func TestGRPCWebUnary(t *testing.T) {
+	for _, spec := range []struct {
+		name        string
+		contentType string
+		marshaler   runtime.Marshaler
+		failures    int
+		wantTrailer string
+	}{
+		{
+			name:        "proto",
+			contentType: "application/grpc-web+proto",
+			marshaler:   &runtime.ProtoMarshaller{},
+			wantTrailer: "grpc-status: 0\r\n",
+		},
+		{
+			name:        "json",
+			contentType: "application/grpc-web+json",
+			marshaler:   &runtime.JSONPb{OrigName: true},
+			wantTrailer: "grpc-status: 0\r\n",
+		},
+		{
+			name:        "error",
+			contentType: "application/grpc-web",
+			marshaler:   &runtime.ProtoMarshaller{},
+			failures:    1,
+			wantTrailer: "grpc-status: 14\r\ngrpc-message: flaky\r\n",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			client := &flakyEchoClient{failures: spec.failures, code: codes.Unavailable}
+			mux := runtime.NewServeMux(runtime.WithGRPCWeb())
+			if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+				t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+			}
+			in := &pb.SimpleMessage{Id: "foo", Num: 1}
+			buf, err := spec.marshaler.Marshal(in)
+			if err != nil {
+				t.Fatalf("spec.marshaler.Marshal(%v) failed with %v; want success", in, err)
+			}
+			req := httptest.NewRequest("POST", "http://example.com/grpc.gateway.examples.examplepb.EchoService/Echo", bytes.NewReader(grpcWebFrame(0, buf)))
+			req.Header.Set("Content-Type", spec.contentType)
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if got, want := resp.Code, http.StatusOK; got != want {
+				t.Errorf("resp.Code = %d; want %d", got, want)
+			}
+			if got, want := resp.Header().Get("Content-Type"), spec.contentType; got != want {
+				t.Errorf("resp.Header().Get(%q) = %q; want %q", "Content-Type", got, want)
+			}
+			msgs, trailer := readGRPCWebFrames(t, resp.Body.Bytes())
+			if trailer != spec.wantTrailer {
+				t.Errorf("trailer = %q; want %q", trailer, spec.wantTrailer)
+			}
+			if spec.failures != 0 {
+				if len(msgs) != 0 {
+					t.Errorf("len(msgs) = %d; want 0", len(msgs))
+				}
+				return
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("len(msgs) = %d; want 1", len(msgs))
+			}
+			var out pb.SimpleMessage
+			if err := spec.marshaler.Unmarshal(msgs[0], &out); err != nil {
+				t.Fatalf("spec.marshaler.Unmarshal(%q) failed with %v; want success", msgs[0], err)
+			}
+			if !proto.Equal(&out, in) {
+				t.Errorf("out = %v; want %v", &out, in)
+			}
+		})
+	}
+}
+
+func TestGRPCWebUnknownMethod(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithGRPCWeb())
+	req := httptest.NewRequest("POST", "http://example.com/pkg.Service/Missing", bytes.NewReader(grpcWebFrame(0, nil)))
+	req.Header.Set("Content-Type", "application/grpc-web")
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	_, trailer := readGRPCWebFrames(t, resp.Body.Bytes())
+	if want := "grpc-status: 12\r\ngrpc-message: unknown method /pkg.Service/Missing\r\n"; trailer != want {
+		t.Errorf("trailer = %q; want %q", trailer, want)
+	}
+}
+
+func TestGRPCWebOversizedMessage(t *testing.T) {
+	for _, spec := range []struct {
+		name   string
+		opts   []runtime.ServeMuxOption
+		length uint32
+	}{
+		{name: "default limit", length: 0xffffffff},
+		{name: "body limit", opts: []runtime.ServeMuxOption{runtime.WithMaxRequestBodyBytes(16)}, length: 17},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(append(spec.opts, runtime.WithGRPCWeb())...)
+			client := &flakyEchoClient{}
+			if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+				t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+			}
+			// Only the header of the frame is sent: its declared length must be enough to reject it.
+			hdr := []byte{0, 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(hdr[1:], spec.length)
+			req := httptest.NewRequest("POST", "http://example.com/grpc.gateway.examples.examplepb.EchoService/Echo", bytes.NewReader(hdr))
+			req.Header.Set("Content-Type", "application/grpc-web")
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			_, trailer := readGRPCWebFrames(t, resp.Body.Bytes())
+			if !strings.HasPrefix(trailer, "grpc-status: 3\r\n") || !strings.Contains(trailer, "exceeds the limit") {
+				t.Errorf("trailer = %q; want an InvalidArgument status for the message size", trailer)
+			}
+			if client.calls != 0 {
+				t.Errorf("client.calls = %d; want 0", client.calls)
+			}
+		})
+	}
+}
+
+func TestGRPCWebTrailers(t *testing.T) {
+	trailers := func(req *http.Request, s *status.Status, md metadata.MD) metadata.MD {
+		md = metadata.Join(md, metadata.Pairs("X-Request-Id", req.Header.Get("X-Request-Id"), "trace-bin", "\x01\x02"))
+		return runtime.DefaultGRPCWebTrailers(req, s, md)
+	}
+	mux := runtime.NewServeMux(runtime.WithGRPCWeb(), runtime.WithGRPCWebTrailers(trailers))
+	client := &flakyEchoClient{failures: 1, code: codes.Unavailable}
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	req := httptest.NewRequest("POST", "http://example.com/grpc.gateway.examples.examplepb.EchoService/Echo", bytes.NewReader(grpcWebFrame(0, nil)))
+	req.Header.Set("Content-Type", "application/grpc-web")
+	req.Header.Set("X-Request-Id", "abc")
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	_, trailer := readGRPCWebFrames(t, resp.Body.Bytes())
+	if want := "grpc-status: 14\r\ngrpc-message: flaky\r\ntrace-bin: AQI\r\nx-request-id: abc\r\n"; trailer != want {
+		t.Errorf("trailer = %q; want %q", trailer, want)
+	}
+}
+
+type fakeStreamClient struct {
+	pb.StreamServiceClient
+	msgs []*pb.ABitOfEverything
+}
+
+func (c *fakeStreamClient) List(context.Context, *empty.Empty, ...grpc.CallOption) (pb.StreamService_ListClient, error) {
+	return &fakeListClient{msgs: c.msgs}, nil
+}
+
+type fakeListClient struct {
+	grpc.ClientStream
+	msgs []*pb.ABitOfEverything
+}
+
+func (c *fakeListClient) Header() (metadata.MD, error) { return metadata.Pairs("foo", "bar"), nil }
+func (c *fakeListClient) Trailer() metadata.MD         { return metadata.Pairs("baz", "qux") }
+
+func (c *fakeListClient) Recv() (*pb.ABitOfEverything, error) {
+	if len(c.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := c.msgs[0]
+	c.msgs = c.msgs[1:]
+	return msg, nil
+}
+
+func TestGRPCWebServerStream(t *testing.T) {
+	want := []*pb.ABitOfEverything{{Uuid: "foo"}, {Uuid: "bar"}}
+	mux := runtime.NewServeMux(runtime.WithGRPCWeb())
+	if err := pb.RegisterStreamServiceHandlerClient(context.Background(), mux, &fakeStreamClient{msgs: want}); err != nil {
+		t.Fatalf("pb.RegisterStreamServiceHandlerClient failed with %v; want success", err)
+	}
+	req := httptest.NewRequest("POST", "http://example.com/grpc.gateway.examples.examplepb.StreamService/List", bytes.NewReader(grpcWebFrame(0, nil)))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	if got, want := resp.Header().Get("Foo"), "bar"; got != want {
+		t.Errorf("resp.Header().Get(%q) = %q; want %q", "Foo", got, want)
+	}
+	msgs, trailer := readGRPCWebFrames(t, resp.Body.Bytes())
+	if want := "grpc-status: 0\r\nbaz: qux\r\n"; trailer != want {
+		t.Errorf("trailer = %q; want %q", trailer, want)
+	}
+	if len(msgs) != len(want) {
+		t.Fatalf("len(msgs) = %d; want %d", len(msgs), len(want))
+	}
+	for i, buf := range msgs {
+		var msg pb.ABitOfEverything
+		if err := proto.Unmarshal(buf, &msg); err != nil {
+			t.Fatalf("proto.Unmarshal(%q) failed with %v; want success", buf, err)
+		}
+		if !proto.Equal(&msg, want[i]) {
+			t.Errorf("msgs[%d] = %v; want %v", i, &msg, want[i])
+		}
+	}
+}
+
+func TestGRPCWebInterceptorAndCallWrapper(t *testing.T) {
+	var intercepted []string
+	var wrapped int
+	mux := runtime.NewServeMux(
+		runtime.WithGRPCWeb(),
+		runtime.WithGatewayInterceptor(func(next runtime.GatewayHandler) runtime.GatewayHandler {
+			return func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+				intercepted = append(intercepted, req.URL.Path)
+				if req.Header.Get("Authorization") == "" {
+					return status.Error(codes.Unauthenticated, "no credentials")
+				}
+				return next(ctx, w, req)
+			}
+		}),
+		runtime.WithCallWrapper(func(ctx context.Context, invoke func() error) error {
+			wrapped++
+			return invoke()
+		}),
+	)
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	if err := pb.RegisterStreamServiceHandlerClient(context.Background(), mux, &fakeStreamClient{}); err != nil {
+		t.Fatalf("pb.RegisterStreamServiceHandlerClient failed with %v; want success", err)
+	}
+	for _, spec := range []struct {
+		path          string
+		authorization string
+		wantTrailer   string
+		wantWrapped   int
+	}{
+		{
+			path:        "/grpc.gateway.examples.examplepb.EchoService/Echo",
+			wantTrailer: "grpc-status: 16\r\ngrpc-message: no credentials\r\n",
+		},
+		{
+			path:          "/grpc.gateway.examples.examplepb.EchoService/Echo",
+			authorization: "Bearer token",
+			wantTrailer:   "grpc-status: 0\r\n",
+			wantWrapped:   1,
+		},
+		{
+			path:        "/grpc.gateway.examples.examplepb.StreamService/List",
+			wantTrailer: "grpc-status: 16\r\ngrpc-message: no credentials\r\n",
+		},
+		{
+			path:          "/grpc.gateway.examples.examplepb.StreamService/List",
+			authorization: "Bearer token",
+			wantTrailer:   "grpc-status: 0\r\nbaz: qux\r\n",
+		},
+	} {
+		intercepted, wrapped = nil, 0
+		req := httptest.NewRequest("POST", "http://example.com"+spec.path, bytes.NewReader(grpcWebFrame(0, nil)))
+		req.Header.Set("Content-Type", "application/grpc-web")
+		if spec.authorization != "" {
+			req.Header.Set("Authorization", spec.authorization)
+		}
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+
+		if _, trailer := readGRPCWebFrames(t, resp.Body.Bytes()); trailer != spec.wantTrailer {
+			t.Errorf("%s with %q: trailer = %q; want %q", spec.path, spec.authorization, trailer, spec.wantTrailer)
+		}
+		if want := []string{spec.path}; len(intercepted) != 1 || intercepted[0] != spec.path {
+			t.Errorf("%s with %q: intercepted = %q; want %q", spec.path, spec.authorization, intercepted, want)
+		}
+		if wrapped != spec.wantWrapped {
+			t.Errorf("%s with %q: wrapped = %d; want %d", spec.path, spec.authorization, wrapped, spec.wantWrapped)
+		}
+	}
+}
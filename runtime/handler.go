@@ -1,32 +1,65 @@
 package runtime
 
 import (
+	"bytes"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"context"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/ninnemana/grpc-gateway/internal"
-	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var errEmptyResponse = errors.New("empty response")
+var (
+	errEmptyResponse   = errors.New("empty response")
+	errKeepAliveFailed = errors.New("failed to send keep-alive")
+)
+
+// The trailers ForwardResponseStream sets at the end of a complete stream, so that clients can tell
+// it from a truncated one. A stream ended at its deadline by WithPartialStreamOnDeadline also has
+// the Warning streamTruncatedWarning.
+const (
+	streamStatusTrailer  = "Grpc-Status"
+	streamMessageTrailer = "Grpc-Message"
+
+	warningHeader          = "Warning"
+	streamTruncatedWarning = `199 - "response truncated at deadline"`
+)
 
 // ForwardResponseStream forwards the stream from gRPC server to REST client.
+//
+// Each message is written as a {"result": ...} chunk. If the stream fails, the last chunk is an
+// {"error": ...} chunk with the StreamError of the failure, written like the messages:
+//
+//   - for newline-delimited marshalers such as JSONPb and JSONBuiltin, as an object followed by the
+//     delimiter;
+//   - for ProtoMarshaller, as a length-prefixed StreamChunk whose error field is set;
+//   - for other StreamFramer marshalers, such as server-sent events, as a frame of its own.
+//
+// A stream which is not cut short also ends with the HTTP trailer "Grpc-Status", the gRPC code of
+// the stream ("0" if it succeeded), and "Grpc-Message" for a failed stream with a message.
+// A route set up with WithPartialStreamOnDeadline ends with "0" and a Warning when the deadline
+// of the call passes, without an error chunk.
 func ForwardResponseStream(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, req *http.Request, recv func() (proto.Message, error), opts ...func(context.Context, http.ResponseWriter, proto.Message) error) {
 	f, ok := w.(http.Flusher)
 	if !ok {
-		grpclog.Infof("Flush not supported in %T", w)
+		loggerOf(mux).Infof("Flush not supported in %T", w)
 		http.Error(w, "unexpected type of web server", http.StatusInternalServerError)
 		return
 	}
 
 	md, ok := ServerMetadataFromContext(ctx)
 	if !ok {
-		grpclog.Infof("Failed to extract ServerMetadata from context")
+		loggerOf(mux).Infof("Failed to extract ServerMetadata from context")
 		http.Error(w, "unexpected error", http.StatusInternalServerError)
 		return
 	}
@@ -34,52 +67,189 @@ func ForwardResponseStream(ctx context.Context, mux *ServeMux, marshaler Marshal
 
 	w.Header().Set("Transfer-Encoding", "chunked")
 	w.Header().Set("Content-Type", marshaler.ContentType())
-	if err := handleForwardResponseOptions(ctx, w, nil, opts); err != nil {
+	if err := handleForwardResponseOptions(ctx, mux, w, nil, opts); err != nil {
 		HTTPError(ctx, mux, marshaler, w, req, err)
 		return
 	}
 
-	var delimiter []byte
-	if d, ok := marshaler.(Delimited); ok {
-		delimiter = d.Delimiter()
-	} else {
-		delimiter = []byte("\n")
+	w.Header().Add("Trailer", streamStatusTrailer)
+	w.Header().Add("Trailer", streamMessageTrailer)
+	pat, _ := HTTPPathPattern(req.Context())
+	partialOnDeadline := mux.partialStreamOnDeadline[pat]
+	if mux.forwardStatusDetails {
+		w.Header().Add("Trailer", statusDetailsHeader)
+	}
+
+	framer, _ := streamFramerOf(marshaler)
+	delimiter := streamDelimiter(marshaler)
+
+	var keepAlive []byte
+	if ka, ok := marshaler.(StreamKeepAlive); ok {
+		keepAlive = ka.KeepAlive()
+	} else if bytes.Equal(delimiter, []byte("\n")) {
+		// An empty line is ignored by newline-delimited stream readers.
+		keepAlive = delimiter
 	}
 
 	var wroteHeader bool
+	idle := func() error {
+		if len(keepAlive) == 0 {
+			return nil
+		}
+		if _, err := w.Write(keepAlive); err != nil {
+			loggerOf(mux).Infof("Failed to send keep-alive: %v", err)
+			return errKeepAliveFailed
+		}
+		wroteHeader = true
+		f.Flush()
+		return nil
+	}
+	receiver := newStreamReceiver(recv)
+	defer receiver.stop()
 	for {
-		resp, err := recv()
+		resp, err := receiver.recvOrDone(req.Context(), mux.streamKeepAlive, idle)
 		if err == io.EOF {
+			w.Header().Set(streamStatusTrailer, "0")
+			return
+		}
+		if err == errKeepAliveFailed {
 			return
 		}
+		if cerr := req.Context().Err(); cerr != nil {
+			// The client has gone away. Returning lets the caller cancel the
+			// gRPC stream instead of pulling messages into a dead connection.
+			loggerOf(mux).Infof("Client disconnected while streaming: %v", cerr)
+			return
+		}
+		if partialOnDeadline && (ctx.Err() == context.DeadlineExceeded || status.Code(err) == codes.DeadlineExceeded) {
+			recordCode(req, codes.DeadlineExceeded)
+			// The Warning is a header if no message was sent yet, or else a trailer which
+			// is not announced in advance, so that clients only get it once.
+			if wroteHeader {
+				w.Header().Set(http.TrailerPrefix+warningHeader, streamTruncatedWarning)
+			} else {
+				w.Header().Set(warningHeader, streamTruncatedWarning)
+			}
+			w.Header().Set(streamStatusTrailer, "0")
+			return
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			// Stop marshaling messages which the client will never read once the
+			// deadline of the call has passed.
+			handleForwardResponseStreamError(ctx, wroteHeader, marshaler, w, req, mux, status.Error(codes.DeadlineExceeded, ctx.Err().Error()))
+			return
+		}
+		if err == nil && resp != nil {
+			resp, err = transformResponse(ctx, mux, resp)
+		}
 		if err != nil {
 			handleForwardResponseStreamError(ctx, wroteHeader, marshaler, w, req, mux, err)
 			return
 		}
-		if err := handleForwardResponseOptions(ctx, w, resp, opts); err != nil {
+		if err := handleForwardResponseOptions(ctx, mux, w, resp, opts); err != nil {
 			handleForwardResponseStreamError(ctx, wroteHeader, marshaler, w, req, mux, err)
 			return
 		}
 
 		buf, err := marshaler.Marshal(streamChunk(ctx, resp, mux.streamErrorHandler))
 		if err != nil {
-			grpclog.Infof("Failed to marshal response chunk: %v", err)
+			loggerOf(mux).Infof("Failed to marshal response chunk: %v", err)
 			handleForwardResponseStreamError(ctx, wroteHeader, marshaler, w, req, mux, err)
 			return
 		}
+		if framer != nil {
+			buf = framer.Frame(buf)
+		}
 		if _, err = w.Write(buf); err != nil {
-			grpclog.Infof("Failed to send response chunk: %v", err)
+			loggerOf(mux).Infof("Failed to send response chunk: %v", err)
 			return
 		}
 		wroteHeader = true
+		if framer != nil {
+			f.Flush()
+			continue
+		}
 		if _, err = w.Write(delimiter); err != nil {
-			grpclog.Infof("Failed to send delimiter chunk: %v", err)
+			loggerOf(mux).Infof("Failed to send delimiter chunk: %v", err)
 			return
 		}
 		f.Flush()
 	}
 }
 
+type recvResult struct {
+	resp proto.Message
+	err  error
+}
+
+// streamReceiver calls recv on a single goroutine for the whole stream, once for every message
+// asked for with recvOrDone, so that waiting for a message can be abandoned when the request is done.
+type streamReceiver struct {
+	recv    func() (proto.Message, error)
+	started bool
+	next    chan struct{}
+	results chan recvResult
+	done    chan struct{}
+}
+
+func newStreamReceiver(recv func() (proto.Message, error)) *streamReceiver {
+	return &streamReceiver{
+		recv:    recv,
+		next:    make(chan struct{}),
+		results: make(chan recvResult, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func (r *streamReceiver) run() {
+	for {
+		select {
+		case <-r.next:
+		case <-r.done:
+			return
+		}
+		resp, err := r.recv()
+		r.results <- recvResult{resp: resp, err: err}
+	}
+}
+
+// stop ends the goroutine of r once it is no longer blocked in recv.
+func (r *streamReceiver) stop() {
+	close(r.done)
+}
+
+// recvOrDone returns the next message of the stream but returns ctx.Err() as soon as ctx is
+// done, even if recv is still blocked waiting for the message.
+// If keepAlive is positive, idle is called every keepAlive while waiting.
+func (r *streamReceiver) recvOrDone(ctx context.Context, keepAlive time.Duration, idle func() error) (proto.Message, error) {
+	if ctx.Done() == nil && keepAlive <= 0 {
+		return r.recv()
+	}
+	if !r.started {
+		r.started = true
+		go r.run()
+	}
+	r.next <- struct{}{}
+	var tick <-chan time.Time
+	if keepAlive > 0 {
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case res := <-r.results:
+			return res.resp, res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick:
+			if err := idle(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
 func handleForwardResponseServerMetadata(w http.ResponseWriter, mux *ServeMux, md ServerMetadata) {
 	for k, vs := range md.HeaderMD {
 		if h, ok := mux.outgoingHeaderMatcher(k); ok {
@@ -90,18 +260,20 @@ func handleForwardResponseServerMetadata(w http.ResponseWriter, mux *ServeMux, m
 	}
 }
 
-func handleForwardResponseTrailerHeader(w http.ResponseWriter, md ServerMetadata) {
+func handleForwardResponseTrailerHeader(w http.ResponseWriter, mux *ServeMux, md ServerMetadata) {
 	for k := range md.TrailerMD {
-		tKey := textproto.CanonicalMIMEHeaderKey(fmt.Sprintf("%s%s", MetadataTrailerPrefix, k))
-		w.Header().Add("Trailer", tKey)
+		if tKey, ok := mux.outgoingTrailerMatcher(k); ok {
+			w.Header().Add("Trailer", textproto.CanonicalMIMEHeaderKey(tKey))
+		}
 	}
 }
 
-func handleForwardResponseTrailer(w http.ResponseWriter, md ServerMetadata) {
+func handleForwardResponseTrailer(w http.ResponseWriter, mux *ServeMux, md ServerMetadata) {
 	for k, vs := range md.TrailerMD {
-		tKey := fmt.Sprintf("%s%s", MetadataTrailerPrefix, k)
-		for _, v := range vs {
-			w.Header().Add(tKey, v)
+		if tKey, ok := mux.outgoingTrailerMatcher(k); ok {
+			for _, v := range vs {
+				w.Header().Add(tKey, v)
+			}
 		}
 	}
 }
@@ -112,15 +284,41 @@ type responseBody interface {
 	XXX_ResponseBody() interface{}
 }
 
+// transformResponse returns resp as transformed by the hook set with WithResponseTransformer, if any.
+// If resp wraps the response message of a binding with a response body, the hook is given the
+// wrapped message, and the result is wrapped the same way.
+func transformResponse(ctx context.Context, mux *ServeMux, resp proto.Message) (proto.Message, error) {
+	if mux.responseTransformer == nil {
+		return resp, nil
+	}
+	v := reflect.ValueOf(resp)
+	if _, ok := resp.(responseBody); !ok || v.Kind() != reflect.Struct {
+		return mux.responseTransformer(ctx, resp)
+	}
+	msg, err := mux.responseTransformer(ctx, v.Field(0).Interface().(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+	wrapped := reflect.New(v.Type()).Elem()
+	wrapped.Field(0).Set(reflect.ValueOf(msg))
+	return wrapped.Interface().(proto.Message), nil
+}
+
 // ForwardResponseMessage forwards the message "resp" from gRPC server to REST client.
 func ForwardResponseMessage(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, req *http.Request, resp proto.Message, opts ...func(context.Context, http.ResponseWriter, proto.Message) error) {
 	md, ok := ServerMetadataFromContext(ctx)
 	if !ok {
-		grpclog.Infof("Failed to extract ServerMetadata from context")
+		loggerOf(mux).Infof("Failed to extract ServerMetadata from context")
 	}
 
 	handleForwardResponseServerMetadata(w, mux, md)
-	handleForwardResponseTrailerHeader(w, md)
+	handleForwardResponseTrailerHeader(w, mux, md)
+
+	resp, err := transformResponse(ctx, mux, resp)
+	if err != nil {
+		HTTPError(ctx, mux, marshaler, w, req, err)
+		return
+	}
 
 	contentType := marshaler.ContentType()
 	// Check marshaler on run time in order to keep backwards compatability
@@ -131,37 +329,131 @@ func ForwardResponseMessage(ctx context.Context, mux *ServeMux, marshaler Marsha
 	}
 	w.Header().Set("Content-Type", contentType)
 
-	if err := handleForwardResponseOptions(ctx, w, resp, opts); err != nil {
+	if err := handleForwardResponseOptions(ctx, mux, w, resp, opts); err != nil {
 		HTTPError(ctx, mux, marshaler, w, req, err)
 		return
 	}
+	if mux.etagGenerator != nil && (req.Method == "GET" || req.Method == "HEAD") {
+		if etag := mux.etagGenerator(resp); etag != "" {
+			etag = quoteETag(etag)
+			w.Header().Set("ETag", etag)
+			if etagMatches(req.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+	if mux.lastModified != nil && (req.Method == "GET" || req.Method == "HEAD") {
+		if t := mux.lastModified(resp); !t.IsZero() {
+			w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+			if notModifiedSince(req, t) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+	if mux.locationResolver != nil {
+		if loc := mux.locationResolver(req.Method, resp); loc != "" {
+			w.Header().Set("Location", loc)
+		}
+	}
+	st := http.StatusOK
+	if mux.successStatusMapper != nil {
+		st = mux.successStatusMapper(req.Method, resp)
+	}
+	if _, ok := resp.(*empty.Empty); ok && mux.emptyResponseBody != nil {
+		if !*mux.emptyResponseBody {
+			st = http.StatusNoContent
+		} else if st == http.StatusNoContent {
+			st = http.StatusOK
+		}
+	}
+	if st == http.StatusNoContent {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(st)
+		handleForwardResponseTrailer(w, mux, md)
+		return
+	}
 	var buf []byte
-	var err error
+	start := time.Now()
 	if rb, ok := resp.(responseBody); ok {
 		buf, err = marshaler.Marshal(rb.XXX_ResponseBody())
 	} else {
 		buf, err = marshaler.Marshal(resp)
 	}
+	recordMarshalTime(req, time.Since(start))
 	if err != nil {
-		grpclog.Infof("Marshal error: %v", err)
+		loggerOf(mux).Infof("Marshal error: %v", err)
 		HTTPError(ctx, mux, marshaler, w, req, err)
 		return
 	}
 
+	if st != http.StatusOK {
+		w.WriteHeader(st)
+	} else if mux.responseCacheSet != nil && contentType == marshaler.ContentType() {
+		mux.responseCacheSet(ctx, req, buf)
+	}
 	if _, err = w.Write(buf); err != nil {
-		grpclog.Infof("Failed to write response: %v", err)
+		loggerOf(mux).Infof("Failed to write response: %v", err)
 	}
 
-	handleForwardResponseTrailer(w, md)
+	handleForwardResponseTrailer(w, mux, md)
+}
+
+// DefaultSuccessStatus replies to requests whose response is google.protobuf.Empty with
+// http.StatusNoContent and no body, and to all others with http.StatusOK.
+func DefaultSuccessStatus(_ string, msg proto.Message) int {
+	if _, ok := msg.(*empty.Empty); ok {
+		return http.StatusNoContent
+	}
+	return http.StatusOK
+}
+
+// quoteETag returns etag as an entity tag, quoting it unless it already is.
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
 }
 
-func handleForwardResponseOptions(ctx context.Context, w http.ResponseWriter, resp proto.Message, opts []func(context.Context, http.ResponseWriter, proto.Message) error) error {
+// etagMatches reports whether the If-None-Match header value matches etag
+// using the weak comparison of RFC 7232.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// notModifiedSince reports whether the If-Modified-Since header of req shows that the client has the
+// version of the resource last modified at t. The header is ignored with an If-None-Match header,
+// which takes precedence.
+func notModifiedSince(req *http.Request, t time.Time) bool {
+	if req.Header.Get("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// HTTP dates have a resolution of one second.
+	return !t.Truncate(time.Second).After(since)
+}
+
+func handleForwardResponseOptions(ctx context.Context, mux *ServeMux, w http.ResponseWriter, resp proto.Message, opts []func(context.Context, http.ResponseWriter, proto.Message) error) error {
 	if len(opts) == 0 {
 		return nil
 	}
 	for _, opt := range opts {
 		if err := opt(ctx, w, resp); err != nil {
-			grpclog.Infof("Error handling ForwardResponseOptions: %v", err)
+			loggerOf(mux).Infof("Error handling ForwardResponseOptions: %v", err)
 			return err
 		}
 	}
@@ -170,18 +462,41 @@ func handleForwardResponseOptions(ctx context.Context, w http.ResponseWriter, re
 
 func handleForwardResponseStreamError(ctx context.Context, wroteHeader bool, marshaler Marshaler, w http.ResponseWriter, req *http.Request, mux *ServeMux, err error) {
 	serr := streamError(ctx, mux.streamErrorHandler, err)
+	recordCode(req, codes.Code(serr.GrpcCode))
 	if !wroteHeader {
+		handleDeadlineDiagnostics(ctx, w, mux, req, codes.Code(serr.GrpcCode))
 		w.WriteHeader(int(serr.HttpCode))
 	}
 	buf, merr := marshaler.Marshal(errorChunk(serr))
 	if merr != nil {
-		grpclog.Infof("Failed to marshal an error: %v", merr)
+		loggerOf(mux).Infof("Failed to marshal an error: %v", merr)
 		return
 	}
+	if framer, ok := streamFramerOf(marshaler); ok {
+		buf = framer.Frame(buf)
+	} else {
+		buf = append(buf, streamDelimiter(marshaler)...)
+	}
 	if _, werr := w.Write(buf); werr != nil {
-		grpclog.Infof("Failed to notify error to client: %v", werr)
+		loggerOf(mux).Infof("Failed to notify error to client: %v", werr)
 		return
 	}
+	w.Header().Set(streamStatusTrailer, strconv.Itoa(int(serr.GrpcCode)))
+	if serr.Message != "" {
+		w.Header().Set(streamMessageTrailer, encodeGrpcMessage(serr.Message))
+	}
+	if s, ok := status.FromError(err); ok {
+		handleStatusDetails(w, mux, s)
+	}
+}
+
+// streamDelimiter returns the delimiter written after each chunk of a stream marshaled with
+// marshaler which is not a StreamFramer.
+func streamDelimiter(marshaler Marshaler) []byte {
+	if d, ok := marshaler.(Delimited); ok {
+		return d.Delimiter()
+	}
+	return []byte("\n")
 }
 
 // streamChunk returns a chunk in a response stream for the given result. The
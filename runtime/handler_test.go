@@ -1,20 +1,36 @@
 package runtime_test
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"context"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	pb "github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
 	"github.com/ninnemana/grpc-gateway/internal"
 	"github.com/ninnemana/grpc-gateway/runtime"
+	"github.com/ninnemana/grpc-gateway/utilities"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestForwardResponseStream(t *testing.T) {
@@ -26,6 +42,7 @@ func TestForwardResponseStream(t *testing.T) {
 		name       string
 		msgs       []msg
 		statusCode int
+		grpcStatus string
 	}{{
 		name: "encoding",
 		msgs: []msg{
@@ -33,13 +50,16 @@ func TestForwardResponseStream(t *testing.T) {
 			{&pb.SimpleMessage{Id: "Two"}, nil},
 		},
 		statusCode: http.StatusOK,
+		grpcStatus: "0",
 	}, {
 		name:       "empty",
 		statusCode: http.StatusOK,
+		grpcStatus: "0",
 	}, {
 		name:       "error",
 		msgs:       []msg{{nil, grpc.Errorf(codes.OutOfRange, "400")}},
 		statusCode: http.StatusBadRequest,
+		grpcStatus: "11",
 	}, {
 		name: "stream_error",
 		msgs: []msg{
@@ -47,6 +67,7 @@ func TestForwardResponseStream(t *testing.T) {
 			{nil, grpc.Errorf(codes.OutOfRange, "400")},
 		},
 		statusCode: http.StatusOK,
+		grpcStatus: "11",
 	}}
 
 	newTestRecv := func(t *testing.T, msgs []msg) func() (proto.Message, error) {
@@ -79,6 +100,9 @@ func TestForwardResponseStream(t *testing.T) {
 			if h := w.Header.Get("Transfer-Encoding"); h != "chunked" {
 				t.Errorf("ForwardResponseStream missing header chunked")
 			}
+			if got := w.Trailer.Get("Grpc-Status"); got != tt.grpcStatus {
+				t.Errorf("w.Trailer.Get(%q) = %q; want %q", "Grpc-Status", got, tt.grpcStatus)
+			}
 			body, err := ioutil.ReadAll(w.Body)
 			if err != nil {
 				t.Errorf("Failed to read response body with %v", err)
@@ -106,6 +130,7 @@ func TestForwardResponseStream(t *testing.T) {
 					if err != nil {
 						t.Errorf("marshaler.Marshal() failed %v", err)
 					}
+					b = append(b, marshaler.Delimiter()...)
 					errBytes := body[len(want):]
 					if string(errBytes) != string(b) {
 						t.Errorf("ForwardResponseStream() = \"%s\" want \"%s\"", errBytes, b)
@@ -226,3 +251,1486 @@ func TestForwardResponseStreamCustomMarshaler(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardResponseStreamClientDisconnect(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	reqCtx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil).WithContext(reqCtx)
+	resp := httptest.NewRecorder()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	var count int
+	recv := func() (proto.Message, error) {
+		count++
+		if count == 1 {
+			return &pb.SimpleMessage{Id: "One"}, nil
+		}
+		// Simulate the client going away while the backend has nothing to send.
+		disconnect()
+		<-unblock
+		return nil, io.EOF
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runtime.ForwardResponseStream(ctx, runtime.NewServeMux(), &runtime.JSONPb{}, resp, req, recv)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("ForwardResponseStream did not return after the client disconnected")
+	}
+
+	want, err := (&runtime.JSONPb{}).Marshal(map[string]proto.Message{"result": &pb.SimpleMessage{Id: "One"}})
+	if err != nil {
+		t.Fatalf("marshaler.Marshal() failed %v", err)
+	}
+	want = append(want, '\n')
+	if got := resp.Body.String(); got != string(want) {
+		t.Errorf("ForwardResponseStream() = %q want %q", got, want)
+	}
+}
+
+func TestForwardResponseStreamDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{})
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	resp := httptest.NewRecorder()
+
+	var count int
+	recv := func() (proto.Message, error) {
+		count++
+		if count > 1 {
+			// Simulate messages which were already buffered when the deadline passed.
+			<-ctx.Done()
+		}
+		return &pb.SimpleMessage{Id: "One"}, nil
+	}
+	runtime.ForwardResponseStream(ctx, runtime.NewServeMux(), &runtime.JSONPb{OrigName: true}, resp, req, recv)
+
+	if got, want := count, 2; got != want {
+		t.Errorf("count = %d; want %d", got, want)
+	}
+	want := `{"result":{"id":"One"}}` + "\n" + `{"error":{"grpc_code":4,"http_code":504,"message":"context deadline exceeded","http_status":"Gateway Timeout"}}` + "\n"
+	if got := resp.Body.String(); got != want {
+		t.Errorf("ForwardResponseStream() = %q want %q", got, want)
+	}
+	if got, want := resp.Header().Get("Grpc-Status"), "4"; got != want {
+		t.Errorf(`resp.Header().Get("Grpc-Status") = %q; want %q`, got, want)
+	}
+}
+
+func TestForwardResponseStreamPartialOnDeadline(t *testing.T) {
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	for _, spec := range []struct {
+		name     string
+		messages int
+		want     string
+	}{
+		{name: "after messages", messages: 1, want: `{"result":{"id":"One"}}` + "\n"},
+		{name: "before messages", messages: 0, want: ""},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(runtime.WithPartialStreamOnDeadline("/foo"))
+			var count int
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				ctx, cancel := context.WithTimeout(r.Context(), 10*time.Millisecond)
+				defer cancel()
+				ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{})
+				recv := func() (proto.Message, error) {
+					count++
+					if count > spec.messages {
+						<-ctx.Done()
+						return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+					}
+					return &pb.SimpleMessage{Id: "One"}, nil
+				}
+				runtime.ForwardResponseStream(ctx, mux, &runtime.JSONPb{OrigName: true}, w, r, recv)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			res, err := http.Get(server.URL + "/foo")
+			if err != nil {
+				t.Fatalf("http.Get(%q) failed with %v; want success", server.URL+"/foo", err)
+			}
+			defer res.Body.Close()
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("ioutil.ReadAll(res.Body) failed with %v; want success", err)
+			}
+
+			if got, want := res.StatusCode, http.StatusOK; got != want {
+				t.Errorf("res.StatusCode = %d; want %d", got, want)
+			}
+			if got := string(body); got != spec.want {
+				t.Errorf("ForwardResponseStream() = %q; want %q", got, spec.want)
+			}
+			// The warning is sent once: as a header if it is known before the first
+			// message, or else as a trailer.
+			sent, other := res.Trailer, res.Header
+			if spec.messages == 0 {
+				sent, other = res.Header, res.Trailer
+			}
+			if got := sent["Warning"]; len(got) != 1 || !strings.Contains(got[0], "truncated") {
+				t.Errorf("Warning = %q; want a truncation warning", got)
+			}
+			if got, ok := other["Warning"]; ok {
+				t.Errorf("Warning = %q also sent the other way; want it only once", got)
+			}
+			if got, want := res.Trailer.Get("Grpc-Status"), "0"; got != want {
+				t.Errorf(`res.Trailer.Get("Grpc-Status") = %q; want %q`, got, want)
+			}
+		})
+	}
+}
+
+func TestForwardResponseStreamKeepAlive(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	marshaler := &runtime.JSONPb{}
+	var count int
+	recv := func() (proto.Message, error) {
+		count++
+		if count > 1 {
+			return nil, io.EOF
+		}
+		time.Sleep(50 * time.Millisecond)
+		return &pb.SimpleMessage{Id: "One"}, nil
+	}
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	resp := httptest.NewRecorder()
+	mux := runtime.NewServeMux(runtime.WithStreamKeepAlive(10 * time.Millisecond))
+	runtime.ForwardResponseStream(ctx, mux, marshaler, resp, req, recv)
+
+	msg, err := marshaler.Marshal(map[string]proto.Message{"result": &pb.SimpleMessage{Id: "One"}})
+	if err != nil {
+		t.Fatalf("marshaler.Marshal() failed %v", err)
+	}
+	body := resp.Body.String()
+	if !strings.HasPrefix(body, "\n") {
+		t.Errorf("ForwardResponseStream() = %q; want a leading keep-alive line", body)
+	}
+	if got, want := strings.TrimLeft(body, "\n"), string(msg)+"\n"; got != want {
+		t.Errorf("ForwardResponseStream() without keep-alives = %q; want %q", got, want)
+	}
+}
+
+func TestForwardResponseMessageETag(t *testing.T) {
+	for _, spec := range []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		wantStatus  int
+		wantBody    bool
+	}{
+		{
+			name:       "no condition",
+			method:     "GET",
+			wantStatus: http.StatusOK,
+			wantBody:   true,
+		},
+		{
+			name:        "match",
+			method:      "GET",
+			ifNoneMatch: `"v1", "v2"`,
+			wantStatus:  http.StatusNotModified,
+		},
+		{
+			name:        "weak match",
+			method:      "GET",
+			ifNoneMatch: `W/"v2"`,
+			wantStatus:  http.StatusNotModified,
+		},
+		{
+			name:        "mismatch",
+			method:      "GET",
+			ifNoneMatch: `"v1"`,
+			wantStatus:  http.StatusOK,
+			wantBody:    true,
+		},
+		{
+			name:        "not a read",
+			method:      "POST",
+			ifNoneMatch: `"v2"`,
+			wantStatus:  http.StatusOK,
+			wantBody:    true,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+			mux := runtime.NewServeMux(runtime.WithETagGenerator(func(msg proto.Message) string {
+				return msg.(*pb.SimpleMessage).Id
+			}))
+			req := httptest.NewRequest(spec.method, "http://example.com/foo", nil)
+			if spec.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", spec.ifNoneMatch)
+			}
+			resp := httptest.NewRecorder()
+			runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, &pb.SimpleMessage{Id: "v2"})
+
+			if got := resp.Code; got != spec.wantStatus {
+				t.Errorf("resp.Code = %d; want %d", got, spec.wantStatus)
+			}
+			wantETag := `"v2"`
+			if spec.method != "GET" {
+				wantETag = ""
+			}
+			if got := resp.Header().Get("ETag"); got != wantETag {
+				t.Errorf(`resp.Header().Get("ETag") = %q; want %q`, got, wantETag)
+			}
+			if got := resp.Body.Len() > 0; got != spec.wantBody {
+				t.Errorf("resp.Body = %q; want body %v", resp.Body.String(), spec.wantBody)
+			}
+		})
+	}
+}
+
+func TestForwardResponseMessageLastModified(t *testing.T) {
+	modified := time.Date(2019, 3, 1, 12, 0, 0, 500, time.UTC)
+	for _, spec := range []struct {
+		name            string
+		method          string
+		ifModifiedSince string
+		ifNoneMatch     string
+		wantStatus      int
+	}{
+		{name: "no condition", method: "GET", wantStatus: http.StatusOK},
+		{name: "not modified", method: "GET", ifModifiedSince: "Fri, 01 Mar 2019 12:00:00 GMT", wantStatus: http.StatusNotModified},
+		{name: "modified", method: "GET", ifModifiedSince: "Fri, 01 Mar 2019 11:59:59 GMT", wantStatus: http.StatusOK},
+		{name: "invalid date", method: "GET", ifModifiedSince: "yesterday", wantStatus: http.StatusOK},
+		{name: "If-None-Match takes precedence", method: "GET", ifModifiedSince: "Fri, 01 Mar 2019 12:00:00 GMT", ifNoneMatch: `"v1"`, wantStatus: http.StatusOK},
+		{name: "not a read", method: "POST", ifModifiedSince: "Fri, 01 Mar 2019 12:00:00 GMT", wantStatus: http.StatusOK},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+			mux := runtime.NewServeMux(runtime.WithLastModified(func(proto.Message) time.Time { return modified }))
+			req := httptest.NewRequest(spec.method, "http://example.com/foo", nil)
+			if spec.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", spec.ifModifiedSince)
+			}
+			if spec.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", spec.ifNoneMatch)
+			}
+			resp := httptest.NewRecorder()
+			runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, &pb.SimpleMessage{Id: "v2"})
+
+			if got := resp.Code; got != spec.wantStatus {
+				t.Errorf("resp.Code = %d; want %d", got, spec.wantStatus)
+			}
+			wantLastModified := "Fri, 01 Mar 2019 12:00:00 GMT"
+			if spec.method != "GET" {
+				wantLastModified = ""
+			}
+			if got := resp.Header().Get("Last-Modified"); got != wantLastModified {
+				t.Errorf(`resp.Header().Get("Last-Modified") = %q; want %q`, got, wantLastModified)
+			}
+			if got, want := resp.Body.Len() > 0, spec.wantStatus == http.StatusOK; got != want {
+				t.Errorf("resp.Body = %q; want body %v", resp.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestForwardResponseMessageSuccessStatus(t *testing.T) {
+	mapper := func(method string, msg proto.Message) int {
+		if method == "POST" {
+			return http.StatusCreated
+		}
+		return runtime.DefaultSuccessStatus(method, msg)
+	}
+	for _, spec := range []struct {
+		name       string
+		method     string
+		msg        proto.Message
+		wantStatus int
+		wantBody   bool
+	}{
+		{
+			name:       "ok",
+			method:     "GET",
+			msg:        &pb.SimpleMessage{Id: "foo"},
+			wantStatus: http.StatusOK,
+			wantBody:   true,
+		},
+		{
+			name:       "created",
+			method:     "POST",
+			msg:        &pb.SimpleMessage{Id: "foo"},
+			wantStatus: http.StatusCreated,
+			wantBody:   true,
+		},
+		{
+			name:       "empty",
+			method:     "DELETE",
+			msg:        &empty.Empty{},
+			wantStatus: http.StatusNoContent,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+			mux := runtime.NewServeMux(runtime.WithSuccessStatusMapper(mapper))
+			req := httptest.NewRequest(spec.method, "http://example.com/foo", nil)
+			resp := httptest.NewRecorder()
+			runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, spec.msg)
+
+			if got := resp.Code; got != spec.wantStatus {
+				t.Errorf("resp.Code = %d; want %d", got, spec.wantStatus)
+			}
+			if got := resp.Body.Len() > 0; got != spec.wantBody {
+				t.Errorf("resp.Body = %q; want body %v", resp.Body.String(), spec.wantBody)
+			}
+		})
+	}
+}
+
+func TestForwardResponseMessageEmptyResponseBody(t *testing.T) {
+	for _, spec := range []struct {
+		name       string
+		opts       []runtime.ServeMuxOption
+		msg        proto.Message
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "default",
+			msg:        &empty.Empty{},
+			wantStatus: http.StatusOK,
+			wantBody:   "{}",
+		},
+		{
+			name:       "disabled",
+			opts:       []runtime.ServeMuxOption{runtime.WithEmptyResponseBody(false)},
+			msg:        &empty.Empty{},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "enabled over mapper",
+			opts:       []runtime.ServeMuxOption{runtime.WithSuccessStatusMapper(nil), runtime.WithEmptyResponseBody(true)},
+			msg:        &empty.Empty{},
+			wantStatus: http.StatusOK,
+			wantBody:   "{}",
+		},
+		{
+			name:       "other messages",
+			opts:       []runtime.ServeMuxOption{runtime.WithEmptyResponseBody(false)},
+			msg:        &pb.SimpleMessage{Id: "foo"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"foo"}`,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+			mux := runtime.NewServeMux(spec.opts...)
+			req := httptest.NewRequest("DELETE", "http://example.com/foo", nil)
+			resp := httptest.NewRecorder()
+			runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, spec.msg)
+
+			if got := resp.Code; got != spec.wantStatus {
+				t.Errorf("resp.Code = %d; want %d", got, spec.wantStatus)
+			}
+			if got := resp.Body.String(); got != spec.wantBody {
+				t.Errorf("resp.Body = %q; want %q", got, spec.wantBody)
+			}
+		})
+	}
+}
+
+func TestForwardResponseMessageLocation(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	mux := runtime.NewServeMux(runtime.WithLocationResolver(func(method string, msg proto.Message) string {
+		if method != "POST" {
+			return ""
+		}
+		return "/v1/messages/" + msg.(*pb.SimpleMessage).Id
+	}))
+	for method, want := range map[string]string{
+		"POST": "/v1/messages/foo",
+		"GET":  "",
+	} {
+		req := httptest.NewRequest(method, "http://example.com/v1/messages", nil)
+		resp := httptest.NewRecorder()
+		runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, &pb.SimpleMessage{Id: "foo"})
+		if got := resp.Header().Get("Location"); got != want {
+			t.Errorf(`%s: resp.Header().Get("Location") = %q; want %q`, method, got, want)
+		}
+	}
+}
+
+func TestForwardResponseMessageTrailers(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+		TrailerMD: metadata.Pairs("grpc-message", "ok", "grpc-status-details-bin", "details", "foo", "bar"),
+	})
+	for _, spec := range []struct {
+		name string
+		opts []runtime.ServeMuxOption
+		want http.Header
+	}{
+		{
+			name: "default",
+			want: http.Header{"Grpc-Trailer-Grpc-Message": {"ok"}},
+		},
+		{
+			name: "matcher",
+			opts: []runtime.ServeMuxOption{runtime.WithOutgoingTrailerMatcher(func(key string) (string, bool) {
+				return "X-" + key, key == "foo"
+			})},
+			want: http.Header{"X-Foo": {"bar"}},
+		},
+	} {
+		mux := runtime.NewServeMux(spec.opts...)
+		req := httptest.NewRequest("GET", "http://example.com/v1/messages", nil)
+		resp := httptest.NewRecorder()
+		runtime.ForwardResponseMessage(ctx, mux, &runtime.JSONPb{}, resp, req, &pb.SimpleMessage{Id: "foo"})
+
+		declared := resp.Header()["Trailer"]
+		var wantDeclared []string
+		for k := range spec.want {
+			wantDeclared = append(wantDeclared, k)
+		}
+		if !reflect.DeepEqual(declared, wantDeclared) {
+			t.Errorf(`%s: resp.Header()["Trailer"] = %q; want %q`, spec.name, declared, wantDeclared)
+		}
+		if got := resp.Result().Trailer; !reflect.DeepEqual(got, spec.want) {
+			t.Errorf("%s: resp.Result().Trailer = %q; want %q", spec.name, got, spec.want)
+		}
+	}
+}
+
+func TestForwardResponseMessageContentTypeMarshaler(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	marshaler := &runtime.ContentTypeMarshaler{Marshaler: &runtime.JSONPb{}, Type: "application/json; charset=utf-8"}
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	resp := httptest.NewRecorder()
+	runtime.ForwardResponseMessage(ctx, runtime.NewServeMux(), marshaler, resp, req, &pb.SimpleMessage{Id: "foo"})
+
+	if got, want := resp.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+		t.Errorf(`resp.Header().Get("Content-Type") = %q; want %q`, got, want)
+	}
+	if got, want := resp.Body.String(), `{"id":"foo"}`; got != want {
+		t.Errorf("resp.Body = %q; want %q", got, want)
+	}
+}
+
+// updateV2Client records the request of UpdateV2, which binds the body to
+// the "abe" field and the path to "abe.uuid".
+type updateV2Client struct {
+	pb.ABitOfEverythingServiceClient
+	got *pb.UpdateV2Request
+}
+
+func (c *updateV2Client) UpdateV2(_ context.Context, in *pb.UpdateV2Request, _ ...grpc.CallOption) (*empty.Empty, error) {
+	c.got = in
+	return &empty.Empty{}, nil
+}
+
+func TestBodyFieldMergesWithPathParams(t *testing.T) {
+	for _, spec := range []struct {
+		name       string
+		url        string
+		body       string
+		wantString string
+	}{
+		{
+			name:       "path wins over body",
+			url:        "http://example.com/v2/example/a_bit_of_everything/from-path",
+			body:       `{"uuid":"from-body","string_value":"foo"}`,
+			wantString: "foo",
+		},
+		{
+			name: "empty body",
+			url:  "http://example.com/v2/example/a_bit_of_everything/from-path",
+		},
+		{
+			name:       "query does not override path",
+			url:        "http://example.com/v2/example/a_bit_of_everything/from-path?abe.uuid=from-query",
+			body:       `{"string_value":"foo"}`,
+			wantString: "foo",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			client := &updateV2Client{}
+			mux := runtime.NewServeMux()
+			if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, client); err != nil {
+				t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+			}
+			req := httptest.NewRequest("PUT", spec.url, strings.NewReader(spec.body))
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusOK {
+				t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+			}
+			if got, want := client.got.GetAbe().GetUuid(), "from-path"; got != want {
+				t.Errorf("abe.uuid = %q; want %q", got, want)
+			}
+			if got := client.got.GetAbe().GetStringValue(); got != spec.wantString {
+				t.Errorf("abe.string_value = %q; want %q", got, spec.wantString)
+			}
+		})
+	}
+}
+
+func TestAutoFieldMask(t *testing.T) {
+	for _, spec := range []struct {
+		name  string
+		opts  []runtime.ServeMuxOption
+		body  string
+		paths []string
+	}{
+		{
+			name:  "nested fields",
+			opts:  []runtime.ServeMuxOption{runtime.WithAutoFieldMask("update_mask")},
+			body:  `{"abe":{"string_value":"foo","single_nested":{"amount":1}}}`,
+			paths: []string{"abe.single_nested.amount", "abe.string_value"},
+		},
+		{
+			name:  "camelCase fields of a message defined elsewhere",
+			opts:  []runtime.ServeMuxOption{runtime.WithAutoFieldMask("update_mask")},
+			body:  `{"abe":{"stringValue":"foo","singleNested":{"amount":1},"nestedPathEnumValue":"GHI","mapValue":{"a":"ONE"}}}`,
+			paths: []string{"abe.map_value", "abe.nested_path_enum_value", "abe.single_nested.amount", "abe.string_value"},
+		},
+		{
+			name:  "mask set by client",
+			opts:  []runtime.ServeMuxOption{runtime.WithAutoFieldMask("update_mask")},
+			body:  `{"abe":{"string_value":"foo","single_nested":{"amount":1}},"update_mask":{"paths":["abe.string_value"]}}`,
+			paths: []string{"abe.string_value"},
+		},
+		{
+			name: "option not set",
+			body: `{"abe":{"string_value":"foo"}}`,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			client := &updateV2Client{}
+			mux := runtime.NewServeMux(spec.opts...)
+			if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, client); err != nil {
+				t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+			}
+			req := httptest.NewRequest("PATCH", "http://example.com/v2a/example/a_bit_of_everything/foo", strings.NewReader(spec.body))
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusOK {
+				t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+			}
+			paths := client.got.GetUpdateMask().GetPaths()
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, spec.paths) {
+				t.Errorf("update_mask.paths = %q; want %q", paths, spec.paths)
+			}
+		})
+	}
+}
+
+// flakyEchoClient fails the first "failures" calls of Echo with "code".
+type flakyEchoClient struct {
+	pb.EchoServiceClient
+	failures int
+	code     codes.Code
+	calls    int
+}
+
+func (c *flakyEchoClient) Echo(_ context.Context, in *pb.SimpleMessage, _ ...grpc.CallOption) (*pb.SimpleMessage, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, status.Error(c.code, "flaky")
+	}
+	return in, nil
+}
+
+func (c *flakyEchoClient) EchoBody(ctx context.Context, in *pb.SimpleMessage, opts ...grpc.CallOption) (*pb.SimpleMessage, error) {
+	return c.Echo(ctx, in, opts...)
+}
+
+func TestGetRetry(t *testing.T) {
+	for _, spec := range []struct {
+		name      string
+		method    string
+		url       string
+		failures  int
+		code      codes.Code
+		wantCalls int
+		wantCode  int
+	}{
+		{
+			name:      "GET succeeds after retries",
+			method:    "GET",
+			url:       "http://example.com/v1/example/echo/foo/1",
+			failures:  2,
+			code:      codes.Unavailable,
+			wantCalls: 3,
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "GET gives up after attempts",
+			method:    "GET",
+			url:       "http://example.com/v1/example/echo/foo/1",
+			failures:  5,
+			code:      codes.DeadlineExceeded,
+			wantCalls: 3,
+			wantCode:  http.StatusGatewayTimeout,
+		},
+		{
+			name:      "GET is not retried on other codes",
+			method:    "GET",
+			url:       "http://example.com/v1/example/echo/foo/1",
+			failures:  1,
+			code:      codes.NotFound,
+			wantCalls: 1,
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:      "POST is never retried",
+			method:    "POST",
+			url:       "http://example.com/v1/example/echo/foo",
+			failures:  1,
+			code:      codes.Unavailable,
+			wantCalls: 1,
+			wantCode:  http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			client := &flakyEchoClient{failures: spec.failures, code: spec.code}
+			mux := runtime.NewServeMux(runtime.WithGetRetry(3, time.Millisecond))
+			if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+				t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+			}
+			req := httptest.NewRequest(spec.method, spec.url, nil)
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if resp.Code != spec.wantCode {
+				t.Errorf("resp.Code = %d; want %d; body %s", resp.Code, spec.wantCode, resp.Body)
+			}
+			if client.calls != spec.wantCalls {
+				t.Errorf("client.calls = %d; want %d", client.calls, spec.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGetRetryTracing(t *testing.T) {
+	tracer := mocktracer.New()
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	opentracing.SetGlobalTracer(tracer)
+
+	client := &flakyEchoClient{failures: 2, code: codes.Unavailable}
+	mux := runtime.NewServeMux(runtime.WithGetRetry(3, time.Millisecond))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil)
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	spans := tracer.FinishedSpans()
+	if got, want := len(spans), 4; got != want {
+		t.Fatalf("len(tracer.FinishedSpans()) = %d; want %d", got, want)
+	}
+	server := spans[len(spans)-1]
+	if got, want := server.OperationName, "/v1/example/echo/foo/1"; got != want {
+		t.Errorf("server.OperationName = %q; want %q", got, want)
+	}
+	for i, span := range spans[:len(spans)-1] {
+		if span.ParentID != server.SpanContext.SpanID {
+			t.Errorf("spans[%d].ParentID = %d; want %d", i, span.ParentID, server.SpanContext.SpanID)
+		}
+		if got, want := span.Tag("attempt"), i+1; got != want {
+			t.Errorf("spans[%d].Tag(\"attempt\") = %v; want %d", i, got, want)
+		}
+		if got, want := span.Tag("error") == true, i < 2; got != want {
+			t.Errorf("spans[%d] has error tag %v; want %v", i, got, want)
+		}
+	}
+}
+
+func TestQueryDefaults(t *testing.T) {
+	for _, spec := range []struct {
+		method string
+		url    string
+		want   *pb.SimpleMessage
+	}{
+		{
+			method: "POST",
+			url:    "http://example.com/v1/example/echo/foo",
+			want:   &pb.SimpleMessage{Id: "foo", Num: 50, Code: &pb.SimpleMessage_Lang{Lang: "en"}},
+		},
+		{
+			method: "POST",
+			url:    "http://example.com/v1/example/echo/foo?num=3&lang=fr",
+			want:   &pb.SimpleMessage{Id: "foo", Num: 3, Code: &pb.SimpleMessage_Lang{Lang: "fr"}},
+		},
+		{
+			method: "POST",
+			url:    "http://example.com/v1/example/echo/foo?line_num=7",
+			want:   &pb.SimpleMessage{Id: "foo", Num: 50, Code: &pb.SimpleMessage_LineNum{LineNum: 7}},
+		},
+		{
+			method: "GET",
+			url:    "http://example.com/v1/example/echo/foo/4",
+			want:   &pb.SimpleMessage{Id: "foo", Num: 4, Code: &pb.SimpleMessage_Lang{Lang: "en"}},
+		},
+	} {
+		mux := runtime.NewServeMux(runtime.WithQueryDefaults("/grpc.gateway.examples.examplepb.EchoService/Echo", map[string]string{"num": "50", "lang": "en"}))
+		if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+			t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+		}
+		req := httptest.NewRequest(spec.method, spec.url, nil)
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+
+		if resp.Code != http.StatusOK {
+			t.Errorf("%s %s: resp.Code = %d; want %d; body %s", spec.method, spec.url, resp.Code, http.StatusOK, resp.Body)
+			continue
+		}
+		var got pb.SimpleMessage
+		if err := (&runtime.JSONPb{OrigName: true}).Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+		}
+		if !proto.Equal(&got, spec.want) {
+			t.Errorf("%s %s: got %v; want %v", spec.method, spec.url, &got, spec.want)
+		}
+	}
+}
+
+type echoABitOfEverythingClient struct {
+	pb.ABitOfEverythingServiceClient
+}
+
+func (echoABitOfEverythingClient) CheckPostQueryParams(_ context.Context, in *pb.ABitOfEverything, _ ...grpc.CallOption) (*pb.ABitOfEverything, error) {
+	return in, nil
+}
+
+func (echoABitOfEverythingClient) CheckGetQueryParams(_ context.Context, in *pb.ABitOfEverything, _ ...grpc.CallOption) (*pb.ABitOfEverything, error) {
+	return in, nil
+}
+
+func (echoABitOfEverythingClient) CheckNestedEnumGetQueryParams(_ context.Context, in *pb.ABitOfEverything, _ ...grpc.CallOption) (*pb.ABitOfEverything, error) {
+	return in, nil
+}
+
+func TestQueryParametersAndBodyPrecedence(t *testing.T) {
+	for _, spec := range []struct {
+		name string
+		opts []runtime.ServeMuxOption
+		want *pb.ABitOfEverything_Nested
+	}{
+		{
+			name: "body wins",
+			want: &pb.ABitOfEverything_Nested{Name: "body", Amount: 1},
+		},
+		{
+			name: "query overrides body",
+			opts: []runtime.ServeMuxOption{runtime.WithQueryOverridesBody()},
+			want: &pb.ABitOfEverything_Nested{Name: "query", Amount: 7},
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(spec.opts...)
+			if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, echoABitOfEverythingClient{}); err != nil {
+				t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+			}
+			body := strings.NewReader(`{"name": "body", "amount": 1}`)
+			req := httptest.NewRequest("POST", "http://example.com/v1/example/a_bit_of_everything/params/post/foo?single_nested.name=query&singleNested.amount=7", body)
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusOK {
+				t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+			}
+			var got pb.ABitOfEverything
+			if err := (&runtime.JSONPb{OrigName: true}).Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+			}
+			if !proto.Equal(got.SingleNested, spec.want) {
+				t.Errorf("got.SingleNested = %v; want %v", got.SingleNested, spec.want)
+			}
+			if got, want := got.StringValue, "foo"; got != want {
+				t.Errorf("got.StringValue = %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestBracketQueryNotation(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithBracketQueryNotation())
+	if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, echoABitOfEverythingClient{}); err != nil {
+		t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+	}
+
+	for _, spec := range []struct {
+		query string
+		want  *pb.ABitOfEverything
+	}{
+		{
+			query: "single_nested[amount]=7&singleNested[ok]=TRUE",
+			want:  &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Name: "foo", Amount: 7, Ok: pb.ABitOfEverything_Nested_TRUE}},
+		},
+		{
+			query: "repeated_string_value[]=a&repeated_string_value[]=b&uuid=x",
+			want:  &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Name: "foo"}, RepeatedStringValue: []string{"a", "b"}, Uuid: "x"},
+		},
+		{
+			query: "mapped_string_value[k]=v",
+			want:  &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Name: "foo"}, MappedStringValue: map[string]string{"k": "v"}},
+		},
+	} {
+		req := httptest.NewRequest("GET", "http://example.com/v1/example/a_bit_of_everything/params/get/foo?"+spec.query, nil)
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Errorf("resp.Code = %d for %s; want %d; body %s", resp.Code, spec.query, http.StatusOK, resp.Body)
+			continue
+		}
+		var got pb.ABitOfEverything
+		if err := (&runtime.JSONPb{OrigName: true}).Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+		}
+		if !proto.Equal(&got, spec.want) {
+			t.Errorf("got %v for %s; want %v", &got, spec.query, spec.want)
+		}
+	}
+
+	for _, key := range []string{
+		"single_nested[amount",
+		"single_nested[amount]x",
+		"single_nested[][amount]",
+		"[amount]",
+		"single_nested[a[b]]",
+		"mapped_string_value[k][x]",
+	} {
+		req := httptest.NewRequest("GET", "http://example.com/v1/example/a_bit_of_everything/params/get/foo", nil)
+		req.URL.RawQuery = url.Values{key: {"7"}}.Encode()
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+		if got, want := resp.Code, http.StatusBadRequest; got != want {
+			t.Errorf("resp.Code = %d for %s; want %d; body %s", got, key, want, resp.Body)
+		}
+	}
+}
+
+func TestCustomTypeDecoder(t *testing.T) {
+	decodeDate := func(s string) (proto.Message, error) {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, err
+		}
+		return ptypes.TimestampProto(d)
+	}
+	mux := runtime.NewServeMux(runtime.WithCustomTypeDecoder("google.protobuf.Timestamp", decodeDate))
+	if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, echoABitOfEverythingClient{}); err != nil {
+		t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+	}
+	want, err := ptypes.TimestampProto(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ptypes.TimestampProto failed with %v; want success", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/v1/example/a_bit_of_everything/params/get/foo?timestamp_value=2020-01-02", nil)
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+	}
+	var got pb.ABitOfEverything
+	if err := (&runtime.JSONPb{OrigName: true}).Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+	}
+	if !proto.Equal(got.TimestampValue, want) {
+		t.Errorf("got.TimestampValue = %v; want %v", got.TimestampValue, want)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/v1/example/a_bit_of_everything/params/get/foo?timestamp_value=2020-01-02T00:00:00Z", nil)
+	resp = httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Code, http.StatusBadRequest; got != want {
+		t.Errorf("resp.Code = %d for an RFC 3339 timestamp; want %d; body %s", got, want, resp.Body)
+	}
+
+	req = httptest.NewRequest("POST", "http://example.com/v1/example/a_bit_of_everything/params/post/foo", nil)
+	req.Header.Set("Content-Type", "application/json")
+	inbound, _ := runtime.MarshalerForRequest(mux, req)
+	var body pb.ABitOfEverything
+	if err := inbound.Unmarshal([]byte(`{"timestamp_value": "2020-01-02", "nested": [{"name": "x"}]}`), &body); err != nil {
+		t.Fatalf("inbound.Unmarshal failed with %v; want success", err)
+	}
+	if !proto.Equal(body.TimestampValue, want) {
+		t.Errorf("body.TimestampValue = %v; want %v", body.TimestampValue, want)
+	}
+	if len(body.Nested) != 1 || body.Nested[0].Name != "x" {
+		t.Errorf("body.Nested = %v; want [name:\"x\"]", body.Nested)
+	}
+	if err := inbound.NewDecoder(strings.NewReader(`{"timestamp_value": "tomorrow"}`)).Decode(&body); err == nil {
+		t.Errorf("Decode of an invalid date succeeded; want failure")
+	}
+}
+
+func TestCaseInsensitiveEnums(t *testing.T) {
+	for _, spec := range []struct {
+		name string
+		path string
+		opts []runtime.ServeMuxOption
+		want *pb.ABitOfEverything
+		msg  string
+	}{
+		{
+			name: "path parameter",
+			path: "/v1/example/a_bit_of_everything/params/get/nested_enum/true",
+			msg:  "type mismatch, parameter: single_nested.ok, error: true is not valid, want one of FALSE, TRUE or their numbers",
+		},
+		{
+			name: "path parameter with WithCaseInsensitiveEnums",
+			path: "/v1/example/a_bit_of_everything/params/get/nested_enum/true",
+			opts: []runtime.ServeMuxOption{runtime.WithCaseInsensitiveEnums()},
+			want: &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Ok: pb.ABitOfEverything_Nested_TRUE}},
+		},
+		{
+			name: "query parameter",
+			path: "/v1/example/a_bit_of_everything/params/get/foo?enum_value=one",
+			msg:  "one is not valid, want one of ZERO, ONE or their numbers",
+		},
+		{
+			name: "query parameter with WithCaseInsensitiveEnums",
+			path: "/v1/example/a_bit_of_everything/params/get/foo?enum_value=one",
+			opts: []runtime.ServeMuxOption{runtime.WithCaseInsensitiveEnums()},
+			want: &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Name: "foo"}, EnumValue: pb.NumericEnum_ONE},
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(spec.opts...)
+			if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, echoABitOfEverythingClient{}); err != nil {
+				t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+			}
+			req := httptest.NewRequest("GET", "http://example.com"+spec.path, nil)
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if spec.want == nil {
+				if got, want := resp.Code, http.StatusBadRequest; got != want {
+					t.Fatalf("resp.Code = %d; want %d; body %s", got, want, resp.Body)
+				}
+				var got struct {
+					Message string `json:"message"`
+				}
+				if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+					t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+				}
+				if got.Message != spec.msg {
+					t.Errorf("got.Message = %q; want %q", got.Message, spec.msg)
+				}
+				return
+			}
+			if resp.Code != http.StatusOK {
+				t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+			}
+			var got pb.ABitOfEverything
+			if err := (&runtime.JSONPb{OrigName: true}).Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) failed with %v; want success", resp.Body, err)
+			}
+			if !proto.Equal(&got, spec.want) {
+				t.Errorf("got %v; want %v", &got, spec.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedHandlersLogger(t *testing.T) {
+	logger := new(recordingLogger)
+	mux := runtime.NewServeMux(runtime.WithLogger(logger))
+	if err := pb.RegisterABitOfEverythingServiceHandlerClient(context.Background(), mux, echoABitOfEverythingClient{}); err != nil {
+		t.Fatalf("pb.RegisterABitOfEverythingServiceHandlerClient failed with %v; want success", err)
+	}
+	if got := mux.Logger(); got != logger {
+		t.Errorf("mux.Logger() = %v; want %v", got, logger)
+	}
+
+	req := httptest.NewRequest("GET", "http://host.example/v1/example/a_bit_of_everything/params/get/foo?no_such_field=1&uuid=a&uuid=b", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Fatalf("w.Code = %d; want %d; body %s", got, want, w.Body)
+	}
+	for _, want := range []string{"field not found", "too many field values: uuid"} {
+		if !strings.Contains(strings.Join(logger.infos, "\n"), want) {
+			t.Errorf("logger.infos = %q; want %q", logger.infos, want)
+		}
+	}
+
+	logger.infos = nil
+	runtime.DefaultHTTPError(context.Background(), mux, &runtime.JSONPb{}, httptest.NewRecorder(), req, status.Error(codes.Code(42), "unknown"))
+	if !strings.Contains(strings.Join(logger.infos, "\n"), "Unknown gRPC error code: Code(42)") {
+		t.Errorf("logger.infos = %q; want the unknown code", logger.infos)
+	}
+}
+
+func TestResponseTransformer(t *testing.T) {
+	redact := func(_ context.Context, msg proto.Message) (proto.Message, error) {
+		m, ok := msg.(*pb.SimpleMessage)
+		if !ok {
+			return msg, nil
+		}
+		if m.Id == "secret" {
+			return nil, status.Error(codes.PermissionDenied, "denied")
+		}
+		m = proto.Clone(m).(*pb.SimpleMessage)
+		m.Num = 0
+		return m, nil
+	}
+	mux := runtime.NewServeMux(runtime.WithResponseTransformer(redact))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/5", nil)
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Body.String(), `{"id":"foo"}`; got != want {
+		t.Errorf("resp.Body = %s; want %s", got, want)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/v1/example/echo/secret/5", nil)
+	resp = httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Code, http.StatusForbidden; got != want {
+		t.Errorf("resp.Code = %d; want %d", got, want)
+	}
+
+	msgs := []proto.Message{&pb.SimpleMessage{Id: "a", Num: 1}, &pb.SimpleMessage{Id: "secret", Num: 2}}
+	recv := func() (proto.Message, error) {
+		if len(msgs) == 0 {
+			return nil, io.EOF
+		}
+		msg := msgs[0]
+		msgs = msgs[1:]
+		return msg, nil
+	}
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	resp = httptest.NewRecorder()
+	runtime.ForwardResponseStream(ctx, mux, &runtime.JSONPb{OrigName: true}, resp, httptest.NewRequest("GET", "http://example.com/foo", nil), recv)
+	want := `{"result":{"id":"a"}}` + "\n" + `{"error":{"grpc_code":7,"http_code":403,"message":"denied","http_status":"Forbidden"}}` + "\n"
+	if got := resp.Body.String(); got != want {
+		t.Errorf("resp.Body = %s; want %s", got, want)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	cache := make(map[string][]byte)
+	get := func(_ context.Context, req *http.Request) ([]byte, bool) {
+		body, ok := cache[req.URL.Path]
+		return body, ok
+	}
+	set := func(_ context.Context, req *http.Request, body []byte) {
+		cache[req.URL.Path] = body
+	}
+	client := &flakyEchoClient{}
+	mux := runtime.NewServeMux(runtime.WithResponseCache(get, set))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/5", nil)
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+		if got, want := resp.Body.String(), `{"id":"foo","num":"5"}`; got != want {
+			t.Errorf("resp.Body = %s on request %d; want %s", got, i, want)
+		}
+		if got, want := resp.Header().Get("Content-Type"), "application/json"; got != want {
+			t.Errorf(`resp.Header().Get("Content-Type") = %q on request %d; want %q`, got, i, want)
+		}
+	}
+	if got, want := client.calls, 1; got != want {
+		t.Errorf("client.calls = %d; want %d", got, want)
+	}
+}
+
+func TestChunkedRequestBody(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithMaxRequestBodyBytes(32))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+
+	for _, spec := range []struct {
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{body: `{"id":"foo","num":5}`, wantCode: http.StatusOK, wantBody: `{"id":"foo","num":"5"}`},
+		{body: `{"id":"` + strings.Repeat("x", 32) + `"}`, wantCode: http.StatusBadRequest},
+	} {
+		// A reader of unknown length makes the request chunked, without a Content-Length.
+		req := httptest.NewRequest("POST", "http://example.com/v1/example/echo_body", ioutil.NopCloser(strings.NewReader(spec.body)))
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+		if got := resp.Code; got != spec.wantCode {
+			t.Errorf("resp.Code = %d for %s; want %d; body %s", got, spec.body, spec.wantCode, resp.Body)
+			continue
+		}
+		if spec.wantBody != "" {
+			if got := resp.Body.String(); got != spec.wantBody {
+				t.Errorf("resp.Body = %s; want %s", got, spec.wantBody)
+			}
+		}
+	}
+}
+
+func TestRawBodyMetadata(t *testing.T) {
+	var got []string
+	capture := func(ctx context.Context, _ proto.Message) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get("x-raw-body-bin")
+		return nil
+	}
+	mux := runtime.NewServeMux(runtime.WithRawBodyMetadata("X-Raw-Body"), runtime.WithRequestTransformer(capture))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+
+	body := `{ "id": "foo",  "num": 5 }`
+	req := httptest.NewRequest("POST", "http://example.com/v1/example/echo_body", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Body.String(), `{"id":"foo","num":"5"}`; got != want {
+		t.Errorf("resp.Body = %s; want %s", got, want)
+	}
+	if want := []string{body}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`md.Get("x-raw-body-bin") = %q; want %q`, got, want)
+	}
+}
+
+func TestRequestTransformer(t *testing.T) {
+	injectTenant := func(ctx context.Context, msg proto.Message) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		tenant := md.Get("tenant")
+		if len(tenant) == 0 {
+			return status.Error(codes.Unauthenticated, "no tenant")
+		}
+		msg.(*pb.SimpleMessage).Code = &pb.SimpleMessage_Lang{Lang: tenant[0]}
+		return nil
+	}
+	validate := func(msg proto.Message) error {
+		if msg.(*pb.SimpleMessage).GetLang() == "" {
+			return errors.New("lang is required")
+		}
+		return nil
+	}
+	mux := runtime.NewServeMux(
+		runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			if tenant := req.Header.Get("X-Tenant"); tenant != "" {
+				return metadata.Pairs("tenant", tenant)
+			}
+			return nil
+		}),
+		runtime.WithRequestTransformer(injectTenant),
+		runtime.WithRequestValidator(validate),
+	)
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/5", nil)
+	req.Header.Set("X-Tenant", "acme")
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Body.String(), `{"id":"foo","num":"5","lang":"acme"}`; got != want {
+		t.Errorf("resp.Body = %s; want %s", got, want)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/5", nil)
+	resp = httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	if got, want := resp.Code, http.StatusUnauthorized; got != want {
+		t.Errorf("resp.Code = %d; want %d", got, want)
+	}
+}
+
+func TestGetRetryRespectsDeadline(t *testing.T) {
+	client := &flakyEchoClient{failures: 5, code: codes.Unavailable}
+	mux := runtime.NewServeMux(runtime.WithGetRetry(5, time.Hour))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil)
+	req.Header.Set("Grpc-Timeout", "1S")
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	if got, want := resp.Code, http.StatusServiceUnavailable; got != want {
+		t.Errorf("resp.Code = %d; want %d", got, want)
+	}
+	if got, want := client.calls, 1; got != want {
+		t.Errorf("client.calls = %d; want %d", got, want)
+	}
+}
+
+func TestCallWrapper(t *testing.T) {
+	client := &flakyEchoClient{failures: 1, code: codes.Internal}
+	var failures int
+	breaker := func(ctx context.Context, invoke func() error) error {
+		if failures > 0 {
+			return errors.New("circuit breaker is open")
+		}
+		err := invoke()
+		if err != nil {
+			failures++
+		}
+		return err
+	}
+	mux := runtime.NewServeMux(runtime.WithCallWrapper(breaker))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	for _, want := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil)
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+
+		if resp.Code != want {
+			t.Errorf("resp.Code = %d; want %d; body %s", resp.Code, want, resp.Body)
+		}
+	}
+	if got, want := client.calls, 1; got != want {
+		t.Errorf("client.calls = %d; want %d", got, want)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	var gotPattern, gotIP string
+	limiter := func(ctx context.Context, pattern, clientIP string) error {
+		gotPattern, gotIP = pattern, clientIP
+		if clientIP == "192.0.2.1" {
+			return runtime.RateLimitExceeded(1500 * time.Millisecond)
+		}
+		return nil
+	}
+	client := &flakyEchoClient{}
+	mux := runtime.NewServeMux(runtime.WithRateLimiter(limiter))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	for _, spec := range []struct {
+		name           string
+		forwardedFor   string
+		wantIP         string
+		wantCode       int
+		wantRetryAfter string
+	}{
+		{
+			name:     "remote address",
+			wantIP:   "192.0.2.2",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:           "forwarded client is limited",
+			forwardedFor:   "192.0.2.1, 198.51.100.1",
+			wantIP:         "192.0.2.1",
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil)
+			req.RemoteAddr = "192.0.2.2:1234"
+			if spec.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", spec.forwardedFor)
+			}
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, req)
+
+			if resp.Code != spec.wantCode {
+				t.Errorf("resp.Code = %d; want %d; body %s", resp.Code, spec.wantCode, resp.Body)
+			}
+			if got := resp.Header().Get("Retry-After"); got != spec.wantRetryAfter {
+				t.Errorf("Retry-After = %q; want %q", got, spec.wantRetryAfter)
+			}
+			if got, want := gotPattern, "/v1/example/echo/{id=*}/{num=*}"; got != want {
+				t.Errorf("pattern = %q; want %q", got, want)
+			}
+			if gotIP != spec.wantIP {
+				t.Errorf("clientIP = %q; want %q", gotIP, spec.wantIP)
+			}
+		})
+	}
+}
+
+func TestServerTiming(t *testing.T) {
+	for _, spec := range []struct {
+		name string
+		opts []runtime.ServeMuxOption
+		want bool
+	}{
+		{name: "default"},
+		{name: "enabled", opts: []runtime.ServeMuxOption{runtime.WithServerTiming()}, want: true},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(spec.opts...)
+			if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, &flakyEchoClient{}); err != nil {
+				t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+			}
+			resp := httptest.NewRecorder()
+			mux.ServeHTTP(resp, httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil))
+
+			if resp.Code != http.StatusOK {
+				t.Fatalf("resp.Code = %d; want %d; body %s", resp.Code, http.StatusOK, resp.Body)
+			}
+			got := resp.Header().Get("Server-Timing")
+			if !spec.want {
+				if got != "" {
+					t.Errorf("Server-Timing = %q; want none", got)
+				}
+				return
+			}
+			if !regexp.MustCompile(`^total;dur=\d+\.\d{3}, backend;dur=\d+\.\d{3}, marshal;dur=\d+\.\d{3}$`).MatchString(got) {
+				t.Errorf("Server-Timing = %q; want total, backend and marshal durations", got)
+			}
+		})
+	}
+}
+
+func TestGatewayInterceptor(t *testing.T) {
+	var (
+		gotMethod []string
+		gotCode   codes.Code
+	)
+	observe := func(next runtime.GatewayHandler) runtime.GatewayHandler {
+		return func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			gotMethod = md.Get("x-http-method")
+			err := next(ctx, w, req)
+			gotCode = status.Code(err)
+			return err
+		}
+	}
+	auth := func(next runtime.GatewayHandler) runtime.GatewayHandler {
+		return func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			if req.Header.Get("Authorization") == "" {
+				return status.Error(codes.Unauthenticated, "missing credentials")
+			}
+			return next(ctx, w, req)
+		}
+	}
+	client := &flakyEchoClient{failures: 1, code: codes.NotFound}
+	mux := runtime.NewServeMux(runtime.WithGatewayInterceptor(observe), runtime.WithGatewayInterceptor(auth))
+	if err := pb.RegisterEchoServiceHandlerClient(context.Background(), mux, client); err != nil {
+		t.Fatalf("pb.RegisterEchoServiceHandlerClient failed with %v; want success", err)
+	}
+	for _, spec := range []struct {
+		auth      string
+		wantCode  codes.Code
+		wantHTTP  int
+		wantCalls int
+	}{
+		{wantCode: codes.Unauthenticated, wantHTTP: http.StatusUnauthorized},
+		{auth: "Bearer foo", wantCode: codes.NotFound, wantHTTP: http.StatusNotFound, wantCalls: 1},
+		{auth: "Bearer foo", wantCode: codes.OK, wantHTTP: http.StatusOK, wantCalls: 2},
+	} {
+		req := httptest.NewRequest("GET", "http://example.com/v1/example/echo/foo/1", nil)
+		if spec.auth != "" {
+			req.Header.Set("Authorization", spec.auth)
+		}
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+
+		if resp.Code != spec.wantHTTP {
+			t.Errorf("resp.Code = %d; want %d; body %s", resp.Code, spec.wantHTTP, resp.Body)
+		}
+		if gotCode != spec.wantCode {
+			t.Errorf("status code seen by interceptor = %v; want %v", gotCode, spec.wantCode)
+		}
+		if got, want := gotMethod, []string{"GET"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("x-http-method seen by interceptor = %q; want %q", got, want)
+		}
+		if client.calls != spec.wantCalls {
+			t.Errorf("client.calls = %d; want %d", client.calls, spec.wantCalls)
+		}
+	}
+}
+
+// bulkCreateServer fails BulkCreate with a trailer after receiving "accept" messages.
+type bulkCreateServer struct {
+	pb.StreamServiceServer
+	accept   int
+	received int
+}
+
+func (s *bulkCreateServer) BulkCreate(stream pb.StreamService_BulkCreateServer) error {
+	for s.received < s.accept {
+		if _, err := stream.Recv(); err != nil {
+			return err
+		}
+		s.received++
+	}
+	stream.SetTrailer(metadata.Pairs("reason", "stop"))
+	return status.Error(codes.FailedPrecondition, "stop")
+}
+
+// countingMessagesReader returns "total" JSON messages and counts the bytes read from it.
+type countingMessagesReader struct {
+	total int
+	read  int
+	buf   []byte
+}
+
+func (r *countingMessagesReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.total == 0 {
+			return 0, io.EOF
+		}
+		r.total--
+		r.buf = []byte(`{"string_value":"` + strings.Repeat("x", 1024) + `"}`)
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.read += n
+	return n, nil
+}
+
+func TestClientStreamServerTerminatesEarly(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	impl := &bulkCreateServer{accept: 2}
+	pb.RegisterStreamServiceServer(server, impl)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("grpc.Dial failed with %v; want success", err)
+	}
+	defer conn.Close()
+	mux := runtime.NewServeMux(runtime.WithOutgoingTrailerMatcher(func(key string) (string, bool) {
+		return runtime.MetadataTrailerPrefix + key, true
+	}))
+	if err := pb.RegisterStreamServiceHandler(context.Background(), mux, conn); err != nil {
+		t.Fatalf("pb.RegisterStreamServiceHandler failed with %v; want success", err)
+	}
+
+	const total = 100000
+	body := &countingMessagesReader{total: total}
+	req := httptest.NewRequest("POST", "http://example.com/v1/example/a_bit_of_everything/bulk", body)
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+
+	if got, want := resp.Code, runtime.HTTPStatusFromCode(codes.FailedPrecondition); got != want {
+		t.Errorf("resp.Code = %d; want %d; body %s", got, want, resp.Body)
+	}
+	if !strings.Contains(resp.Body.String(), "stop") {
+		t.Errorf("resp.Body = %s; want the server's status message %q", resp.Body, "stop")
+	}
+	if got, want := resp.Result().Trailer.Get("Grpc-Trailer-Reason"), "stop"; got != want {
+		t.Errorf("resp.Result().Trailer.Get(%q) = %q; want %q", "Grpc-Trailer-Reason", got, want)
+	}
+	if body.total == 0 {
+		t.Errorf("the gateway read the whole request body after the server terminated the stream; want it to stop early")
+	}
+}
@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+)
+
+// GatewayHandler handles a request routed to a gRPC method once its context has been annotated.
+// It returns the error the call failed with, if any, before a response was written.
+type GatewayHandler func(ctx context.Context, w http.ResponseWriter, req *http.Request) error
+
+// GatewayInterceptor wraps the GatewayHandler of each route registered by the generated code.
+//
+// An interceptor may inspect or replace the annotated context, the request and the response writer
+// before calling next, inspect the gRPC status returned by next, or return an error without calling
+// next to reply with that status instead of calling the gRPC method.
+type GatewayInterceptor func(next GatewayHandler) GatewayHandler
+
+// WithGatewayInterceptor returns a ServeMuxOption that adds interceptor to the chain run for each
+// request. Interceptors run in the order they are added, the first one being the outermost.
+func WithGatewayInterceptor(interceptor GatewayInterceptor) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.gatewayInterceptors = append(serveMux.gatewayInterceptors, interceptor)
+	}
+}
+
+// Intercept runs handler through the interceptors of mux and returns the resulting error, which the
+// caller is expected to reply with.
+//
+// This is used by generated code.
+func Intercept(ctx context.Context, mux *ServeMux, w http.ResponseWriter, req *http.Request, handler GatewayHandler) error {
+	for i := len(mux.gatewayInterceptors) - 1; i >= 0; i-- {
+		handler = mux.gatewayInterceptors[i](handler)
+	}
+	return handler(ctx, w, req)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
@@ -128,7 +129,7 @@ func (j *JSONPb) marshalNonProtoField(v interface{}) ([]byte, error) {
 
 // Unmarshal unmarshals JSON "data" into "v"
 func (j *JSONPb) Unmarshal(data []byte, v interface{}) error {
-	return unmarshalJSONPb(data, v)
+	return unmarshalJSONPb(data, v, allowUnknownFields)
 }
 
 // NewDecoder returns a Decoder which reads JSON stream from "r".
@@ -146,7 +147,7 @@ type DecoderWrapper struct {
 // Decode wraps the embedded decoder's Decode method to support
 // protos using a jsonpb.Unmarshaler.
 func (d DecoderWrapper) Decode(v interface{}) error {
-	return decodeJSONPb(d.Decoder, v)
+	return decodeJSONPb(d.Decoder, v, allowUnknownFields)
 }
 
 // NewEncoder returns an Encoder which writes JSON stream into "w".
@@ -162,21 +163,122 @@ func (j *JSONPb) NewEncoder(w io.Writer) Encoder {
 	})
 }
 
-func unmarshalJSONPb(data []byte, v interface{}) error {
+func unmarshalJSONPb(data []byte, v interface{}, allowUnknown bool) error {
 	d := json.NewDecoder(bytes.NewReader(data))
-	return decodeJSONPb(d, v)
+	return decodeJSONPb(d, v, allowUnknown)
 }
 
-func decodeJSONPb(d *json.Decoder, v interface{}) error {
+func decodeJSONPb(d *json.Decoder, v interface{}, allowUnknown bool) error {
 	p, ok := v.(proto.Message)
 	if !ok {
-		return decodeNonProtoField(d, v)
+		return decodeNonProtoField(d, v, allowUnknown)
 	}
-	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: allowUnknownFields}
-	return unmarshaler.UnmarshalNext(d, p)
+	var data json.RawMessage
+	if err := d.Decode(&data); err != nil {
+		return err
+	}
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: allowUnknown}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(data), p); err != nil {
+		if path := jsonFieldPath(data, reflect.TypeOf(p), allowUnknown); path != "" {
+			return fmt.Errorf("invalid value for field %s: %v", path, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// jsonFieldPath returns the path, e.g. "nested[0].name", of the first field of the JSON object
+// "data" which cannot be unmarshaled into a message of type "t", or "" if there is none.
+func jsonFieldPath(data []byte, t reflect.Type, allowUnknown bool) string {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return ""
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field, err := json.Marshal(map[string]json.RawMessage{k: obj[k]})
+		if err != nil {
+			return ""
+		}
+		if jsonUnmarshalsInto(field, t, allowUnknown) {
+			continue
+		}
+		return k + jsonElementPath(obj[k], jsonFieldType(t, k), allowUnknown)
+	}
+	return ""
+}
+
+// jsonElementPath returns the path, relative to a field of type "t", of the element of its JSON
+// value "data" which cannot be unmarshaled, or "" if the value as a whole is invalid.
+func jsonElementPath(data []byte, t reflect.Type, allowUnknown bool) string {
+	if t == nil {
+		return ""
+	}
+	switch {
+	case t.Implements(typeProtoMessage):
+		if path := jsonFieldPath(data, t, allowUnknown); path != "" {
+			return "." + path
+		}
+	case t.Kind() == reflect.Slice && t.Elem().Implements(typeProtoMessage):
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return ""
+		}
+		for i, e := range elems {
+			if !jsonUnmarshalsInto(e, t.Elem(), allowUnknown) {
+				return fmt.Sprintf("[%d]", i) + jsonElementPath(e, t.Elem(), allowUnknown)
+			}
+		}
+	case t.Kind() == reflect.Map && t.Elem().Implements(typeProtoMessage):
+		var elems map[string]json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return ""
+		}
+		keys := make([]string, 0, len(elems))
+		for k := range elems {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !jsonUnmarshalsInto(elems[k], t.Elem(), allowUnknown) {
+				return fmt.Sprintf("[%q]", k) + jsonElementPath(elems[k], t.Elem(), allowUnknown)
+			}
+		}
+	}
+	return ""
 }
 
-func decodeNonProtoField(d *json.Decoder, v interface{}) error {
+// jsonUnmarshalsInto reports whether the JSON value "data" can be unmarshaled into a new message of type "t".
+func jsonUnmarshalsInto(data []byte, t reflect.Type, allowUnknown bool) bool {
+	msg := reflect.New(t.Elem()).Interface().(proto.Message)
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: allowUnknown}
+	return unmarshaler.Unmarshal(bytes.NewReader(data), msg) == nil
+}
+
+// jsonFieldType returns the Go type of the field of the message type "t" whose proto or JSON name is "name".
+func jsonFieldType(t reflect.Type, name string) reflect.Type {
+	props := proto.GetProperties(t.Elem())
+	if op, ok := props.OneofTypes[name]; ok {
+		return op.Type.Elem().Field(0).Type
+	}
+	for _, p := range props.Prop {
+		if p.OrigName == name || p.JSONName == name {
+			if f, ok := t.Elem().FieldByName(p.Name); ok {
+				return f.Type
+			}
+		}
+	}
+	return nil
+}
+
+func decodeNonProtoField(d *json.Decoder, v interface{}, allowUnknown bool) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr {
 		return fmt.Errorf("%T is not a pointer", v)
@@ -186,7 +288,7 @@ func decodeNonProtoField(d *json.Decoder, v interface{}) error {
 			rv.Set(reflect.New(rv.Type().Elem()))
 		}
 		if rv.Type().ConvertibleTo(typeProtoMessage) {
-			unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: allowUnknownFields}
+			unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: allowUnknown}
 			return unmarshaler.UnmarshalNext(d, rv.Interface().(proto.Message))
 		}
 		rv = rv.Elem()
@@ -211,7 +313,7 @@ func decodeNonProtoField(d *json.Decoder, v interface{}) error {
 			}
 			bk := result[0]
 			bv := reflect.New(rv.Type().Elem())
-			if err := unmarshalJSONPb([]byte(*v), bv.Interface()); err != nil {
+			if err := unmarshalJSONPb([]byte(*v), bv.Interface(), allowUnknown); err != nil {
 				return err
 			}
 			rv.SetMapIndex(bk, bv.Elem())
@@ -257,6 +359,136 @@ var allowUnknownFields = true
 // DisallowUnknownFields enables option in decoder (unmarshaller) to
 // return an error when it finds an unknown field. This function must be
 // called before using the JSON marshaller.
+//
+// See WithRejectUnknownFields to do so for the requests of a single ServeMux.
 func DisallowUnknownFields() {
 	allowUnknownFields = false
 }
+
+// rejectUnknownFieldsJSONPb is a JSONPb whose decoders return an error naming
+// the first unknown field they find.
+type rejectUnknownFieldsJSONPb struct {
+	*JSONPb
+}
+
+// Unmarshal unmarshals JSON "data" into "v", rejecting unknown fields.
+func (j rejectUnknownFieldsJSONPb) Unmarshal(data []byte, v interface{}) error {
+	return unmarshalJSONPb(data, v, false)
+}
+
+// NewDecoder returns a Decoder which reads JSON stream from "r", rejecting unknown fields.
+func (j rejectUnknownFieldsJSONPb) NewDecoder(r io.Reader) Decoder {
+	d := json.NewDecoder(r)
+	return DecoderFunc(func(v interface{}) error { return decodeJSONPb(d, v, false) })
+}
+
+// customTypesJSONPb wraps a JSONPb inbound Marshaler so that it decodes the JSON strings given for
+// fields of the message types in decoders with them, as registered with WithCustomTypeDecoder.
+type customTypesJSONPb struct {
+	Marshaler
+	decoders customTypeDecoders
+}
+
+// Unmarshal unmarshals JSON "data" into "v", decoding the values of custom types first.
+func (j customTypesJSONPb) Unmarshal(data []byte, v interface{}) error {
+	data, err := decodeCustomTypes(data, reflect.TypeOf(v), j.decoders)
+	if err != nil {
+		return err
+	}
+	return j.Marshaler.Unmarshal(data, v)
+}
+
+// NewDecoder returns a Decoder which reads JSON stream from "r", decoding the values of custom types first.
+func (j customTypesJSONPb) NewDecoder(r io.Reader) Decoder {
+	d := json.NewDecoder(r)
+	return DecoderFunc(func(v interface{}) error {
+		var data json.RawMessage
+		if err := d.Decode(&data); err != nil {
+			return err
+		}
+		return j.Unmarshal(data, v)
+	})
+}
+
+// decodeCustomTypes returns the JSON value "data" of type "t" with the strings given for the message
+// types in decoders replaced by the JSON encoding of the messages they decode to.
+// Values it cannot make sense of are returned as is, for the unmarshaler to report.
+func decodeCustomTypes(data []byte, t reflect.Type, decoders customTypeDecoders) ([]byte, error) {
+	if t == nil {
+		return data, nil
+	}
+	// Body fields are decoded through a pointer to the field.
+	for t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t.Implements(typeProtoMessage):
+		if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			return data, nil
+		}
+		name := proto.MessageName(reflect.Zero(t).Interface().(proto.Message))
+		if decoder, ok := decoders[name]; ok {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return data, nil
+			}
+			msg, err := decoder(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad %s: %v", name, err)
+			}
+			if reflect.TypeOf(msg) != t {
+				return nil, fmt.Errorf("decoder for %s returned %T", name, msg)
+			}
+			var buf bytes.Buffer
+			if err := (&jsonpb.Marshaler{}).Marshal(&buf, msg); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return data, nil
+		}
+		changed := false
+		for k, v := range obj {
+			decoded, err := decodeCustomTypes(v, jsonFieldType(t, k), decoders)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for field %s: %v", k, err)
+			}
+			if !bytes.Equal(decoded, v) {
+				obj[k], changed = decoded, true
+			}
+		}
+		if !changed {
+			return data, nil
+		}
+		return json.Marshal(obj)
+	case t.Kind() == reflect.Slice && t.Elem().Implements(typeProtoMessage):
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return data, nil
+		}
+		for i, e := range elems {
+			decoded, err := decodeCustomTypes(e, t.Elem(), decoders)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			elems[i] = decoded
+		}
+		return json.Marshal(elems)
+	case t.Kind() == reflect.Map && t.Elem().Implements(typeProtoMessage):
+		var elems map[string]json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return data, nil
+		}
+		for k, e := range elems {
+			decoded, err := decodeCustomTypes(e, t.Elem(), decoders)
+			if err != nil {
+				return nil, fmt.Errorf("[%q]: %v", k, err)
+			}
+			elems[k] = decoded
+		}
+		return json.Marshal(elems)
+	}
+	return data, nil
+}
@@ -0,0 +1,214 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Int64Encoding selects how JSONPbInt64 encodes int64 and uint64 fields.
+type Int64Encoding int
+
+const (
+	// Int64AsString encodes 64-bit integers as JSON strings, as the proto3 JSON mapping does.
+	Int64AsString Int64Encoding = iota
+	// Int64AsNumber encodes 64-bit integers as JSON numbers.
+	Int64AsNumber
+	// Int64AsSafeNumber encodes 64-bit integers as JSON numbers if a JavaScript number
+	// represents them exactly, i.e. their magnitude is below 2^53, and as strings otherwise.
+	Int64AsSafeNumber
+)
+
+// maxSafeInteger is the largest integer which a float64 represents along with all smaller ones.
+const maxSafeInteger = 1<<53 - 1
+
+// JSONPbInt64 is a JSONPb marshaler which encodes int64 and uint64 fields, including
+// google.protobuf.Int64Value and UInt64Value, according to Encoding.
+//
+// Unmarshaling is the same as for JSONPb, which accepts both strings and numbers for these fields.
+type JSONPbInt64 struct {
+	JSONPb
+	Encoding Int64Encoding
+}
+
+// Marshal marshals "v" into JSON.
+func (j *JSONPbInt64) Marshal(v interface{}) ([]byte, error) {
+	buf, err := j.JSONPb.Marshal(v)
+	if err != nil || j.Encoding == Int64AsString {
+		return buf, err
+	}
+	if _, ok := v.(proto.Message); !ok {
+		return buf, nil
+	}
+	var out bytes.Buffer
+	if err := j.rewrite(&out, buf, reflect.TypeOf(v)); err != nil {
+		return nil, err
+	}
+	if j.Indent == "" {
+		return out.Bytes(), nil
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out.Bytes(), "", j.Indent); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// NewEncoder returns an Encoder which writes JSON stream into "w".
+func (j *JSONPbInt64) NewEncoder(w io.Writer) Encoder {
+	return EncoderFunc(func(v interface{}) error {
+		buf, err := j.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		_, err = w.Write(j.Delimiter())
+		return err
+	})
+}
+
+// rewrite copies the JSON encoding data of a value of Go type t into out,
+// re-encoding the 64-bit integers it contains. Values which do not have the
+// expected shape are copied unchanged.
+func (j *JSONPbInt64) rewrite(out *bytes.Buffer, data []byte, t reflect.Type) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64:
+		out.Write(j.encodeInteger(data))
+		return nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		return j.rewriteArray(out, data, t.Elem())
+	case t.Kind() == reflect.Map:
+		return j.rewriteObject(out, data, func(string) (reflect.Type, bool) { return t.Elem(), true })
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		return j.rewriteMessage(out, data, t)
+	}
+	out.Write(data)
+	return nil
+}
+
+func (j *JSONPbInt64) rewriteMessage(out *bytes.Buffer, data []byte, t reflect.Type) error {
+	if wkt, ok := reflect.Zero(t).Interface().(interface{ XXX_WellKnownType() string }); ok {
+		switch wkt.XXX_WellKnownType() {
+		case "Int64Value", "UInt64Value":
+			out.Write(j.encodeInteger(data))
+		default:
+			out.Write(data)
+		}
+		return nil
+	}
+	st := t.Elem()
+	props := proto.GetProperties(st)
+	fields := make(map[string]reflect.Type)
+	for i, p := range props.Prop {
+		if p.OrigName == "" {
+			continue
+		}
+		fields[p.OrigName] = st.Field(i).Type
+		if p.JSONName != "" {
+			fields[p.JSONName] = st.Field(i).Type
+		}
+	}
+	for _, oneof := range props.OneofTypes {
+		ft := oneof.Type.Elem().Field(0).Type
+		fields[oneof.Prop.OrigName] = ft
+		if oneof.Prop.JSONName != "" {
+			fields[oneof.Prop.JSONName] = ft
+		}
+	}
+	return j.rewriteObject(out, data, func(key string) (reflect.Type, bool) {
+		ft, ok := fields[key]
+		return ft, ok
+	})
+}
+
+func (j *JSONPbInt64) rewriteObject(out *bytes.Buffer, data []byte, typeOf func(key string) (reflect.Type, bool)) error {
+	if len(data) == 0 || data[0] != '{' {
+		out.Write(data)
+		return nil
+	}
+	// Decode the members one by one, so that their order is preserved.
+	d := json.NewDecoder(bytes.NewReader(data))
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+	out.WriteByte('{')
+	for i := 0; d.More(); i++ {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := d.Decode(&raw); err != nil {
+			return err
+		}
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		out.Write(k)
+		out.WriteByte(':')
+		if ft, ok := typeOf(key); ok {
+			if err := j.rewrite(out, raw, ft); err != nil {
+				return err
+			}
+		} else {
+			out.Write(raw)
+		}
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+func (j *JSONPbInt64) rewriteArray(out *bytes.Buffer, data []byte, elem reflect.Type) error {
+	var items []json.RawMessage
+	if len(data) == 0 || data[0] != '[' || json.Unmarshal(data, &items) != nil {
+		out.Write(data)
+		return nil
+	}
+	out.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if err := j.rewrite(out, item, elem); err != nil {
+			return err
+		}
+	}
+	out.WriteByte(']')
+	return nil
+}
+
+// encodeInteger re-encodes the JSON string data holding a 64-bit integer.
+func (j *JSONPbInt64) encodeInteger(data []byte) []byte {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return data
+	}
+	if j.Encoding == Int64AsSafeNumber {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			if n > maxSafeInteger || n < -maxSafeInteger {
+				return data
+			}
+		} else if n, err := strconv.ParseUint(s, 10, 64); err != nil || n > maxSafeInteger {
+			return data
+		}
+	} else {
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+				return data
+			}
+		}
+	}
+	return []byte(s)
+}
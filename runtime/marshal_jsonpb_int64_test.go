@@ -0,0 +1,104 @@
+package runtime_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
+	"github.com/ninnemana/grpc-gateway/runtime"
+)
+
+func TestJSONPbInt64Marshal(t *testing.T) {
+	msg := &examplepb.ABitOfEverything{
+		Int64Value:  1 << 60,
+		Uint64Value: 5,
+		Sint64Value: -3,
+		Uuid:        "6EC2446F-7E89-4127-B3E6-5C05E6BECBA7",
+		Nested: []*examplepb.ABitOfEverything_Nested{
+			{Name: "foo", Amount: 10},
+		},
+	}
+	for _, spec := range []struct {
+		encoding runtime.Int64Encoding
+		want     []string
+	}{
+		{
+			encoding: runtime.Int64AsString,
+			want:     []string{`"int64Value":"1152921504606846976"`, `"uint64Value":"5"`, `"sint64Value":"-3"`},
+		},
+		{
+			encoding: runtime.Int64AsNumber,
+			want:     []string{`"int64Value":1152921504606846976`, `"uint64Value":5`, `"sint64Value":-3`},
+		},
+		{
+			encoding: runtime.Int64AsSafeNumber,
+			want:     []string{`"int64Value":"1152921504606846976"`, `"uint64Value":5`, `"sint64Value":-3`},
+		},
+	} {
+		m := &runtime.JSONPbInt64{Encoding: spec.encoding}
+		buf, err := m.Marshal(msg)
+		if err != nil {
+			t.Errorf("m.Marshal(%v) failed with %v; want success", msg, err)
+			continue
+		}
+		got := string(buf)
+		for _, want := range append(spec.want, `"uuid":"6EC2446F-7E89-4127-B3E6-5C05E6BECBA7"`, `"nested":[{"name":"foo","amount":10}]`) {
+			if !strings.Contains(got, want) {
+				t.Errorf("m.Marshal(%v) = %s; want to contain %s; encoding=%v", msg, got, want, spec.encoding)
+			}
+		}
+
+		var roundTrip examplepb.ABitOfEverything
+		if err := m.Unmarshal(buf, &roundTrip); err != nil {
+			t.Errorf("m.Unmarshal(%s, &roundTrip) failed with %v; want success", buf, err)
+		} else if !proto.Equal(&roundTrip, msg) {
+			t.Errorf("roundTrip = %v; want %v", &roundTrip, msg)
+		}
+	}
+}
+
+func TestJSONPbInt64MarshalNested(t *testing.T) {
+	m := &runtime.JSONPbInt64{Encoding: runtime.Int64AsNumber, JSONPb: runtime.JSONPb{OrigName: true}}
+	for _, spec := range []struct {
+		msg  proto.Message
+		want string
+	}{
+		{
+			msg:  &examplepb.Wrappers{Int64Value: &wrappers.Int64Value{Value: 7}, StringValue: &wrappers.StringValue{Value: "8"}},
+			want: `{"string_value":"8","int64_value":7}`,
+		},
+		{
+			msg:  &wrappers.UInt64Value{Value: 9},
+			want: `9`,
+		},
+		{
+			msg:  &examplepb.ABitOfEverythingRepeated{PathRepeatedInt64Value: []int64{1, 2}},
+			want: `{"path_repeated_int64_value":[1,2]}`,
+		},
+	} {
+		buf, err := m.Marshal(spec.msg)
+		if err != nil {
+			t.Errorf("m.Marshal(%v) failed with %v; want success", spec.msg, err)
+			continue
+		}
+		if got := string(buf); got != spec.want {
+			t.Errorf("m.Marshal(%v) = %s; want %s", spec.msg, got, spec.want)
+		}
+	}
+}
+
+func TestJSONPbInt64UnmarshalAcceptsBothForms(t *testing.T) {
+	m := &runtime.JSONPbInt64{Encoding: runtime.Int64AsNumber}
+	for _, data := range []string{`{"int64Value":42}`, `{"int64Value":"42"}`} {
+		var msg examplepb.ABitOfEverything
+		if err := m.Unmarshal([]byte(data), &msg); err != nil {
+			t.Errorf("m.Unmarshal(%s, &msg) failed with %v; want success", data, err)
+			continue
+		}
+		if got, want := msg.Int64Value, int64(42); got != want {
+			t.Errorf("msg.Int64Value = %d; want %d; data=%s", got, want, data)
+		}
+	}
+}
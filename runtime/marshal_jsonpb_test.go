@@ -2,6 +2,7 @@ package runtime_test
 
 import (
 	"bytes"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -807,3 +808,54 @@ func TestJSONPbMarshalResponseBodies(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONPbDecoderFieldPath(t *testing.T) {
+	for _, spec := range []struct {
+		data string
+		path string
+	}{
+		{data: `{"int32_value":"abc"}`, path: "int32_value"},
+		{data: `{"uuid":"foo","singleNested":{"amount":"x"}}`, path: "singleNested.amount"},
+		{data: `{"nested":[{"name":"foo"},{"name":1}]}`, path: "nested[1].name"},
+		{data: `{"mapped_nested_value":{"a":{"amount":true}}}`, path: `mapped_nested_value["a"].amount`},
+		{data: `{"oneof_string":1}`, path: "oneof_string"},
+	} {
+		var m runtime.JSONPb
+		err := m.NewDecoder(strings.NewReader(spec.data)).Decode(&examplepb.ABitOfEverything{})
+		if err == nil {
+			t.Errorf("m.NewDecoder(%q).Decode(&msg) did not fail; want error", spec.data)
+			continue
+		}
+		if want := "invalid value for field " + spec.path + ": "; !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("m.NewDecoder(%q).Decode(&msg) failed with %q; want prefix %q", spec.data, err, want)
+		}
+	}
+}
+
+func TestJSONPbRejectUnknownFields(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithRejectUnknownFields())
+	for _, spec := range []struct {
+		data    string
+		wantErr string
+	}{
+		{data: `{"uuid":"foo","timestamp_value":"2019-01-02T03:04:05Z","nested":[{"name":"bar"}]}`},
+		{data: `{"uuid":"foo","foo":1}`, wantErr: `unknown field "foo"`},
+		{data: `{"single_nested":{"name":"foo","bar":1}}`, wantErr: `invalid value for field single_nested.bar: unknown field "bar"`},
+	} {
+		r, err := http.NewRequest("POST", "http://example.com", strings.NewReader(spec.data))
+		if err != nil {
+			t.Fatalf(`http.NewRequest("POST", "http://example.com", body) failed with %v; want success`, err)
+		}
+		in, _ := runtime.MarshalerForRequest(mux, r)
+		err = in.NewDecoder(r.Body).Decode(&examplepb.ABitOfEverything{})
+		if spec.wantErr == "" {
+			if err != nil {
+				t.Errorf("in.NewDecoder(%q).Decode(&msg) failed with %v; want success", spec.data, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), spec.wantErr) {
+			t.Errorf("in.NewDecoder(%q).Decode(&msg) failed with %v; want error containing %q", spec.data, err, spec.wantErr)
+		}
+	}
+}
@@ -1,14 +1,32 @@
 package runtime
 
 import (
+	"bufio"
+	"encoding/binary"
 	"io"
 
 	"errors"
+	"fmt"
 	"github.com/golang/protobuf/proto"
+	"github.com/ninnemana/grpc-gateway/internal"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
 	"io/ioutil"
 )
 
 // ProtoMarshaller is a Marshaller which marshals/unmarshals into/from serialize proto bytes
+//
+// The messages of a server-streaming response are written as frames, each made of
+// the varint-encoded length of a chunk followed by the chunk, which is the wire
+// encoding of a message equivalent to:
+//
+//	message StreamChunk {
+//	  bytes result = 1; // the serialized response message
+//	  grpc.gateway.runtime.StreamError error = 2;
+//	}
+//
+// The last chunk holds the error if the stream fails. Empty frames are sent as
+// keep-alives. ProtoStreamDecoder reads such streams.
 type ProtoMarshaller struct{}
 
 // ContentType always returns "application/octet-stream".
@@ -18,6 +36,9 @@ func (*ProtoMarshaller) ContentType() string {
 
 // Marshal marshals "value" into Proto
 func (*ProtoMarshaller) Marshal(value interface{}) ([]byte, error) {
+	if chunk, ok := value.(map[string]proto.Message); ok {
+		return marshalStreamChunk(chunk)
+	}
 	message, ok := value.(proto.Message)
 	if !ok {
 		return nil, errors.New("unable to marshal non proto field")
@@ -60,3 +81,93 @@ func (marshaller *ProtoMarshaller) NewEncoder(writer io.Writer) Encoder {
 		return nil
 	})
 }
+
+// Frame prefixes the chunk "buf" of a stream with its varint-encoded length.
+func (*ProtoMarshaller) Frame(buf []byte) []byte {
+	return append(proto.EncodeVarint(uint64(len(buf))), buf...)
+}
+
+// KeepAlive returns an empty frame.
+func (*ProtoMarshaller) KeepAlive() []byte {
+	return []byte{0}
+}
+
+// marshalStreamChunk encodes a chunk built by streamChunk or errorChunk as a StreamChunk.
+func marshalStreamChunk(chunk map[string]proto.Message) ([]byte, error) {
+	b := proto.NewBuffer(nil)
+	for tag, key := range []string{1: "result", 2: "error"} {
+		msg, ok := chunk[key]
+		if !ok {
+			continue
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.EncodeVarint(uint64(tag)<<3 | proto.WireBytes); err != nil {
+			return nil, err
+		}
+		if err := b.EncodeRawBytes(data); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// ProtoStreamDecoder reads the messages of a server-streaming response written with ProtoMarshaller.
+type ProtoStreamDecoder struct {
+	r *bufio.Reader
+}
+
+// NewProtoStreamDecoder returns a ProtoStreamDecoder which reads the stream from "r".
+func NewProtoStreamDecoder(r io.Reader) *ProtoStreamDecoder {
+	return &ProtoStreamDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next message of the stream into "msg", skipping keep-alives.
+// It returns io.EOF at the end of the stream, and the status error sent by the
+// gateway if the stream failed.
+func (d *ProtoStreamDecoder) Decode(msg proto.Message) error {
+	var n uint64
+	for n == 0 {
+		var err error
+		if n, err = binary.ReadUvarint(d.r); err != nil {
+			return err
+		}
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	errMalformed := errors.New("malformed stream chunk")
+	for len(data) > 0 {
+		key, k := binary.Uvarint(data)
+		if k <= 0 {
+			return errMalformed
+		}
+		if key&7 != proto.WireBytes {
+			return fmt.Errorf("unexpected wire type %d in stream chunk", key&7)
+		}
+		l, m := binary.Uvarint(data[k:])
+		if m <= 0 || l > uint64(len(data)-k-m) {
+			return errMalformed
+		}
+		field := data[k+m : k+m+int(l)]
+		data = data[k+m+int(l):]
+		switch key >> 3 {
+		case 1:
+			return proto.Unmarshal(field, msg)
+		case 2:
+			serr := &internal.StreamError{}
+			if err := proto.Unmarshal(field, serr); err != nil {
+				return err
+			}
+			return status.ErrorProto(&spb.Status{Code: serr.GrpcCode, Message: serr.Message, Details: serr.Details})
+		}
+	}
+	return errors.New("stream chunk has neither a result nor an error")
+}
@@ -2,12 +2,17 @@ package runtime_test
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
 	"github.com/ninnemana/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var message = &examplepb.ABitOfEverything{
@@ -89,3 +94,50 @@ func TestProtoEncoderDecodert(t *testing.T) {
 		)
 	}
 }
+
+func TestProtoStream(t *testing.T) {
+	msgs := []proto.Message{
+		&examplepb.SimpleMessage{Id: "One"},
+		&examplepb.SimpleMessage{},
+		&examplepb.SimpleMessage{Id: "Three"},
+	}
+	var count int
+	recv := func() (proto.Message, error) {
+		if count == len(msgs) {
+			return nil, status.Error(codes.OutOfRange, "no more messages")
+		}
+		count++
+		return msgs[count-1], nil
+	}
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	resp := httptest.NewRecorder()
+	runtime.ForwardResponseStream(ctx, runtime.NewServeMux(), &runtime.ProtoMarshaller{}, resp, req, recv)
+
+	// Keep-alives are skipped.
+	body := append((&runtime.ProtoMarshaller{}).KeepAlive(), resp.Body.Bytes()...)
+	decoder := runtime.NewProtoStreamDecoder(bytes.NewReader(body))
+	for i, want := range msgs {
+		got := &examplepb.SimpleMessage{}
+		if err := decoder.Decode(got); err != nil {
+			t.Fatalf("decoder.Decode() #%d failed with %v; want success", i, err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("decoder.Decode() #%d = %v; want %v", i, got, want)
+		}
+	}
+	err := decoder.Decode(&examplepb.SimpleMessage{})
+	if st := status.Convert(err); st.Code() != codes.OutOfRange || st.Message() != "no more messages" {
+		t.Errorf("decoder.Decode() after the last message failed with %v; want the stream error", err)
+	}
+	if err := decoder.Decode(&examplepb.SimpleMessage{}); err != io.EOF {
+		t.Errorf("decoder.Decode() at the end of the stream failed with %v; want %v", err, io.EOF)
+	}
+	trailer := resp.Result().Trailer
+	if got, want := trailer.Get("Grpc-Status"), "11"; got != want {
+		t.Errorf("trailer.Get(%q) = %q; want %q", "Grpc-Status", got, want)
+	}
+	if got, want := trailer.Get("Grpc-Message"), "no more messages"; got != want {
+		t.Errorf("trailer.Get(%q) = %q; want %q", "Grpc-Message", got, want)
+	}
+}
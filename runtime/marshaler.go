@@ -16,6 +16,7 @@ type Marshaler interface {
 	// NewEncoder returns an Encoder which writes bytes sequence into "w".
 	NewEncoder(w io.Writer) Encoder
 	// ContentType returns the Content-Type which this marshaler is responsible for.
+	// It is set verbatim on responses, so it may include parameters such as a charset.
 	ContentType() string
 }
 
@@ -46,3 +47,70 @@ type Delimited interface {
 	// Delimiter returns the record seperator for the stream.
 	Delimiter() []byte
 }
+
+// StreamKeepAlive defines the keep-alive written to idle streams.
+type StreamKeepAlive interface {
+	// KeepAlive returns the bytes written to a stream which has not sent a
+	// message for the interval set by WithStreamKeepAlive, e.g. a comment
+	// line for server-sent events. They must not break the stream's framing.
+	KeepAlive() []byte
+}
+
+// StreamFramer is implemented by marshalers which frame the messages of a stream themselves,
+// instead of following each of them with a delimiter.
+type StreamFramer interface {
+	// Frame returns the bytes written to the stream for "buf", a marshaled message.
+	Frame(buf []byte) []byte
+}
+
+// CharsetMarshaler is implemented by text marshalers which can write their output in charsets other
+// than their default one. See WithAcceptCharset.
+type CharsetMarshaler interface {
+	// Charsets returns the charsets the marshaler can write, its default one first, e.g. "utf-8".
+	Charsets() []string
+	// WithCharset returns a Marshaler which writes its output in charset, one of Charsets, and
+	// advertises it in its content type.
+	WithCharset(charset string) Marshaler
+}
+
+// streamFramerOf returns the StreamFramer of m, looking through a ContentTypeMarshaler.
+func streamFramerOf(m Marshaler) (StreamFramer, bool) {
+	if ctm, ok := m.(*ContentTypeMarshaler); ok {
+		m = ctm.Marshaler
+	}
+	f, ok := m.(StreamFramer)
+	return f, ok
+}
+
+// ContentTypeMarshaler wraps a Marshaler to advertise a full Content-Type, including parameters
+// such as "application/json; charset=utf-8". The gateway sets it verbatim on responses.
+type ContentTypeMarshaler struct {
+	Marshaler
+	// Type is the Content-Type returned by ContentType.
+	Type string
+}
+
+// ContentType returns m.Type.
+func (m *ContentTypeMarshaler) ContentType() string {
+	return m.Type
+}
+
+// Delimiter returns the delimiter of the wrapped marshaler, or a newline if it is not Delimited.
+func (m *ContentTypeMarshaler) Delimiter() []byte {
+	if d, ok := m.Marshaler.(Delimited); ok {
+		return d.Delimiter()
+	}
+	return []byte("\n")
+}
+
+// KeepAlive returns the keep-alive of the wrapped marshaler, or the empty line used for
+// newline-delimited streams.
+func (m *ContentTypeMarshaler) KeepAlive() []byte {
+	if ka, ok := m.Marshaler.(StreamKeepAlive); ok {
+		return ka.KeepAlive()
+	}
+	if d := m.Delimiter(); string(d) == "\n" {
+		return d
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package runtime
 
 import (
+	"bufio"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // MIMEWildcard is the fallback MIME type used for requests which do not match
@@ -10,10 +14,17 @@ import (
 const MIMEWildcard = "*"
 
 var (
-	acceptHeader      = http.CanonicalHeaderKey("Accept")
-	contentTypeHeader = http.CanonicalHeaderKey("Content-Type")
+	acceptHeader        = http.CanonicalHeaderKey("Accept")
+	acceptCharsetHeader = http.CanonicalHeaderKey("Accept-Charset")
+	contentTypeHeader   = http.CanonicalHeaderKey("Content-Type")
 
 	defaultMarshaler = &JSONPb{OrigName: true}
+
+	// altMIMETypes maps the short values of the alt parameter to MIME types.
+	altMIMETypes = map[string]string{
+		"json":  "application/json",
+		"proto": "application/x-protobuf",
+	}
 )
 
 // MarshalerForRequest returns the inbound/outbound marshalers for this request.
@@ -22,11 +33,53 @@ var (
 // If there are multiple Content-Type headers set, choose the first one that it can
 // exactly match in the registry.
 // Otherwise, it follows the above logic for "*"/InboundMarshaler/OutboundMarshaler.
+//
+// If the mux was created with WithAcceptCharset and the outbound marshaler is a CharsetMarshaler,
+// it is switched to the charset preferred by the Accept-Charset header.
+//
+// If the mux has an alt parameter set with WithAltParameter which names a registered MIME
+// type, its marshaler is used as the outbound marshaler instead of the Accept header.
 func MarshalerForRequest(mux *ServeMux, r *http.Request) (inbound Marshaler, outbound Marshaler) {
-	for _, acceptVal := range r.Header[acceptHeader] {
-		if m, ok := mux.marshalers.mimeMap[acceptVal]; ok {
-			outbound = m
-			break
+	inbound, outbound = registeredMarshalersForRequest(mux, r)
+	if cm, ok := outbound.(CharsetMarshaler); ok && mux.acceptCharset {
+		charsets := cm.Charsets()
+		if cs, ok := negotiateCharset(r.Header.Get(acceptCharsetHeader), charsets); ok && cs != charsets[0] {
+			outbound = cm.WithCharset(cs)
+		}
+	}
+	if j, ok := inbound.(*JSONPb); ok && mux.rejectUnknownFields {
+		inbound = rejectUnknownFieldsJSONPb{j}
+	}
+	if len(mux.customTypeDecoders) > 0 {
+		switch inbound.(type) {
+		case *JSONPb, rejectUnknownFieldsJSONPb:
+			inbound = customTypesJSONPb{inbound, mux.customTypeDecoders}
+		}
+	}
+	if mux.allowEmptyBody {
+		inbound = emptyBodyMarshaler{inbound}
+	}
+
+	return inbound, outbound
+}
+
+// registeredMarshalersForRequest returns the marshalers registered on mux for the MIME types r
+// asks for, as described in MarshalerForRequest, without adapting them to r.
+func registeredMarshalersForRequest(mux *ServeMux, r *http.Request) (inbound Marshaler, outbound Marshaler) {
+	if mux.altParameter != "" {
+		if alt := r.URL.Query().Get(mux.altParameter); alt != "" {
+			if mime, ok := altMIMETypes[alt]; ok {
+				alt = mime
+			}
+			outbound = mux.marshalers.mimeMap[alt]
+		}
+	}
+	if outbound == nil {
+		for _, acceptVal := range r.Header[acceptHeader] {
+			if m, ok := mux.marshalers.mimeMap[acceptVal]; ok {
+				outbound = m
+				break
+			}
 		}
 	}
 
@@ -43,10 +96,23 @@ func MarshalerForRequest(mux *ServeMux, r *http.Request) (inbound Marshaler, out
 	if outbound == nil {
 		outbound = inbound
 	}
-
 	return inbound, outbound
 }
 
+// emptyBodyMarshaler wraps an inbound Marshaler so that its decoders report io.EOF for an empty
+// body, which generated code treats as the zero value of the request message.
+type emptyBodyMarshaler struct {
+	Marshaler
+}
+
+func (m emptyBodyMarshaler) NewDecoder(r io.Reader) Decoder {
+	br := bufio.NewReader(r)
+	if _, err := br.Peek(1); err == io.EOF {
+		return DecoderFunc(func(interface{}) error { return io.EOF })
+	}
+	return m.Marshaler.NewDecoder(br)
+}
+
 // marshalerRegistry is a mapping from MIME types to Marshalers.
 type marshalerRegistry struct {
 	mimeMap map[string]Marshaler
@@ -89,3 +155,118 @@ func WithMarshalerOption(mime string, marshaler Marshaler) ServeMuxOption {
 		}
 	}
 }
+
+// WithAltParameter returns a ServeMuxOption which lets clients choose the outbound marshaler
+// with the query parameter name, overriding the Accept header, like the "alt" parameter of
+// Google APIs. Its value is a MIME type registered with WithMarshalerOption, or "json" or
+// "proto" for "application/json" and "application/x-protobuf". Unregistered values are ignored.
+func WithAltParameter(name string) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.altParameter = name
+	}
+}
+
+// WithRejectUnknownFields returns a ServeMuxOption which makes the JSONPb inbound marshalers of
+// the mux reject request bodies with unknown fields, with an error naming the first one found,
+// which generated handlers reply to with codes.InvalidArgument. It is like DisallowUnknownFields,
+// but only applies to this mux. Other marshalers are unaffected.
+func WithRejectUnknownFields() ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.rejectUnknownFields = true
+	}
+}
+
+// WithUseProtoNames returns a ServeMuxOption which makes the JSONPb marshalers registered on the mux,
+// including the default one, write the original proto names of fields, e.g. "string_value", if use is
+// true, or their lowerCamelCase JSON names, e.g. "stringValue", if it is false. It applies to the
+// marshalers registered with WithMarshalerOption too, regardless of the order of the options, and
+// leaves the marshalers themselves unchanged. Unmarshaling accepts both names either way.
+func WithUseProtoNames(use bool) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.useProtoNames = &use
+	}
+}
+
+// applyUseProtoNames replaces the JSONPb marshalers of mux with copies using the field names
+// set with WithUseProtoNames.
+func applyUseProtoNames(mux *ServeMux) {
+	if mux.useProtoNames == nil {
+		return
+	}
+	for mime, m := range mux.marshalers.mimeMap {
+		if j, ok := m.(*JSONPb); ok {
+			c := *j
+			c.OrigName = *mux.useProtoNames
+			mux.marshalers.mimeMap[mime] = &c
+		}
+	}
+}
+
+// WithAcceptCharset returns a ServeMuxOption which makes the mux honor the Accept-Charset header
+// of requests. CharsetMarshalers write the response in the charset the client prefers, and the
+// built-in JSON marshalers, which always write UTF-8, reject requests which do not accept it.
+// Requests accepting none of the charsets of their outbound marshaler are replied to with
+// http.StatusNotAcceptable. Marshalers of binary formats ignore the header.
+func WithAcceptCharset() ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.acceptCharset = true
+	}
+}
+
+// outboundCharsets returns the charsets the outbound marshaler m can write, or nil if m does not
+// write text, or may write any.
+func outboundCharsets(m Marshaler) []string {
+	if cm, ok := m.(CharsetMarshaler); ok {
+		return cm.Charsets()
+	}
+	if ctm, ok := m.(*ContentTypeMarshaler); ok {
+		m = ctm.Marshaler
+	}
+	switch m.(type) {
+	case *JSONPb, *JSONBuiltin:
+		return []string{"utf-8"}
+	}
+	return nil
+}
+
+// negotiateCharset returns the charset in supported which the Accept-Charset header value header
+// prefers, or false if it accepts none of them. An empty header accepts the first one.
+func negotiateCharset(header string, supported []string) (string, bool) {
+	if strings.TrimSpace(header) == "" {
+		return supported[0], true
+	}
+	wildcard := -1.0
+	explicit := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[len("q="):], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name == "*" {
+			wildcard = q
+		} else {
+			explicit[name] = q
+		}
+	}
+	var best string
+	var bestQ float64
+	for _, cs := range supported {
+		q, ok := explicit[strings.ToLower(cs)]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = cs, q
+		}
+	}
+	return best, bestQ > 0
+}
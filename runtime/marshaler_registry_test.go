@@ -4,8 +4,11 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
 	"github.com/ninnemana/grpc-gateway/runtime"
 )
 
@@ -105,3 +108,122 @@ type dummyEncoder struct{}
 func (dummyEncoder) Encode(interface{}) error {
 	return errors.New("not implemented")
 }
+
+func TestMarshalerForRequestAllowEmptyBody(t *testing.T) {
+	for _, spec := range []struct {
+		name    string
+		opts    []runtime.ServeMuxOption
+		body    string
+		wantEOF bool
+	}{
+		{
+			name: "disabled",
+			body: "",
+		},
+		{
+			name:    "empty body",
+			opts:    []runtime.ServeMuxOption{runtime.WithAllowEmptyBody()},
+			body:    "",
+			wantEOF: true,
+		},
+		{
+			name: "non-empty body",
+			opts: []runtime.ServeMuxOption{runtime.WithAllowEmptyBody()},
+			body: "{}",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			opts := append([]runtime.ServeMuxOption{runtime.WithMarshalerOption(runtime.MIMEWildcard, dummyMarshaler{})}, spec.opts...)
+			mux := runtime.NewServeMux(opts...)
+			r, err := http.NewRequest("POST", "http://example.com", strings.NewReader(spec.body))
+			if err != nil {
+				t.Fatalf(`http.NewRequest("POST", "http://example.com", body) failed with %v; want success`, err)
+			}
+			in, _ := runtime.MarshalerForRequest(mux, r)
+			err = in.NewDecoder(r.Body).Decode(nil)
+			if got := err == io.EOF; got != spec.wantEOF {
+				t.Errorf("in.NewDecoder(r.Body).Decode(nil) = %v; want io.EOF %v", err, spec.wantEOF)
+			}
+		})
+	}
+}
+
+func TestMarshalerForRequestAltParameter(t *testing.T) {
+	marshaler := func(name string) runtime.Marshaler {
+		return &runtime.ContentTypeMarshaler{Marshaler: dummyMarshaler{}, Type: name}
+	}
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler("wildcard")),
+		runtime.WithMarshalerOption("application/x-protobuf", marshaler("proto")),
+		runtime.WithMarshalerOption("application/x-out", marshaler("out")),
+		runtime.WithAltParameter("alt"),
+	)
+	for _, spec := range []struct {
+		url     string
+		wantOut string
+	}{
+		{url: "http://example.com", wantOut: "out"},
+		{url: "http://example.com?alt=proto", wantOut: "proto"},
+		{url: "http://example.com?alt=application/x-protobuf", wantOut: "proto"},
+		{url: "http://example.com?alt=xml", wantOut: "out"},
+	} {
+		r, err := http.NewRequest("GET", spec.url, nil)
+		if err != nil {
+			t.Fatalf(`http.NewRequest("GET", %q, nil) failed with %v; want success`, spec.url, err)
+		}
+		r.Header.Set("Accept", "application/x-out")
+
+		in, out := runtime.MarshalerForRequest(mux, r)
+		if got, want := in.ContentType(), "wildcard"; got != want {
+			t.Errorf("in.ContentType() = %q for %s; want %q", got, spec.url, want)
+		}
+		if got := out.ContentType(); got != spec.wantOut {
+			t.Errorf("out.ContentType() = %q for %s; want %q", got, spec.url, spec.wantOut)
+		}
+	}
+}
+
+func TestUseProtoNames(t *testing.T) {
+	msg := &examplepb.ABitOfEverything{StringValue: "foo", RepeatedStringValue: []string{"bar"}}
+	for _, spec := range []struct {
+		use  bool
+		want string
+	}{
+		{use: true, want: `{"string_value":"foo","repeated_string_value":["bar"]}`},
+		{use: false, want: `{"stringValue":"foo","repeatedStringValue":["bar"]}`},
+	} {
+		mux := runtime.NewServeMux(
+			runtime.WithUseProtoNames(spec.use),
+			runtime.WithMarshalerOption("application/x-json", &runtime.JSONPb{OrigName: !spec.use}),
+		)
+		for _, accept := range []string{"", "application/x-json"} {
+			r, err := http.NewRequest("GET", "http://example.com", nil)
+			if err != nil {
+				t.Fatalf(`http.NewRequest("GET", "http://example.com", nil) failed with %v; want success`, err)
+			}
+			r.Header.Set("Accept", accept)
+			r.Header.Set("Content-Type", accept)
+			in, out := runtime.MarshalerForRequest(mux, r)
+
+			buf, err := out.Marshal(msg)
+			if err != nil {
+				t.Fatalf("out.Marshal(%v) failed with %v; want success", msg, err)
+			}
+			if got := string(buf); got != spec.want {
+				t.Errorf("out.Marshal(%v) = %s with use = %t, Accept %q; want %s", msg, got, spec.use, accept, spec.want)
+			}
+
+			// Both names are accepted on input, and may be mixed.
+			for _, body := range []string{string(buf), `{"string_value":"foo","repeatedStringValue":["bar"]}`} {
+				var got examplepb.ABitOfEverything
+				if err := in.Unmarshal([]byte(body), &got); err != nil {
+					t.Errorf("in.Unmarshal(%s) failed with %v; want success", body, err)
+					continue
+				}
+				if !proto.Equal(&got, msg) {
+					t.Errorf("in.Unmarshal(%s) = %v; want %v", body, &got, msg)
+				}
+			}
+		}
+	}
+}
@@ -1,18 +1,64 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
 	"net/http"
 	"net/textproto"
+	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/rogpeppe/fastuuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// Logger is the interface through which a ServeMux logs the problems it runs into while
+// serving requests, see WithLogger. The methods format their arguments like fmt.Printf.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// grpcLogger is the Logger of a ServeMux without WithLogger, which logs to grpclog.
+type grpcLogger struct{}
+
+func (grpcLogger) Infof(format string, args ...interface{})  { grpclog.Infof(format, args...) }
+func (grpcLogger) Errorf(format string, args ...interface{}) { grpclog.Errorf(format, args...) }
+
+// loggerOf returns the Logger set on mux with WithLogger, or one logging to grpclog.
+func loggerOf(mux *ServeMux) Logger {
+	if mux == nil || mux.logger == nil {
+		return grpcLogger{}
+	}
+	return mux.logger
+}
+
+// Logger returns the Logger of the mux, see WithLogger. The generated handlers log through it.
+func (s *ServeMux) Logger() Logger {
+	return loggerOf(s)
+}
+
+// LoggerFromContext returns the Logger of the mux the request ctx comes from, see WithLogger,
+// or one logging to grpclog if ctx does not come from a request dispatched by ServeMux.
+func LoggerFromContext(ctx context.Context) Logger {
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	return loggerOf(mux)
+}
+
 // A HandlerFunc handles a specific pair of path pattern and HTTP method.
 type HandlerFunc func(w http.ResponseWriter, r *http.Request, pathParams map[string]string)
 
@@ -28,16 +74,88 @@ var ErrUnknownURI = status.Error(codes.Unimplemented, http.StatusText(http.Statu
 // It matches http requests to patterns and invokes the corresponding handler.
 type ServeMux struct {
 	// handlers maps HTTP method to a list of handlers.
-	handlers                  map[string][]handler
-	forwardResponseOptions    []func(context.Context, http.ResponseWriter, proto.Message) error
-	marshalers                marshalerRegistry
-	incomingHeaderMatcher     HeaderMatcherFunc
-	outgoingHeaderMatcher     HeaderMatcherFunc
-	metadataAnnotators        []func(context.Context, *http.Request) metadata.MD
-	streamErrorHandler        StreamErrorHandlerFunc
-	protoErrorHandler         ProtoErrorHandlerFunc
-	disablePathLengthFallback bool
-	lastMatchWins             bool
+	handlers                   map[string][]handler
+	forwardResponseOptions     []func(context.Context, http.ResponseWriter, proto.Message) error
+	marshalers                 marshalerRegistry
+	incomingHeaderMatcher      HeaderMatcherFunc
+	metadataPrefix             string
+	outgoingHeaderMatcher      HeaderMatcherFunc
+	outgoingTrailerMatcher     HeaderMatcherFunc
+	metadataAnnotators         []func(context.Context, *http.Request, metadata.MD) metadata.MD
+	streamErrorHandler         StreamErrorHandlerFunc
+	protoErrorHandler          ProtoErrorHandlerFunc
+	disablePathLengthFallback  bool
+	lastMatchWins              bool
+	streamingDeadlineExempt    map[string]bool
+	partialStreamOnDeadline    map[string]bool
+	streamKeepAlive            time.Duration
+	requestIDGenerator         func() string
+	accessLogger               func(AccessLogRecord)
+	requestObserver            RequestObserverFunc
+	recoveryHandler            RecoveryHandlerFunc
+	logger                     Logger
+	middlewares                []func(http.Handler) http.Handler
+	etagGenerator              func(proto.Message) string
+	lastModified               func(proto.Message) time.Time
+	successStatusMapper        func(string, proto.Message) int
+	emptyResponseBody          *bool
+	locationResolver           func(string, proto.Message) string
+	ifMatchPreconditionFailed  bool
+	errorStatusTable           map[codes.Code]int
+	requestValidator           func(proto.Message) error
+	responseTransformer        func(context.Context, proto.Message) (proto.Message, error)
+	requestTransformer         func(context.Context, proto.Message) error
+	callOptions                func(context.Context, *http.Request) []grpc.CallOption
+	authority                  func(*http.Request) string
+	authorizationCookie        string
+	authorizationStripped      map[string]bool
+	cookieMatcher              CookieMatcherFunc
+	decodeBasicAuth            bool
+	requireContentType         bool
+	allowEmptyBody             bool
+	queryDefaults              map[string]map[string]string
+	queryOverridesBody         bool
+	bracketQueryNotation       bool
+	customTypeDecoders         customTypeDecoders
+	clientConn                 atomic.Value
+	getRetryAttempts           int
+	getRetryBackoff            time.Duration
+	callWrapper                func(context.Context, func() error) error
+	rateLimiter                func(context.Context, string, string) error
+	deadlineDiagnostics        bool
+	forwardStatusDetails       bool
+	preserveIncomingMetadata   bool
+	disableHTTPRequestMetadata bool
+	forwardSourcePort          bool
+	forwardedHeaderPrecedence  ForwardedHeaderPrecedence
+	timeoutHeader              string
+	timeoutHeaderPrecedence    TimeoutHeaderPrecedence
+	defaultTimeout             *time.Duration
+	defaultOptionsHandler      bool
+	grpcWeb                    bool
+	grpcWebHandlers            map[string]http.HandlerFunc
+	grpcWebTrailers            GRPCWebTrailerFunc
+	binaryHeaderDecoder        func(string) ([]byte, error)
+	maxMetadataPairs           int
+	maxMetadataBytes           int
+	maxRequestBodyBytes        int64
+	rawBodyMetadataKey         string
+	acceptCharset              bool
+	useProtoNames              *bool
+	livenessPath               string
+	responseCacheGet           func(context.Context, *http.Request) ([]byte, bool)
+	responseCacheSet           func(context.Context, *http.Request, []byte)
+	responseHeaderAnnotators   []func(context.Context, *http.Request) http.Header
+	gatewayInterceptors        []GatewayInterceptor
+	serverTiming               bool
+	requests                   requestTracker
+	autoFieldMask              string
+	trailingSlashPolicy        TrailingSlashPolicy
+	caseInsensitivePaths       bool
+	caseInsensitiveEnums       bool
+	routes                     []RouteInfo
+	altParameter               string
+	rejectUnknownFields        bool
 }
 
 // ServeMuxOption is an option that can be given to a ServeMux on construction.
@@ -62,9 +180,14 @@ type HeaderMatcherFunc func(string) (string, bool)
 // keys (as specified by the IANA) to gRPC context with grpcgateway- prefix. HTTP headers that start with
 // 'Grpc-Metadata-' are mapped to gRPC metadata after removing prefix 'Grpc-Metadata-'.
 func DefaultHeaderMatcher(key string) (string, bool) {
+	return matchHeader(MetadataPrefix, key)
+}
+
+// matchHeader is DefaultHeaderMatcher with prefix instead of MetadataPrefix.
+func matchHeader(prefix, key string) (string, bool) {
 	key = textproto.CanonicalMIMEHeaderKey(key)
 	if isPermanentHTTPHeader(key) {
-		return MetadataPrefix + key, true
+		return prefix + key, true
 	} else if strings.HasPrefix(key, MetadataHeaderPrefix) {
 		return key[len(MetadataHeaderPrefix):], true
 	}
@@ -81,6 +204,16 @@ func WithIncomingHeaderMatcher(fn HeaderMatcherFunc) ServeMuxOption {
 	}
 }
 
+// WithMetadataPrefix returns a ServeMuxOption that makes the default incoming header matcher of the
+// mux forward permanent HTTP headers with prefix instead of MetadataPrefix, e.g. "edge-" to tell them
+// from those forwarded by another gateway in front of the same servers. It has no effect on a matcher
+// set with WithIncomingHeaderMatcher.
+func WithMetadataPrefix(prefix string) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.metadataPrefix = prefix
+	}
+}
+
 // WithOutgoingHeaderMatcher returns a ServeMuxOption representing a headerMatcher for outgoing response from gateway.
 //
 // This matcher will be called with each header in response header metadata. If matcher returns true, that header will be
@@ -92,16 +225,60 @@ func WithOutgoingHeaderMatcher(fn HeaderMatcherFunc) ServeMuxOption {
 	}
 }
 
+// WithOutgoingTrailerMatcher returns a ServeMuxOption representing a headerMatcher for the trailer
+// metadata of the gRPC response, like WithOutgoingHeaderMatcher is for its header metadata.
+//
+// This matcher will be called with each key in response trailer metadata. If matcher returns true, that trailer
+// will be passed to http response returned from gateway as an HTTP trailer named with the returned key.
+// By default only "grpc-status" and "grpc-message" are passed, with the MetadataTrailerPrefix prefix.
+func WithOutgoingTrailerMatcher(fn HeaderMatcherFunc) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.outgoingTrailerMatcher = fn
+	}
+}
+
 // WithMetadata returns a ServeMuxOption for passing metadata to a gRPC context.
 //
 // This can be used by services that need to read from http.Request and modify gRPC context. A common use case
 // is reading token from cookie and adding it in gRPC context.
 func WithMetadata(annotator func(context.Context, *http.Request) metadata.MD) ServeMuxOption {
+	return WithChainedMetadata(func(ctx context.Context, req *http.Request, _ metadata.MD) metadata.MD {
+		return annotator(ctx, req)
+	})
+}
+
+// WithChainedMetadata returns a ServeMuxOption like WithMetadata whose annotator also receives a copy of
+// the metadata accumulated so far: the metadata AnnotateContext built from the request and the metadata
+// returned by the annotators registered before it, with either option. The metadata it returns is
+// joined to them, so it can derive values from them, e.g. a signature of previously added metadata.
+func WithChainedMetadata(annotator func(ctx context.Context, req *http.Request, acc metadata.MD) metadata.MD) ServeMuxOption {
 	return func(serveMux *ServeMux) {
 		serveMux.metadataAnnotators = append(serveMux.metadataAnnotators, annotator)
 	}
 }
 
+// WithRouteMetadata returns a ServeMuxOption like WithMetadata whose annotator only runs for requests
+// dispatched to the route with the path template pattern, as reported by HTTPPathPattern,
+// e.g. "/v1/admin/{name=*}". Annotators run after routing, so the pattern is always known by then.
+func WithRouteMetadata(pattern string, annotator func(context.Context, *http.Request) metadata.MD) ServeMuxOption {
+	return WithChainedMetadata(func(ctx context.Context, req *http.Request, _ metadata.MD) metadata.MD {
+		if pat, ok := HTTPPathPattern(req.Context()); !ok || pat != pattern {
+			return nil
+		}
+		return annotator(ctx, req)
+	})
+}
+
+// WithResponseHeaderAnnotator returns a ServeMuxOption for adding headers to the HTTP response.
+//
+// The headers returned by annotator for each request matching a registered pattern are added
+// to the response before the handler runs, so they are sent with error replies too.
+func WithResponseHeaderAnnotator(annotator func(context.Context, *http.Request) http.Header) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.responseHeaderAnnotators = append(serveMux.responseHeaderAnnotators, annotator)
+	}
+}
+
 // WithProtoErrorHandler returns a ServeMuxOption for passing metadata to a gRPC context.
 //
 // This can be used to handle an error as general proto message defined by gRPC.
@@ -120,6 +297,57 @@ func WithDisablePathLengthFallback() ServeMuxOption {
 	}
 }
 
+// WithDefaultOptionsHandler returns a ServeMuxOption that replies to an OPTIONS request for a path which
+// has routes, but none for OPTIONS, with http.StatusNoContent and an Allow header listing the methods
+// of those routes, instead of an error. It is meant for clients which probe endpoints; use a CORS
+// handler in front of the ServeMux for preflight requests.
+func WithDefaultOptionsHandler() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.defaultOptionsHandler = true
+	}
+}
+
+// WithCaseInsensitivePaths returns a ServeMuxOption that matches the literal segments of request paths
+// against the path templates regardless of case, so that "/V1/Books/Foo" matches "/v1/books/{name}".
+// Values captured by path variables, and verbs, are still matched and passed on verbatim.
+func WithCaseInsensitivePaths() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.caseInsensitivePaths = true
+	}
+}
+
+// WithCaseInsensitiveEnums returns a ServeMuxOption that makes the enum fields set from path and
+// query parameters accept the names of their values regardless of case, e.g. "active" for "ACTIVE".
+func WithCaseInsensitiveEnums() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.caseInsensitiveEnums = true
+	}
+}
+
+// TrailingSlashPolicy determines how a ServeMux handles a request path with a trailing slash
+// which matches no route, e.g. "/v1/books/" when only "/v1/books" is registered.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashStrict treats the paths as different, so the request is not found.
+	TrailingSlashStrict TrailingSlashPolicy = iota
+	// TrailingSlashRedirect redirects the request to the path without the trailing slash,
+	// keeping the query string. GET and HEAD requests are redirected with
+	// http.StatusMovedPermanently, others with http.StatusPermanentRedirect so that
+	// clients repeat the method and body.
+	TrailingSlashRedirect
+	// TrailingSlashIgnore serves the request as if the path had no trailing slash.
+	TrailingSlashIgnore
+)
+
+// WithTrailingSlashPolicy returns a ServeMuxOption that sets how request paths with a trailing
+// slash are handled. The default is TrailingSlashStrict.
+func WithTrailingSlashPolicy(policy TrailingSlashPolicy) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.trailingSlashPolicy = policy
+	}
+}
+
 // WithStreamErrorHandler returns a ServeMuxOption that will use the given custom stream
 // error handler, which allows for customizing the error trailer for server-streaming
 // calls.
@@ -143,161 +371,1421 @@ func WithLastMatchWins() ServeMuxOption {
 	}
 }
 
-// NewServeMux returns a new ServeMux whose internal mapping is empty.
-func NewServeMux(opts ...ServeMuxOption) *ServeMux {
-	serveMux := &ServeMux{
-		handlers:               make(map[string][]handler),
-		forwardResponseOptions: make([]func(context.Context, http.ResponseWriter, proto.Message) error, 0),
-		marshalers:             makeMarshalerMIMERegistry(),
-		streamErrorHandler:     DefaultHTTPStreamErrorHandler,
+// WithStreamingDeadlineExempt returns a ServeMuxOption that exempts the routes
+// with the given path templates (e.g. "/v1/example/stream") from the
+// default timeout (see DefaultTimeout). This is meant for long-poll streaming endpoints that
+// would otherwise be closed after the default timeout.
+//
+// An explicit Grpc-Timeout header sent by the client is still honored.
+func WithStreamingDeadlineExempt(patterns ...string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.streamingDeadlineExempt == nil {
+			serveMux.streamingDeadlineExempt = make(map[string]bool)
+		}
+		for _, p := range patterns {
+			serveMux.streamingDeadlineExempt[p] = true
+		}
 	}
+}
 
-	for _, opt := range opts {
-		opt(serveMux)
+// WithPartialStreamOnDeadline returns a ServeMuxOption that makes the server-streaming
+// routes with the given path templates (e.g. "/v1/example/aggregate") end their
+// responses cleanly when the deadline of the call passes, instead of with a
+// codes.DeadlineExceeded error. The messages received so far are kept, and a
+// Warning header, or trailer once the response has started, tells the client
+// the response was truncated. This is meant for best-effort aggregation
+// endpoints, whose partial results are still useful.
+func WithPartialStreamOnDeadline(patterns ...string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.partialStreamOnDeadline == nil {
+			serveMux.partialStreamOnDeadline = make(map[string]bool)
+		}
+		for _, p := range patterns {
+			serveMux.partialStreamOnDeadline[p] = true
+		}
 	}
+}
 
-	if serveMux.protoErrorHandler != nil {
-		HTTPError = serveMux.protoErrorHandler
-		// OtherErrorHandler is no longer used when protoErrorHandler is set.
-		// Overwritten by a special error handler to return Unknown.
-		OtherErrorHandler = func(w http.ResponseWriter, r *http.Request, _ string, _ int) {
-			ctx := context.Background()
-			_, outboundMarshaler := MarshalerForRequest(serveMux, r)
-			sterr := status.Error(codes.Unknown, "unexpected use of OtherErrorHandler")
-			serveMux.protoErrorHandler(ctx, serveMux, outboundMarshaler, w, r, sterr)
+// WithStreamKeepAlive returns a ServeMuxOption that writes a keep-alive to
+// server-streaming responses whenever no message has been sent for interval,
+// so that proxies and browsers don't close slow streams as idle.
+//
+// The keep-alive is taken from the marshaler if it implements StreamKeepAlive.
+// Otherwise an empty line is written for newline-delimited streams, and no
+// keep-alive is sent for other delimiters since it could corrupt the framing.
+func WithStreamKeepAlive(interval time.Duration) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.streamKeepAlive = interval
+	}
+}
+
+// WithETagGenerator returns a ServeMuxOption that sets the ETag header of responses to GET and HEAD requests
+// to the entity tag generator computes for the response message, such as a resource version.
+//
+// When the tag matches the If-None-Match header of the request, the gateway replies with
+// http.StatusNotModified and no body. An empty tag disables this for the response.
+func WithETagGenerator(generator func(msg proto.Message) string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.etagGenerator = generator
+	}
+}
+
+// WithLastModified returns a ServeMuxOption that sets the Last-Modified header of responses to GET and
+// HEAD requests to the time lastModified returns for the response message, such as an update time.
+//
+// When the resource was not modified after the If-Modified-Since header of the request, the gateway
+// replies with http.StatusNotModified and no body. If-Modified-Since is ignored for requests with an
+// If-None-Match header, as for WithETagGenerator. A zero time disables this for the response.
+func WithLastModified(lastModified func(msg proto.Message) time.Time) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.lastModified = lastModified
+	}
+}
+
+// WithSuccessStatusMapper returns a ServeMuxOption that selects the HTTP status of successful
+// non-streaming responses from the HTTP method of the request and the response message,
+// e.g. http.StatusCreated for a create RPC. A status of http.StatusNoContent suppresses the body.
+//
+// If mapper is nil, DefaultSuccessStatus is used.
+func WithSuccessStatusMapper(mapper func(method string, msg proto.Message) int) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if mapper == nil {
+			mapper = DefaultSuccessStatus
 		}
+		serveMux.successStatusMapper = mapper
+	}
+}
+
+// WithEmptyResponseBody returns a ServeMuxOption that sets whether google.protobuf.Empty responses
+// have a body. If enabled is false, they are replied to with http.StatusNoContent and no body, as
+// DELETE-style endpoints usually are. If it is true, they always have the "{}" body, with
+// http.StatusOK where the mapper set with WithSuccessStatusMapper selects http.StatusNoContent.
+// Without this option, the mapper alone decides, and Empty responses have a body by default.
+func WithEmptyResponseBody(enabled bool) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.emptyResponseBody = &enabled
 	}
+}
 
-	if serveMux.incomingHeaderMatcher == nil {
-		serveMux.incomingHeaderMatcher = DefaultHeaderMatcher
+// WithLocationResolver returns a ServeMuxOption that sets the Location header of successful
+// non-streaming responses to the URL resolver returns for the HTTP method of the request and
+// the response message, such as the URL of a created resource. An empty URL sets no header.
+//
+// It is typically combined with WithSuccessStatusMapper to reply with http.StatusCreated.
+func WithLocationResolver(resolver func(method string, msg proto.Message) string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.locationResolver = resolver
 	}
+}
 
-	if serveMux.outgoingHeaderMatcher == nil {
-		serveMux.outgoingHeaderMatcher = func(key string) (string, bool) {
-			return fmt.Sprintf("%s%s", MetadataHeaderPrefix, key), true
+// WithErrorStatusTable returns a ServeMuxOption that replies to errors with the gRPC codes in table
+// with the HTTP statuses they map to, e.g. to follow an API standard. Codes missing from table keep
+// the status HTTPStatusFromCode maps them to. A later WithErrorStatusTable option is merged over
+// earlier ones. WithIfMatchPreconditionFailed takes precedence over the table.
+func WithErrorStatusTable(table map[codes.Code]int) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.errorStatusTable == nil {
+			serveMux.errorStatusTable = make(map[codes.Code]int)
+		}
+		for code, st := range table {
+			serveMux.errorStatusTable[code] = st
 		}
 	}
+}
 
-	return serveMux
+// WithIfMatchPreconditionFailed returns a ServeMuxOption that replies with http.StatusPreconditionFailed
+// instead of http.StatusBadRequest when a request carrying an If-Match header fails with codes.FailedPrecondition.
+//
+// The If-Match header is forwarded to the gRPC server as "grpcgateway-if-match" metadata by the
+// DefaultHeaderMatcher, so that the server can enforce the precondition. gRPC metadata keys are
+// lowercase, and the prefix is the one set with WithMetadataPrefix, if any.
+func WithIfMatchPreconditionFailed() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.ifMatchPreconditionFailed = true
+	}
 }
 
-// Handle associates "h" to the pair of HTTP method and path pattern.
-func (s *ServeMux) Handle(meth string, pat Pattern, h HandlerFunc) {
-	if s.lastMatchWins {
-		s.handlers[meth] = append([]handler{handler{pat: pat, h: h}}, s.handlers[meth]...)
-	} else {
-		s.handlers[meth] = append(s.handlers[meth], handler{pat: pat, h: h})
+// WithRequestValidator returns a ServeMuxOption that validates request messages once they have been
+// populated from the path, query and body of the request, before they are sent to the gRPC server.
+//
+// An error returned by validator is replied to the client through the error handler. If it is not
+// a gRPC status error, it is converted to one with codes.InvalidArgument.
+func WithRequestValidator(validator func(msg proto.Message) error) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.requestValidator = validator
 	}
 }
 
-// ServeHTTP dispatches the request to the first handler whose pattern matches to r.Method and r.Path.
-func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// WithResponseTransformer returns a ServeMuxOption that replaces each response message, including
+// each message of a response stream, with the one transformer returns for it before it is marshaled,
+// e.g. a clone with some fields redacted. It must not modify msg. If it fails, the error is handled
+// like an error of the gRPC call.
+func WithResponseTransformer(transformer func(ctx context.Context, msg proto.Message) (proto.Message, error)) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.responseTransformer = transformer
+	}
+}
 
-	path := r.URL.Path
-	if !strings.HasPrefix(path, "/") {
-		if s.protoErrorHandler != nil {
-			_, outboundMarshaler := MarshalerForRequest(s, r)
-			sterr := status.Error(codes.InvalidArgument, http.StatusText(http.StatusBadRequest))
-			s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
-		} else {
-			OtherErrorHandler(w, r, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		}
-		return
+// WithRequestTransformer returns a ServeMuxOption that lets transformer modify each request message
+// after it is populated from the path, the query parameters and the body, and before it is validated
+// and sent, e.g. to set a tenant ID from the metadata of ctx.
+func WithRequestTransformer(transformer func(ctx context.Context, msg proto.Message) error) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.requestTransformer = transformer
 	}
+}
 
-	components := strings.Split(path[1:], "/")
-	l := len(components)
-	var verb string
-	if idx := strings.LastIndex(components[l-1], ":"); idx == 0 {
-		if s.protoErrorHandler != nil {
-			_, outboundMarshaler := MarshalerForRequest(s, r)
-			s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
-		} else {
-			OtherErrorHandler(w, r, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-		}
-		return
-	} else if idx > 0 {
-		c := components[l-1]
-		components[l-1], verb = c[:idx], c[idx+1:]
+// TransformRequest calls the request transformer of the ServeMux which dispatched req with ctx, the
+// context of the gRPC call, and msg. It returns nil if the mux has no request transformer. Errors
+// without a gRPC status are returned with codes.InvalidArgument.
+//
+// This is used by generated code after populating a request message.
+func TransformRequest(ctx context.Context, req *http.Request, msg proto.Message) error {
+	mux, ok := req.Context().Value(serveMuxKey{}).(*ServeMux)
+	if !ok || mux.requestTransformer == nil {
+		return nil
+	}
+	err := mux.requestTransformer(ctx, msg)
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
 	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
 
-	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" && s.isPathLengthFallback(r) {
-		r.Method = strings.ToUpper(override)
-		if err := r.ParseForm(); err != nil {
-			if s.protoErrorHandler != nil {
-				_, outboundMarshaler := MarshalerForRequest(s, r)
-				sterr := status.Error(codes.InvalidArgument, err.Error())
-				s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
-			} else {
-				OtherErrorHandler(w, r, err.Error(), http.StatusBadRequest)
-			}
-			return
-		}
+// ValidateRequest validates msg with the validator of the ServeMux which dispatched the request whose
+// context is ctx. It returns nil if the mux has no validator.
+//
+// This is used by generated code after populating a request message.
+func ValidateRequest(ctx context.Context, msg proto.Message) error {
+	mux, ok := ctx.Value(serveMuxKey{}).(*ServeMux)
+	if !ok || mux.requestValidator == nil {
+		return nil
 	}
-	for _, h := range s.handlers[r.Method] {
-		pathParams, err := h.pat.Match(components, verb)
-		if err != nil {
-			continue
-		}
-		h.h(w, r, pathParams)
-		return
+	err := mux.requestValidator(msg)
+	if err == nil {
+		return nil
 	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
 
-	// lookup other methods to handle fallback from GET to POST and
-	// to determine if it is MethodNotAllowed or NotFound.
-	for m, handlers := range s.handlers {
-		if m == r.Method {
-			continue
+// WithGetRetry returns a ServeMuxOption that retries the gRPC call of a unary GET or HEAD request
+// which fails with codes.Unavailable or codes.DeadlineExceeded, making up to "attempts" calls in
+// total and waiting "backoff" before each retry. A retry is not attempted if the deadline of the
+// call would pass during the backoff. Requests with other methods are never retried.
+func WithGetRetry(attempts int, backoff time.Duration) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.getRetryAttempts = attempts
+		serveMux.getRetryBackoff = backoff
+	}
+}
+
+// ShouldRetry reports whether the gRPC call for req, whose attempt-th try failed with err, should be
+// made again according to the WithGetRetry option of the ServeMux which dispatched req.
+// It waits for the backoff before returning true, and returns false if ctx is done meanwhile.
+//
+// This is used by generated code.
+func ShouldRetry(ctx context.Context, req *http.Request, attempt int, err error) bool {
+	if err == nil || (req.Method != "GET" && req.Method != "HEAD") {
+		return false
+	}
+	mux, ok := req.Context().Value(serveMuxKey{}).(*ServeMux)
+	if !ok || attempt >= mux.getRetryAttempts {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+	default:
+		return false
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= mux.getRetryBackoff {
+		return false
+	}
+	if mux.getRetryBackoff <= 0 {
+		return true
+	}
+	t := time.NewTimer(mux.getRetryBackoff)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// WithCallWrapper returns a ServeMuxOption that runs each unary gRPC call through fn, e.g. to guard the
+// backend with a circuit breaker. fn must call invoke to make the call and return its error, or may
+// return an error of its own without calling it. An error returned by fn which is not a gRPC status is
+// replied as codes.Unavailable.
+func WithCallWrapper(fn func(ctx context.Context, invoke func() error) error) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.callWrapper = fn
+	}
+}
+
+// WrapCall makes the unary gRPC call for req by calling call, through the wrapper configured with
+// WithCallWrapper on the ServeMux which dispatched req, if any. Each call is traced as a child span
+// of the server span of req, tagged with the number of the attempt.
+//
+// This is used by generated code.
+func WrapCall(ctx context.Context, req *http.Request, call func() (proto.Message, error)) (proto.Message, error) {
+	if requestStateFromContext(req.Context()) != nil {
+		timed := call
+		call = func() (proto.Message, error) {
+			start := time.Now()
+			defer func() { recordBackendTime(req, time.Since(start)) }()
+			return timed()
 		}
-		for _, h := range handlers {
-			pathParams, err := h.pat.Match(components, verb)
+	}
+	if span := serverSpanFromContext(req.Context()); span != nil && span.span != nil {
+		traced := call
+		call = func() (proto.Message, error) {
+			attempt := span.startAttempt()
+			defer attempt.Finish()
+			msg, err := traced()
 			if err != nil {
-				continue
-			}
-			// X-HTTP-Method-Override is optional. Always allow fallback to POST.
-			if s.isPathLengthFallback(r) {
-				if err := r.ParseForm(); err != nil {
-					if s.protoErrorHandler != nil {
-						_, outboundMarshaler := MarshalerForRequest(s, r)
-						sterr := status.Error(codes.InvalidArgument, err.Error())
-						s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
-					} else {
-						OtherErrorHandler(w, r, err.Error(), http.StatusBadRequest)
-					}
-					return
-				}
-				h.h(w, r, pathParams)
-				return
-			}
-			if s.protoErrorHandler != nil {
-				_, outboundMarshaler := MarshalerForRequest(s, r)
-				s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
-			} else {
-				OtherErrorHandler(w, r, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+				ext.Error.Set(attempt, true)
 			}
-			return
+			return msg, err
+		}
+	}
+	mux, ok := req.Context().Value(serveMuxKey{}).(*ServeMux)
+	if !ok || mux.callWrapper == nil {
+		return call()
+	}
+	var (
+		msg     proto.Message
+		callErr error
+		called  bool
+	)
+	err := mux.callWrapper(ctx, func() error {
+		msg, callErr = call()
+		called = true
+		return callErr
+	})
+	if err == nil {
+		if !called {
+			return nil, status.Error(codes.Internal, "call wrapper returned without making the call")
 		}
+		return msg, nil
+	}
+	if _, ok := status.FromError(err); !ok && !(called && err == callErr) {
+		return nil, status.Error(codes.Unavailable, err.Error())
 	}
+	return nil, err
+}
 
-	if s.protoErrorHandler != nil {
-		_, outboundMarshaler := MarshalerForRequest(s, r)
-		s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
-	} else {
-		OtherErrorHandler(w, r, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+// WithRateLimiter returns a ServeMuxOption that consults fn before the gRPC call for each request, with
+// the path pattern the request matched and the address of the client. The address is the first one in
+// the X-Forwarded-For header if present, so the header must only be trusted behind a proxy which sets it.
+//
+// A non-nil error rejects the request; an error which is not a gRPC status is replied as
+// codes.ResourceExhausted. See RateLimitExceeded for an error which also sets a Retry-After header.
+func WithRateLimiter(fn func(ctx context.Context, pattern, clientIP string) error) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.rateLimiter = fn
 	}
 }
 
-// GetForwardResponseOptions returns the ForwardResponseOptions associated with this ServeMux.
-func (s *ServeMux) GetForwardResponseOptions() []func(context.Context, http.ResponseWriter, proto.Message) error {
-	return s.forwardResponseOptions
+// WithDeadlineDiagnostics returns a ServeMuxOption that adds headers to codes.DeadlineExceeded replies telling
+// where the deadline of the call came from: "Grpc-Gateway-Deadline-Source" is the DeadlineSource of the call,
+// i.e. DeadlineFromHeader, DeadlineFromDefault or DeadlineFromContext, and "Grpc-Gateway-Deadline-Timeout" is
+// the timeout, measured from the start of the request.
+func WithDeadlineDiagnostics() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.deadlineDiagnostics = true
+	}
 }
 
-func (s *ServeMux) isPathLengthFallback(r *http.Request) bool {
-	return !s.disablePathLengthFallback && r.Method == "POST" && r.Header.Get("Content-Type") == "application/x-www-form-urlencoded"
+// WithLogger returns a ServeMuxOption that makes the mux and the handlers generated for it log to
+// logger instead of grpclog, e.g. when a response cannot be written, a handler panics or a request
+// stream cannot be decoded. Logging which does not involve a mux, such as the parsing of patterns,
+// still goes to grpclog.
+func WithLogger(logger Logger) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.logger = logger
+	}
 }
 
-type handler struct {
-	pat Pattern
-	h   HandlerFunc
+// WithMiddleware returns a ServeMuxOption that runs the net/http middlewares mw, the first outermost,
+// around the handler of each request after it has been routed. Unlike middlewares wrapping the mux,
+// which run before routing, they see the route in HTTPPathPattern and RPCMethod, and can get the
+// context the gRPC call is made with, its metadata, deadline and tracing span, with AnnotatedContext.
+// They run after the checks of the mux, such as WithRequireContentType, and before the request is
+// decoded; replies from the response cache set with WithResponseCache skip them.
+func WithMiddleware(mw ...func(http.Handler) http.Handler) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.middlewares = append(serveMux.middlewares, mw...)
+	}
+}
+
+// WithStatusDetailsHeader returns a ServeMuxOption that forwards the details of gRPC errors to clients
+// verbatim: replies to errors with details get a "Grpc-Status-Details-Bin" header, or trailer for
+// server-streaming responses, whose value is the binary google.rpc.Status of the error in unpadded
+// base64, as in gRPC. This serves clients which decode the full status from the binary encoding,
+// whatever the marshaler of the body.
+func WithStatusDetailsHeader() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.forwardStatusDetails = true
+	}
+}
+
+// WithPreserveIncomingMetadata returns a ServeMuxOption that makes AnnotateContext and AnnotateIncomingContext
+// keep the incoming metadata already carried by the given context, e.g. when the gateway is embedded in a gRPC
+// server, and add the metadata from the request to it instead of discarding it.
+func WithPreserveIncomingMetadata() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.preserveIncomingMetadata = true
+	}
+}
+
+// WithDisableHTTPRequestMetadata returns a ServeMuxOption that stops AnnotateContext from adding the
+// "x-http-method" metadata, holding the HTTP method of the request, and the "x-http-path-pattern" metadata,
+// holding the path pattern the request matched, which it adds by default.
+func WithDisableHTTPRequestMetadata() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.disableHTTPRequestMetadata = true
+	}
+}
+
+// WithForwardSourcePort returns a ServeMuxOption that makes AnnotateContext append the address of the
+// client to the "x-forwarded-for" metadata with its port, as "ip:port" or "[ip]:port" for IPv6, rather
+// than the IP alone. Addresses already in the X-Forwarded-For header are forwarded as they are.
+func WithForwardSourcePort() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.forwardSourcePort = true
+	}
+}
+
+// ForwardedHeaderPrecedence determines which of the X-Forwarded-* headers and the Forwarded header
+// of RFC 7239 AnnotateContext uses for the "x-forwarded-for" and "x-forwarded-host" metadata when a
+// request has both.
+type ForwardedHeaderPrecedence int
+
+const (
+	// XForwardedFirst uses the Forwarded header only where the X-Forwarded-* headers are missing.
+	XForwardedFirst ForwardedHeaderPrecedence = iota
+	// ForwardedFirst uses the X-Forwarded-* headers only where the Forwarded header is missing.
+	ForwardedFirst
+)
+
+// WithForwardedHeaderPrecedence returns a ServeMuxOption that sets which forwarding headers win when a
+// request has both kinds. The default is XForwardedFirst. Either way, the "proto" parameter of a
+// Forwarded header is passed as the "x-forwarded-proto" metadata.
+func WithForwardedHeaderPrecedence(p ForwardedHeaderPrecedence) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.forwardedHeaderPrecedence = p
+	}
+}
+
+// TimeoutHeaderPrecedence determines which of the Grpc-Timeout header and the header set with
+// WithTimeoutHeader AnnotateContext takes the timeout of the gRPC call from when a request has both.
+type TimeoutHeaderPrecedence int
+
+const (
+	// GrpcTimeoutFirst uses the header set with WithTimeoutHeader only where Grpc-Timeout is missing.
+	GrpcTimeoutFirst TimeoutHeaderPrecedence = iota
+	// TimeoutHeaderFirst uses Grpc-Timeout only where the header set with WithTimeoutHeader is missing.
+	TimeoutHeaderFirst
+)
+
+// WithTimeoutHeader returns a ServeMuxOption that lets clients also request the timeout of the gRPC
+// call with the header name, e.g. "X-Request-Timeout", whose value is a duration parsed with
+// time.ParseDuration such as "5s" or "1m30s", as set by many HTTP proxies. precedence says which header
+// wins when a request has both. An invalid value rejects the request with codes.InvalidArgument.
+func WithTimeoutHeader(name string, precedence TimeoutHeaderPrecedence) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.timeoutHeader = name
+		serveMux.timeoutHeaderPrecedence = precedence
+	}
+}
+
+// WithDefaultTimeout returns a ServeMuxOption that sets the timeout of the gRPC calls of requests which ask
+// for none, instead of DefaultContextTimeout. A timeout of 0 gives those calls no deadline.
+func WithDefaultTimeout(timeout time.Duration) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.defaultTimeout = &timeout
+	}
+}
+
+// WithBinaryHeaderDecoder returns a ServeMuxOption that decodes the values of "-bin" headers with fn,
+// e.g. to accept a non-standard base64 alphabet. A decoding error rejects the request with
+// codes.InvalidArgument.
+//
+// If fn is nil, the default decoder is used, which accepts padded and unpadded standard base64.
+func WithBinaryHeaderDecoder(fn func(string) ([]byte, error)) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if fn == nil {
+			fn = decodeBinHeader
+		}
+		serveMux.binaryHeaderDecoder = fn
+	}
+}
+
+// WithMaxMetadataPairs returns a ServeMuxOption that rejects requests with codes.InvalidArgument if
+// the gateway would send more than n metadata pairs with their gRPC call, counting those from headers
+// and those added by WithMetadata and WithChainedMetadata annotators. n <= 0 means no limit.
+func WithMaxMetadataPairs(n int) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.maxMetadataPairs = n
+	}
+}
+
+// WithMaxMetadataBytes returns a ServeMuxOption that rejects requests with codes.InvalidArgument if
+// the keys and values of the metadata the gateway would send with their gRPC call add up to more than
+// n bytes. The values of "-bin" headers count with their decoded size. n <= 0 means no limit.
+//
+// Backends limit the size of the metadata they accept too, but reject oversized metadata with less
+// helpful errors.
+func WithMaxMetadataBytes(n int) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.maxMetadataBytes = n
+	}
+}
+
+// WithMaxRequestBodyBytes returns a ServeMuxOption that limits request bodies to n bytes. It is
+// enforced with http.MaxBytesReader while the body is read, so it also applies to chunked bodies
+// which declare no Content-Length; reading past the limit fails the request with
+// codes.InvalidArgument. n <= 0 means no limit.
+func WithMaxRequestBodyBytes(n int64) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.maxRequestBodyBytes = n
+	}
+}
+
+// WithRawBodyMetadata returns a ServeMuxOption that forwards the request body, exactly as the client
+// sent it, as the binary metadata key, e.g. to let backends verify a signature of it. "-bin" is
+// appended to key unless it already ends with it, so gRPC sends the value base64 encoded. The body is
+// read into memory before the handler runs, up to the limit set with WithMaxRequestBodyBytes.
+func WithRawBodyMetadata(key string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		key = strings.ToLower(key)
+		if key != "" && !strings.HasSuffix(key, "-bin") {
+			key += "-bin"
+		}
+		serveMux.rawBodyMetadataKey = key
+	}
+}
+
+// WithAutoFieldMask returns a ServeMuxOption that fills the google.protobuf.FieldMask field named
+// maskFieldName of PATCH requests, when the client leaves it empty, with the paths of the fields present
+// in the JSON request body, as recommended by https://google.aip.dev/134. Nested fields produce dotted paths.
+//
+// Unlike the allow_patch_feature of the generator, this also applies to bindings with body "*", in which
+// case the paths are relative to the request message, and to requests with several FieldMask fields.
+func WithAutoFieldMask(maskFieldName string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.autoFieldMask = maskFieldName
+	}
+}
+
+// WithCallOptions returns a ServeMuxOption that computes additional grpc.CallOptions, such as
+// grpc.MaxCallRecvMsgSize or per-tenant credentials, for the gRPC call made for each request.
+//
+// The options are carried by the context returned from AnnotateContext and picked up by generated code
+// via CallOptions.
+func WithCallOptions(fn func(ctx context.Context, req *http.Request) []grpc.CallOption) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.callOptions = fn
+	}
+}
+
+// WithAuthority returns a ServeMuxOption that selects the :authority of the gRPC call made for each
+// request, e.g. to route virtual hosts through a single backend. The authority is passed to the call
+// as an AuthorityCallOption. An empty authority adds no option.
+//
+// If fn is nil, the Host of the incoming request is used.
+func WithAuthority(fn func(req *http.Request) string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if fn == nil {
+			fn = func(req *http.Request) string { return req.Host }
+		}
+		serveMux.authority = fn
+	}
+}
+
+// WithAllowEmptyBody returns a ServeMuxOption that treats an empty request body as the zero value
+// of the message bound to the body, even if the inbound marshaler fails to decode empty input.
+func WithAllowEmptyBody() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.allowEmptyBody = true
+	}
+}
+
+// WithQueryDefaults returns a ServeMuxOption that sets the fields of the requests to the gRPC
+// method (e.g. "/pkg.Service/Method") to the values of defaults, keyed by query parameter name,
+// when they are set by neither the path, the body nor the query parameters, e.g. for a default
+// "page_size" of "50". Fields with their zero value count as unset. The generated handlers apply them with ApplyQueryDefaults.
+func WithQueryDefaults(method string, defaults map[string]string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.queryDefaults == nil {
+			serveMux.queryDefaults = make(map[string]map[string]string)
+		}
+		serveMux.queryDefaults[method] = defaults
+	}
+}
+
+// WithQueryOverridesBody returns a ServeMuxOption that lets query parameters set the fields inside
+// the body field of a route, e.g. "book.title" for a route with body "book", overriding the values
+// decoded from the body. By default the body takes precedence and such query parameters are ignored.
+// Path parameters take precedence over both.
+func WithQueryOverridesBody() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.queryOverridesBody = true
+	}
+}
+
+// WithBracketQueryNotation returns a ServeMuxOption that lets query parameters name nested fields with
+// brackets, as Rails and PHP clients do, besides dots: "filter[status]=active" is "filter.status=active",
+// and a trailing "[]" as in "filter[tags][]=a" is ignored, values of repeated fields being appended
+// anyway. A bracket after a map field still names a map key, e.g. "labels[env]=prod".
+// Malformed brackets fail the request with codes.InvalidArgument.
+//
+// It applies to handlers generated to call PopulateQueryParametersContext.
+func WithBracketQueryNotation() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.bracketQueryNotation = true
+	}
+}
+
+// customTypeDecoders maps the full names of message types to the decoders registered for them
+// with WithCustomTypeDecoder.
+type customTypeDecoders map[string]func(string) (proto.Message, error)
+
+// WithCustomTypeDecoder returns a ServeMuxOption that decodes the string values given for fields of
+// the message type fullName, e.g. "google.protobuf.Timestamp", with decoder instead of the default
+// format of the type, in query parameters and in the JSON request bodies read by the JSONPb inbound
+// marshalers of the mux. decoder must return a message of that type; its errors fail the request
+// with codes.InvalidArgument. Values which are not strings, such as JSON objects, are decoded as usual.
+//
+// For query parameters it applies to handlers generated to call PopulateQueryParametersContext.
+func WithCustomTypeDecoder(fullName string, decoder func(string) (proto.Message, error)) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.customTypeDecoders == nil {
+			serveMux.customTypeDecoders = make(customTypeDecoders)
+		}
+		serveMux.customTypeDecoders[fullName] = decoder
+	}
+}
+
+// WithRequireContentType returns a ServeMuxOption that rejects requests with a body with
+// http.StatusUnsupportedMediaType unless their Content-Type exactly matches the MIME type of a
+// marshaler registered with WithMarshalerOption, instead of decoding them with the wildcard marshaler.
+// Requests without a body, such as most GET requests, are exempt.
+func WithRequireContentType() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.requireContentType = true
+	}
+}
+
+// WithBasicAuthMetadata returns a ServeMuxOption that decodes an "Authorization: Basic" header into
+// "x-basic-user" and "x-basic-pass" metadata, in addition to forwarding the header as "authorization".
+// Malformed credentials are rejected with codes.Unauthenticated.
+func WithBasicAuthMetadata() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.decodeBasicAuth = true
+	}
+}
+
+// CookieMatcherFunc checks whether a cookie should be forwarded to the gRPC context, and returns
+// the metadata key to forward it as.
+type CookieMatcherFunc func(name string) (metadataKey string, ok bool)
+
+// WithCookieMatcher returns a ServeMuxOption that forwards individual cookies of the request as
+// metadata, using the keys returned by fn.
+//
+// Cookie values are URL-decoded when possible. A cookie which occurs several times in the request
+// is forwarded with all of its values, in the order they were sent.
+func WithCookieMatcher(fn CookieMatcherFunc) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.cookieMatcher = fn
+	}
+}
+
+// WithoutAuthorizationForwarding returns a ServeMuxOption that keeps the credentials of requests to
+// the routes with the given path templates (e.g. "/v1/public/status") from their metadata:
+// the Authorization header is not forwarded under any name, and WithBasicAuthMetadata and
+// WithCookieToAuthorization do not apply. This is meant for public endpoints whose backends have
+// no use for the bearer tokens clients may send anyway, and should not log them.
+func WithoutAuthorizationForwarding(patterns ...string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if serveMux.authorizationStripped == nil {
+			serveMux.authorizationStripped = make(map[string]bool)
+		}
+		for _, p := range patterns {
+			serveMux.authorizationStripped[p] = true
+		}
+	}
+}
+
+// WithCookieToAuthorization returns a ServeMuxOption that forwards the value of the named cookie
+// as "authorization: Bearer <value>" metadata when the request has no Authorization header.
+//
+// The cookie itself is forwarded only if the Cookie header is matched by the incoming header matcher.
+func WithCookieToAuthorization(cookieName string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.authorizationCookie = cookieName
+	}
+}
+
+// WithRequestID returns a ServeMuxOption that assigns an ID to every request for
+// log correlation across the gateway and the gRPC server.
+//
+// The ID is taken from the X-Request-ID header of the request, or created by
+// generator when the header is absent. A nil generator creates random UUIDs.
+// The ID is forwarded as "x-request-id" metadata, echoed in the X-Request-ID
+// response header and available to handlers through RequestID.
+func WithRequestID(generator func() string) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		if generator == nil {
+			generator = fastuuid.MustNewGenerator().Hex128
+		}
+		serveMux.requestIDGenerator = generator
+	}
+}
+
+// WithRecovery returns a ServeMuxOption which customizes the response to a request whose handling panicked.
+//
+// ServeHTTP always recovers such panics and logs them. handler converts the recovered value into the error
+// replied to the client, which defaults to codes.Internal.
+func WithRecovery(handler RecoveryHandlerFunc) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.recoveryHandler = handler
+	}
+}
+
+// NewServeMux returns a new ServeMux whose internal mapping is empty.
+func NewServeMux(opts ...ServeMuxOption) *ServeMux {
+	serveMux := &ServeMux{
+		handlers:               make(map[string][]handler),
+		forwardResponseOptions: make([]func(context.Context, http.ResponseWriter, proto.Message) error, 0),
+		marshalers:             makeMarshalerMIMERegistry(),
+		streamErrorHandler:     DefaultHTTPStreamErrorHandler,
+		recoveryHandler:        DefaultRecoveryHandler,
+		binaryHeaderDecoder:    decodeBinHeader,
+		metadataPrefix:         MetadataPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(serveMux)
+	}
+	applyUseProtoNames(serveMux)
+
+	if serveMux.protoErrorHandler != nil {
+		HTTPError = serveMux.protoErrorHandler
+		// OtherErrorHandler is no longer used when protoErrorHandler is set.
+		// Overwritten by a special error handler to return Unknown.
+		OtherErrorHandler = func(w http.ResponseWriter, r *http.Request, _ string, _ int) {
+			ctx := context.Background()
+			_, outboundMarshaler := MarshalerForRequest(serveMux, r)
+			sterr := status.Error(codes.Unknown, "unexpected use of OtherErrorHandler")
+			serveMux.protoErrorHandler(ctx, serveMux, outboundMarshaler, w, r, sterr)
+		}
+	}
+
+	if serveMux.incomingHeaderMatcher == nil {
+		serveMux.incomingHeaderMatcher = DefaultHeaderMatcher
+		if prefix := serveMux.metadataPrefix; prefix != MetadataPrefix {
+			serveMux.incomingHeaderMatcher = func(key string) (string, bool) {
+				return matchHeader(prefix, key)
+			}
+		}
+	}
+
+	if serveMux.outgoingHeaderMatcher == nil {
+		serveMux.outgoingHeaderMatcher = func(key string) (string, bool) {
+			return fmt.Sprintf("%s%s", MetadataHeaderPrefix, key), true
+		}
+	}
+
+	if serveMux.outgoingTrailerMatcher == nil {
+		serveMux.outgoingTrailerMatcher = func(key string) (string, bool) {
+			switch key {
+			case "grpc-status", "grpc-message":
+				return fmt.Sprintf("%s%s", MetadataTrailerPrefix, key), true
+			}
+			return "", false
+		}
+	}
+
+	return serveMux
+}
+
+// WithLivenessEndpoint returns a ServeMuxOption which makes the mux reply to GET and HEAD
+// requests for path, e.g. "/healthz", with http.StatusOK as long as the gateway process serves
+// HTTP at all. It never contacts the backend, and keeps replying while the mux shuts down, so it
+// is suited to liveness probes rather than readiness probes.
+func WithLivenessEndpoint(path string) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.livenessPath = path
+	}
+}
+
+// WithResponseCache returns a ServeMuxOption which lets the mux serve responses from a cache.
+// Before dispatching a request to its handler, the mux calls get, and if it reports a hit,
+// replies with the returned body and the content type of the outbound marshaler without calling
+// the RPC. After marshaling a unary response with http.StatusOK, ForwardResponseMessage calls set
+// with the body it is about to write. Computing the cache key from the request, including the
+// headers the response varies by such as Accept, expiring entries and deciding which requests
+// are cacheable are left to get and set.
+func WithResponseCache(get func(ctx context.Context, req *http.Request) ([]byte, bool), set func(ctx context.Context, req *http.Request, body []byte)) ServeMuxOption {
+	return func(mux *ServeMux) {
+		mux.responseCacheGet = get
+		mux.responseCacheSet = set
+	}
+}
+
+// Handle associates "h" to the pair of HTTP method and path pattern.
+func (s *ServeMux) Handle(meth string, pat Pattern, h HandlerFunc) {
+	s.HandleRoute(RouteInfo{Method: meth}, pat, h)
+}
+
+// HandleHost is like Handle, but scopes the route to host, as described in RouteInfo, so that routes
+// with the same path on several hosts can dispatch to different handlers. Routes of the same method
+// are still matched in the order they were registered, so routes scoped to a host must be registered
+// before a route of any host with the same path.
+func (s *ServeMux) HandleHost(host, meth string, pat Pattern, h HandlerFunc) {
+	s.HandleRoute(RouteInfo{Method: meth, Host: host}, pat, h)
+}
+
+// HandlePatterns associates h to the HTTP method meth and each of pats, like the
+// additional_bindings of a google.api.http rule, e.g. to serve a canonical path and a legacy
+// alias of it. Each pattern is registered as a route of its own, in the order given, so it is
+// matched against other routes the same way as if it was registered with Handle, and
+// HTTPPathPattern reports the one a request matched.
+func (s *ServeMux) HandlePatterns(meth string, pats []Pattern, h HandlerFunc) {
+	for _, pat := range pats {
+		s.Handle(meth, pat, h)
+	}
+}
+
+// RouteInfo describes a route registered on a ServeMux.
+type RouteInfo struct {
+	// Method is the HTTP method of the route.
+	Method string
+	// Pattern is the path template of the route, e.g. "/v1/books/{name=*}".
+	Pattern string
+	// Body is the field path of the request message the request body is mapped to,
+	// "*" if it is mapped to the whole message, or "" if the route takes no body.
+	Body string
+	// RPCMethod is the full name of the gRPC method of the route, e.g. "/pkg.Service/Method",
+	// or "" if it is unknown.
+	RPCMethod string
+	// Host is the host the route is scoped to, e.g. "api.example.com", or "*.example.com" for
+	// any subdomain of example.com, matched case-insensitively against the Host header of requests,
+	// without its port, before their path. The route serves any host if it is empty.
+	Host string
+	// ContentTypes are the media types, e.g. "application/vnd.example.v2+json", of the requests
+	// the route serves, compared case-insensitively with the Content-Type header of requests without
+	// its parameters. The route serves any request if it is empty. A request matching the method and
+	// the path of routes with ContentTypes, and no other route, is replied to with
+	// http.StatusUnsupportedMediaType.
+	ContentTypes []string
+}
+
+// HandleRoute is like Handle, but also records route for Routes.
+// The pattern of route is set from pat if it is empty.
+func (s *ServeMux) HandleRoute(route RouteInfo, pat Pattern, h HandlerFunc) {
+	if route.Pattern == "" {
+		route.Pattern = pat.String()
+	}
+	meth := route.Method
+	hd := handler{pat: pat, h: h, rpcMethod: route.RPCMethod, host: strings.ToLower(route.Host)}
+	for _, ct := range route.ContentTypes {
+		hd.contentTypes = append(hd.contentTypes, strings.ToLower(ct))
+	}
+	if s.lastMatchWins {
+		s.handlers[meth] = append([]handler{hd}, s.handlers[meth]...)
+	} else {
+		s.handlers[meth] = append(s.handlers[meth], hd)
+	}
+	s.routes = append(s.routes, route)
+}
+
+// DefaultTimeout returns the timeout the mux applies to the gRPC calls of requests which ask for none:
+// the one set with WithDefaultTimeout, or else DefaultContextTimeout. It is 0 if they get none. Routes
+// exempted with WithStreamingDeadlineExempt get no timeout regardless.
+func (s *ServeMux) DefaultTimeout() time.Duration {
+	if s.defaultTimeout != nil {
+		return *s.defaultTimeout
+	}
+	return DefaultContextTimeout
+}
+
+// Routes returns the routes registered on the mux, in the order they were registered.
+func (s *ServeMux) Routes() []RouteInfo {
+	return append([]RouteInfo(nil), s.routes...)
+}
+
+// ServeHTTP dispatches the request to the first handler whose pattern matches to r.Method and r.Path.
+func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.livenessPath != "" && r.URL.Path == s.livenessPath {
+		serveLiveness(w, r)
+		return
+	}
+	s.serve(w, r, s.route)
+}
+
+// serveLiveness replies to the liveness probe r with http.StatusOK.
+func serveLiveness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "GET" {
+		fmt.Fprint(w, "ok\n")
+	}
+}
+
+// Handler returns an http.HandlerFunc which serves the route registered on the mux for the HTTP
+// method meth and the path template pattern, as reported by Routes, e.g. "/v1/books/{name=*}".
+// It can be mounted on another router at the same path; path parameters are still extracted
+// by the mux, and the request is otherwise handled as by ServeHTTP. Requests with another method
+// are replied to with http.StatusMethodNotAllowed.
+//
+// The route must have been registered before Handler is called.
+func (s *ServeMux) Handler(meth, pattern string) (http.HandlerFunc, error) {
+	for _, h := range s.handlers[meth] {
+		if h.pat.String() != pattern {
+			continue
+		}
+		h := h
+		return func(w http.ResponseWriter, r *http.Request) {
+			s.serve(w, r, func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+				if r.Method != meth {
+					w.Header().Set("Allow", meth)
+					http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+					return
+				}
+				components, verb, ok := s.splitPath(ctx, w, r)
+				if !ok {
+					return
+				}
+				pathParams, err := s.match(h, requestHost(r), components, verb)
+				if err != nil {
+					if s.protoErrorHandler != nil {
+						_, outboundMarshaler := MarshalerForRequest(s, r)
+						s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
+					} else {
+						OtherErrorHandler(w, r, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+					}
+					return
+				}
+				s.dispatch(ctx, w, r, h, pathParams)
+			})
+		}, nil
+	}
+	return nil, fmt.Errorf("no route registered for %s %s", meth, pattern)
+}
+
+// serve sets up the request context and response writer, and recovers from panics, around route.
+func (s *ServeMux) serve(w http.ResponseWriter, r *http.Request, route func(context.Context, http.ResponseWriter, *http.Request)) {
+	r, done, ok := s.requests.begin(r)
+	if !ok {
+		s.replyShuttingDown(w, r)
+		return
+	}
+	defer done()
+	span := &serverSpan{}
+	defer span.finish()
+	ctx := context.WithValue(r.Context(), serverSpanKey{}, span)
+	deadline := &requestDeadline{}
+	defer deadline.cancel()
+	ctx = context.WithValue(ctx, requestDeadlineKey{}, deadline)
+	ctx = context.WithValue(ctx, requestStartKey{}, time.Now())
+	r = r.WithContext(ctx)
+
+	if s.requestIDGenerator != nil {
+		id := r.Header.Get(xRequestID)
+		if id == "" {
+			id = s.requestIDGenerator()
+		}
+		w.Header().Set(xRequestID, id)
+		ctx = context.WithValue(ctx, requestIDKey{}, id)
+		r = r.WithContext(ctx)
+	}
+
+	if s.accessLogger != nil || s.requestObserver != nil || s.serverTiming {
+		var done func()
+		w, r, done = s.instrument(w, r)
+		ctx = r.Context()
+		defer done()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			s.handlePanic(w, r, p)
+		}
+	}()
+
+	route(ctx, w, r)
+}
+
+// route dispatches r to the first handler whose pattern matches to r.Method and r.Path.
+func (s *ServeMux) route(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if s.serveGRPCWeb(ctx, w, r) {
+		return
+	}
+	components, verb, ok := s.splitPath(ctx, w, r)
+	if !ok {
+		return
+	}
+	host := requestHost(r)
+	l := len(components)
+
+	if s.trailingSlashPolicy != TrailingSlashStrict && l > 1 && components[l-1] == "" && verb == "" &&
+		!s.matchesAny(host, components, verb) && s.matchesAny(host, components[:l-1], verb) {
+		if s.trailingSlashPolicy == TrailingSlashRedirect {
+			s.redirectTrailingSlash(w, r)
+			return
+		}
+		components = components[:l-1]
+	}
+
+	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" && s.isPathLengthFallback(r) {
+		r.Method = strings.ToUpper(override)
+		if err := r.ParseForm(); err != nil {
+			if s.protoErrorHandler != nil {
+				_, outboundMarshaler := MarshalerForRequest(s, r)
+				sterr := status.Error(codes.InvalidArgument, err.Error())
+				s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
+			} else {
+				OtherErrorHandler(w, r, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+	}
+	var unsupportedMediaType bool
+	for _, h := range s.handlers[r.Method] {
+		pathParams, err := s.match(h, host, components, verb)
+		if err != nil {
+			continue
+		}
+		if !h.servesContentType(r) {
+			unsupportedMediaType = true
+			continue
+		}
+		s.dispatch(ctx, w, r, h, pathParams)
+		return
+	}
+	if unsupportedMediaType {
+		_, outboundMarshaler := MarshalerForRequest(s, r)
+		sterr := status.Errorf(codes.InvalidArgument, "unsupported Content-Type %q", r.Header.Get(contentTypeHeader))
+		s.replyWithStatus(ctx, outboundMarshaler, w, r, http.StatusUnsupportedMediaType, sterr)
+		return
+	}
+
+	// HEAD requests run the GET binding without sending its response body.
+	if r.Method == "HEAD" {
+		for _, h := range s.handlers["GET"] {
+			pathParams, err := s.match(h, host, components, verb)
+			if err != nil {
+				continue
+			}
+			hw := &headResponseWriter{ResponseWriter: w}
+			s.dispatch(ctx, hw, r, h, pathParams)
+			hw.finish()
+			return
+		}
+	}
+
+	if r.Method == "OPTIONS" && s.defaultOptionsHandler {
+		if allowed := s.allowedMethods(host, components, verb); len(allowed) != 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	// lookup other methods to handle fallback from GET to POST and
+	// to determine if it is MethodNotAllowed or NotFound.
+	for m, handlers := range s.handlers {
+		if m == r.Method {
+			continue
+		}
+		for _, h := range handlers {
+			pathParams, err := s.match(h, host, components, verb)
+			if err != nil {
+				continue
+			}
+			// X-HTTP-Method-Override is optional. Always allow fallback to POST.
+			if s.isPathLengthFallback(r) {
+				if err := r.ParseForm(); err != nil {
+					if s.protoErrorHandler != nil {
+						_, outboundMarshaler := MarshalerForRequest(s, r)
+						sterr := status.Error(codes.InvalidArgument, err.Error())
+						s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
+					} else {
+						OtherErrorHandler(w, r, err.Error(), http.StatusBadRequest)
+					}
+					return
+				}
+				s.dispatch(ctx, w, r, h, pathParams)
+				return
+			}
+			if s.protoErrorHandler != nil {
+				_, outboundMarshaler := MarshalerForRequest(s, r)
+				s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
+			} else {
+				OtherErrorHandler(w, r, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			}
+			return
+		}
+	}
+
+	if s.protoErrorHandler != nil {
+		_, outboundMarshaler := MarshalerForRequest(s, r)
+		s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
+	} else {
+		OtherErrorHandler(w, r, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}
+}
+
+// splitPath splits the path of r into its components and verb. If the path is malformed,
+// it replies to r with an error and returns false.
+func (s *ServeMux) splitPath(ctx context.Context, w http.ResponseWriter, r *http.Request) ([]string, string, bool) {
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/") {
+		if s.protoErrorHandler != nil {
+			_, outboundMarshaler := MarshalerForRequest(s, r)
+			sterr := status.Error(codes.InvalidArgument, http.StatusText(http.StatusBadRequest))
+			s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, sterr)
+		} else {
+			OtherErrorHandler(w, r, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		}
+		return nil, "", false
+	}
+
+	components := strings.Split(path[1:], "/")
+	l := len(components)
+	var verb string
+	if idx := strings.LastIndex(components[l-1], ":"); idx == 0 {
+		if s.protoErrorHandler != nil {
+			_, outboundMarshaler := MarshalerForRequest(s, r)
+			s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, ErrUnknownURI)
+		} else {
+			OtherErrorHandler(w, r, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		}
+		return nil, "", false
+	} else if idx > 0 {
+		c := components[l-1]
+		components[l-1], verb = c[:idx], c[idx+1:]
+	}
+	return components, verb, true
+}
+
+type serveMuxKey struct{}
+
+// dispatch invokes the handler h of the route matched for r.
+func (s *ServeMux) dispatch(ctx context.Context, w http.ResponseWriter, r *http.Request, h handler, pathParams map[string]string) {
+	if st := requestStateFromContext(ctx); st != nil {
+		st.pattern = h.pat.String()
+	}
+	ctx = context.WithValue(withHTTPPattern(ctx, h.pat), serveMuxKey{}, s)
+	if h.rpcMethod != "" {
+		ctx = context.WithValue(ctx, rpcMethodKey{}, h.rpcMethod)
+	}
+	inboundMarshaler, outboundMarshaler := MarshalerForRequest(s, r)
+	ctx = context.WithValue(ctx, inboundContentTypeKey{}, inboundMarshaler.ContentType())
+	ctx = context.WithValue(ctx, outboundContentTypeKey{}, outboundMarshaler.ContentType())
+	r = r.WithContext(ctx)
+	if s.maxRequestBodyBytes > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBodyBytes)
+	}
+	if s.rawBodyMetadataKey != "" && r.Body != nil {
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			s.replyWithStatus(ctx, outboundMarshaler, w, r, http.StatusBadRequest, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(raw))
+		ctx = context.WithValue(ctx, rawRequestBodyKey{}, raw)
+		r = r.WithContext(ctx)
+	}
+	for _, annotator := range s.responseHeaderAnnotators {
+		for k, vs := range annotator(ctx, r) {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+	}
+	if s.requireContentType && r.ContentLength != 0 && !s.hasMarshalerForContentType(r) {
+		sterr := status.Errorf(codes.InvalidArgument, "unsupported Content-Type %q", r.Header.Get(contentTypeHeader))
+		s.replyWithStatus(ctx, outboundMarshaler, w, r, http.StatusUnsupportedMediaType, sterr)
+		return
+	}
+	if s.acceptCharset {
+		_, registered := registeredMarshalersForRequest(s, r)
+		if charsets := outboundCharsets(registered); len(charsets) != 0 {
+			if _, ok := negotiateCharset(r.Header.Get(acceptCharsetHeader), charsets); !ok {
+				sterr := status.Errorf(codes.InvalidArgument, "unsupported Accept-Charset %q, want one of %s", r.Header.Get(acceptCharsetHeader), strings.Join(charsets, ", "))
+				s.replyWithStatus(ctx, outboundMarshaler, w, r, http.StatusNotAcceptable, sterr)
+				return
+			}
+		}
+	}
+	if s.responseCacheGet != nil {
+		if body, ok := s.responseCacheGet(ctx, r); ok {
+			w.Header().Set("Content-Type", outboundMarshaler.ContentType())
+			if _, err := w.Write(body); err != nil {
+				loggerOf(s).Infof("Failed to write response: %v", err)
+			}
+			return
+		}
+	}
+	if len(s.middlewares) == 0 {
+		h.h(w, r, pathParams)
+		return
+	}
+	r = r.WithContext(context.WithValue(r.Context(), annotationKey{}, &annotation{}))
+	var next http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.h(w, r, pathParams)
+	})
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		next = s.middlewares[i](next)
+	}
+	next.ServeHTTP(w, r)
+}
+
+// replyWithStatus replies to r with the error handler of the mux for err, but with the HTTP status code.
+func (s *ServeMux) replyWithStatus(ctx context.Context, marshaler Marshaler, w http.ResponseWriter, r *http.Request, code int, err error) {
+	w = &statusOverrideWriter{ResponseWriter: w, status: code}
+	if s.protoErrorHandler != nil {
+		s.protoErrorHandler(ctx, s, marshaler, w, r, err)
+	} else {
+		HTTPError(ctx, s, marshaler, w, r, err)
+	}
+}
+
+// hasMarshalerForContentType reports whether a marshaler is registered for one of the Content-Type
+// headers of r, not counting the wildcard marshaler.
+func (s *ServeMux) hasMarshalerForContentType(r *http.Request) bool {
+	for _, contentTypeVal := range r.Header[contentTypeHeader] {
+		if _, ok := s.marshalers.mimeMap[contentTypeVal]; ok && contentTypeVal != MIMEWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// statusOverrideWriter replaces the status code written by an error handler.
+type statusOverrideWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusOverrideWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// handlePanic logs p, recovered while serving r, and replies with the error from the recovery handler.
+func (s *ServeMux) handlePanic(w http.ResponseWriter, r *http.Request, p interface{}) {
+	loggerOf(s).Errorf("Recovered from panic serving %s %s: %v\n%s", r.Method, r.URL.Path, p, debug.Stack())
+	ctx := r.Context()
+	err := s.recoveryHandler(ctx, p)
+	_, outboundMarshaler := MarshalerForRequest(s, r)
+	if s.protoErrorHandler != nil {
+		s.protoErrorHandler(ctx, s, outboundMarshaler, w, r, err)
+	} else {
+		HTTPError(ctx, s, outboundMarshaler, w, r, err)
+	}
+}
+
+// SetClientConn sets the connection over which handlers registered with the generated
+// Register...FromMuxConn functions forward requests. It is safe to call while the mux is serving;
+// requests already received keep using the connection they started with.
+func (s *ServeMux) SetClientConn(cc *grpc.ClientConn) {
+	s.clientConn.Store(cc)
+}
+
+// ClientConn returns the connection most recently set with SetClientConn.
+// It fails with codes.Unavailable if no connection has been set.
+func (s *ServeMux) ClientConn() (*grpc.ClientConn, error) {
+	cc, _ := s.clientConn.Load().(*grpc.ClientConn)
+	if cc == nil {
+		return nil, status.Error(codes.Unavailable, "no client connection set on the ServeMux")
+	}
+	return cc, nil
+}
+
+// GetForwardResponseOptions returns the ForwardResponseOptions associated with this ServeMux.
+func (s *ServeMux) GetForwardResponseOptions() []func(context.Context, http.ResponseWriter, proto.Message) error {
+	return s.forwardResponseOptions
+}
+
+// match returns the path parameters of a request to host with the path components and verb if h
+// serves the host and matches the path, or ErrNotMatch otherwise.
+func (s *ServeMux) match(h handler, host string, components []string, verb string) (map[string]string, error) {
+	if !hostMatches(h.host, host) {
+		return nil, ErrNotMatch
+	}
+	return h.pat.match(components, verb, s.caseInsensitivePaths)
+}
+
+// requestHost returns the host of r without its port, in lowercase.
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// hostMatches reports whether a route scoped to the lowercase host pattern serves host, as
+// described in RouteInfo.
+func hostMatches(pattern, host string) bool {
+	switch {
+	case pattern == "":
+		return true
+	case strings.HasPrefix(pattern, "*."):
+		return len(host) > len(pattern)-1 && strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
+
+// matchesAny reports whether a route of any method for host matches the path components and verb.
+func (s *ServeMux) matchesAny(host string, components []string, verb string) bool {
+	for _, handlers := range s.handlers {
+		for _, h := range handlers {
+			if _, err := s.match(h, host, components, verb); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowedMethods returns the sorted methods of the routes for host matching the path, with HEAD if
+// GET is one of them and OPTIONS, or nil if no route matches it.
+func (s *ServeMux) allowedMethods(host string, components []string, verb string) []string {
+	allowed := map[string]bool{}
+	for m, handlers := range s.handlers {
+		for _, h := range handlers {
+			if _, err := s.match(h, host, components, verb); err == nil {
+				allowed[m] = true
+				break
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	if allowed["GET"] {
+		allowed["HEAD"] = true
+	}
+	allowed["OPTIONS"] = true
+	methods := make([]string, 0, len(allowed))
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// redirectTrailingSlash redirects r to its path without the trailing slash.
+func (s *ServeMux) redirectTrailingSlash(w http.ResponseWriter, r *http.Request) {
+	u := *r.URL
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+	code := http.StatusPermanentRedirect
+	if r.Method == "GET" || r.Method == "HEAD" {
+		code = http.StatusMovedPermanently
+	}
+	http.Redirect(w, r, u.RequestURI(), code)
+}
+
+func (s *ServeMux) isPathLengthFallback(r *http.Request) bool {
+	return !s.disablePathLengthFallback && r.Method == "POST" && r.Header.Get("Content-Type") == "application/x-www-form-urlencoded"
+}
+
+type handler struct {
+	pat          Pattern
+	h            HandlerFunc
+	rpcMethod    string
+	host         string
+	contentTypes []string
+}
+
+// servesContentType reports whether h serves the media type of the Content-Type header of r, as
+// described in RouteInfo.
+func (h handler) servesContentType(r *http.Request) bool {
+	if len(h.contentTypes) == 0 {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	if err != nil {
+		return false
+	}
+	for _, ct := range h.contentTypes {
+		if ct == mt {
+			return true
+		}
+	}
+	return false
+}
+
+// headResponseWriter discards the body written by a GET handler serving a HEAD
+// request and reports its length in the Content-Length header instead.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	w.size += len(b)
+	return len(b), nil
+}
+
+// Flush sends the header immediately, as the handler is streaming and the
+// length of the body is not known in advance.
+func (w *headResponseWriter) Flush() {
+	w.writeHeader()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *headResponseWriter) writeHeader() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// finish sends the header once the handler has returned. A 204 or 304 reply gets no
+// Content-Length, which RFC 9110 does not allow for them.
+func (w *headResponseWriter) finish() {
+	noBody := w.status == http.StatusNoContent || w.status == http.StatusNotModified
+	if !w.wroteHeader && !noBody && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.size))
+	}
+	w.writeHeader()
 }
@@ -4,13 +4,23 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/ninnemana/grpc-gateway/runtime"
 	"github.com/ninnemana/grpc-gateway/utilities"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -435,3 +445,1245 @@ func TestDefaultHeaderMatcher(t *testing.T) {
 		})
 	}
 }
+
+func TestServeMuxRequestID(t *testing.T) {
+	for _, spec := range []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name: "generated",
+			want: "generated-id",
+		},
+		{
+			name:   "from request",
+			header: "client-id",
+			want:   "client-id",
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(runtime.WithRequestID(func() string { return "generated-id" }))
+			pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+			if err != nil {
+				t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+			}
+			var md metadata.MD
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				if got, ok := runtime.RequestID(r.Context()); !ok || got != spec.want {
+					t.Errorf("runtime.RequestID(ctx) = %q, %v; want %q, true", got, ok, spec.want)
+				}
+				ctx, err := runtime.AnnotateContext(r.Context(), mux, r)
+				if err != nil {
+					t.Fatalf("runtime.AnnotateContext failed with %v; want success", err)
+				}
+				md, _ = metadata.FromOutgoingContext(ctx)
+			})
+
+			r := httptest.NewRequest("GET", "http://host.example/foo", nil)
+			if spec.header != "" {
+				r.Header.Set("X-Request-ID", spec.header)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if got := w.Header().Get("X-Request-ID"); got != spec.want {
+				t.Errorf("w.Header().Get(%q) = %q; want %q", "X-Request-ID", got, spec.want)
+			}
+			if got := md.Get("x-request-id"); len(got) != 1 || got[0] != spec.want {
+				t.Errorf(`md.Get("x-request-id") = %q; want [%q]`, got, spec.want)
+			}
+		})
+	}
+}
+
+func TestServeMuxRequestIDDefaultGenerator(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithRequestID(nil))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/foo", nil))
+	if got := w.Header().Get("X-Request-ID"); len(got) != 36 {
+		t.Errorf("w.Header().Get(%q) = %q; want a UUID", "X-Request-ID", got)
+	}
+}
+
+func TestServeMuxAccessLogger(t *testing.T) {
+	for _, spec := range []struct {
+		name       string
+		path       string
+		err        error
+		wantPat    string
+		wantCode   codes.Code
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			path:       "/foo",
+			wantPat:    "/foo",
+			wantCode:   codes.OK,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error",
+			path:       "/foo",
+			err:        status.Error(codes.FailedPrecondition, "not ready"),
+			wantPat:    "/foo",
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found",
+			path:       "/bar",
+			wantCode:   codes.Unimplemented,
+			wantStatus: http.StatusNotImplemented,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			var records []runtime.AccessLogRecord
+			mux := runtime.NewServeMux(
+				runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler),
+				runtime.WithAccessLogger(func(rec runtime.AccessLogRecord) {
+					records = append(records, rec)
+				}),
+			)
+			pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+			if err != nil {
+				t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+			}
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				if spec.err != nil {
+					runtime.HTTPError(r.Context(), mux, &runtime.JSONPb{}, w, r, spec.err)
+					return
+				}
+				fmt.Fprint(w, "hello")
+			})
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example"+spec.path, nil))
+
+			if len(records) != 1 {
+				t.Fatalf("access logger called %d times; want 1", len(records))
+			}
+			rec := records[0]
+			if rec.Method != "GET" || rec.Path != spec.path {
+				t.Errorf("rec.Method, rec.Path = %q, %q; want %q, %q", rec.Method, rec.Path, "GET", spec.path)
+			}
+			if rec.Pattern != spec.wantPat {
+				t.Errorf("rec.Pattern = %q; want %q", rec.Pattern, spec.wantPat)
+			}
+			if rec.Code != spec.wantCode {
+				t.Errorf("rec.Code = %v; want %v", rec.Code, spec.wantCode)
+			}
+			if rec.HTTPStatus != spec.wantStatus {
+				t.Errorf("rec.HTTPStatus = %d; want %d", rec.HTTPStatus, spec.wantStatus)
+			}
+			if got := int64(w.Body.Len()); rec.ResponseSize != got {
+				t.Errorf("rec.ResponseSize = %d; want %d", rec.ResponseSize, got)
+			}
+		})
+	}
+}
+
+func TestServeMuxRequestObserver(t *testing.T) {
+	type observation struct {
+		method, pattern string
+		code            codes.Code
+	}
+	var got []observation
+	mux := runtime.NewServeMux(
+		runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler),
+		runtime.WithRequestObserver(func(_ context.Context, method, pattern string, code codes.Code, _ time.Duration) {
+			got = append(got, observation{method, pattern, code})
+		}),
+	)
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 1}, []string{"foo", "id"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		runtime.HTTPError(r.Context(), mux, &runtime.JSONPb{}, w, r, status.Error(codes.NotFound, "no such thing"))
+	})
+
+	for _, path := range []string{"/foo/1", "/foo/2"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://host.example"+path, nil))
+	}
+
+	want := observation{method: "GET", pattern: "/foo/{id=*}", code: codes.NotFound}
+	if len(got) != 2 || got[0] != want || got[1] != want {
+		t.Errorf("observations = %+v; want 2 x %+v", got, want)
+	}
+}
+
+func TestServeMuxBodySizes(t *testing.T) {
+	var rec runtime.AccessLogRecord
+	var observedRequest, observedResponse int64
+	mux := runtime.NewServeMux(
+		runtime.WithAccessLogger(func(r runtime.AccessLogRecord) { rec = r }),
+		runtime.WithRequestObserver(func(ctx context.Context, _, _ string, _ codes.Code, _ time.Duration) {
+			observedRequest, observedResponse = runtime.BodySizes(ctx)
+		}),
+	)
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("POST", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			t.Errorf("ioutil.ReadAll(r.Body) failed with %v; want success", err)
+		}
+		ctx := runtime.NewServerMetadataContext(r.Context(), runtime.ServerMetadata{})
+		var count int
+		recv := func() (proto.Message, error) {
+			if count++; count > 3 {
+				return nil, io.EOF
+			}
+			return &empty.Empty{}, nil
+		}
+		runtime.ForwardResponseStream(ctx, mux, &runtime.JSONPb{}, w, r, recv)
+	})
+
+	body := `{"id": "request"}`
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "http://host.example/foo", strings.NewReader(body)))
+
+	if got, want := rec.RequestSize, int64(len(body)); got != want {
+		t.Errorf("rec.RequestSize = %d; want %d", got, want)
+	}
+	if got, want := rec.ResponseSize, int64(w.Body.Len()); got != want || got == 0 {
+		t.Errorf("rec.ResponseSize = %d; want %d, the whole stream", got, want)
+	}
+	if observedRequest != rec.RequestSize || observedResponse != rec.ResponseSize {
+		t.Errorf("runtime.BodySizes(ctx) = %d, %d in the observer; want %d, %d", observedRequest, observedResponse, rec.RequestSize, rec.ResponseSize)
+	}
+}
+
+func TestServeMuxMiddleware(t *testing.T) {
+	var annotations int
+	var order []string
+	var middlewareMD, handlerMD metadata.MD
+	var handlerCtx context.Context
+	mux := runtime.NewServeMux(
+		runtime.WithMetadata(func(context.Context, *http.Request) metadata.MD {
+			annotations++
+			return metadata.Pairs("x-annotation", strconv.Itoa(annotations))
+		}),
+		runtime.WithMiddleware(
+			func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					order = append(order, "outer")
+					next.ServeHTTP(w, r)
+				})
+			},
+			func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if pat, _ := runtime.HTTPPathPattern(r.Context()); pat != "/foo" {
+						t.Errorf("runtime.HTTPPathPattern(ctx) = %q in the middleware; want %q", pat, "/foo")
+					}
+					ctx, err := runtime.AnnotatedContext(r)
+					if err != nil {
+						t.Fatalf("runtime.AnnotatedContext(r) failed with %v; want success", err)
+					}
+					if _, ok := ctx.Deadline(); !ok {
+						t.Errorf("ctx.Deadline() = _, false in the middleware; want _, true")
+					}
+					middlewareMD, _ = metadata.FromOutgoingContext(ctx)
+					order = append(order, "inner")
+					next.ServeHTTP(w, r)
+				})
+			},
+		),
+	)
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		order = append(order, "handler")
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r)
+		if err != nil {
+			t.Fatalf("runtime.AnnotateContext failed with %v; want success", err)
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("ctx.Deadline() = _, false in the handler; want _, true")
+		}
+		handlerMD, _ = metadata.FromOutgoingContext(ctx)
+		handlerCtx = ctx
+	})
+
+	req := httptest.NewRequest("GET", "http://host.example/foo", nil)
+	req.Header.Set("Grpc-Timeout", "10S")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	if handlerCtx == nil {
+		t.Fatalf("handler was not invoked")
+	}
+	if got, want := handlerCtx.Err(), context.Canceled; got != want {
+		t.Errorf("handlerCtx.Err() = %v once the mux served the request; want %v", got, want)
+	}
+	if want := []string{"outer", "inner", "handler"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %q; want %q", order, want)
+	}
+	if annotations != 1 {
+		t.Errorf("annotator called %d times; want 1", annotations)
+	}
+	if got, want := middlewareMD["x-annotation"], []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`middlewareMD["x-annotation"] = %q; want %q`, got, want)
+	}
+	if !reflect.DeepEqual(handlerMD, middlewareMD) {
+		t.Errorf("metadata in the handler = %v; want %v as in the middleware", handlerMD, middlewareMD)
+	}
+
+	if _, err := runtime.AnnotatedContext(httptest.NewRequest("GET", "http://host.example/foo", nil)); err == nil {
+		t.Errorf("runtime.AnnotatedContext succeeded for a request not dispatched by a ServeMux; want failure")
+	}
+}
+
+func TestServeMuxHandleHost(t *testing.T) {
+	mux := runtime.NewServeMux()
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1}, []string{"v1", "x"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	for _, host := range []string{"api.a.com", "*.b.com", ""} {
+		host := host
+		reply := func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			fmt.Fprint(w, host)
+		}
+		if host == "" {
+			mux.Handle("GET", pat, reply)
+		} else {
+			mux.HandleHost(host, "GET", pat, reply)
+		}
+	}
+	for _, spec := range []struct {
+		host string
+		want string
+	}{
+		{host: "api.a.com", want: "api.a.com"},
+		{host: "API.A.com:8080", want: "api.a.com"},
+		{host: "api.b.com", want: "*.b.com"},
+		{host: "b.com", want: ""},
+		{host: "api.c.com", want: ""},
+	} {
+		req := httptest.NewRequest("GET", "http://example.com/v1/x", nil)
+		req.Host = spec.host
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if got := w.Body.String(); got != spec.want {
+			t.Errorf("handler for host %q = %q; want %q", spec.host, got, spec.want)
+		}
+	}
+
+	routes := mux.Routes()
+	if len(routes) != 3 || routes[0].Host != "api.a.com" || routes[1].Host != "*.b.com" || routes[2].Host != "" {
+		t.Errorf("mux.Routes() = %+v; want the hosts of the routes", routes)
+	}
+
+	scoped := runtime.NewServeMux()
+	scoped.HandleHost("api.a.com", "GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		fmt.Fprint(w, "a")
+	})
+	req := httptest.NewRequest("GET", "http://api.b.com/v1/x", nil)
+	w := httptest.NewRecorder()
+	scoped.ServeHTTP(w, req)
+	if w.Code == http.StatusOK {
+		t.Errorf("w.Code = %d for a host without routes; want an error", w.Code)
+	}
+}
+
+func TestServeMuxContentTypeRoutes(t *testing.T) {
+	mux := runtime.NewServeMux()
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	for _, version := range []string{"v1", "v2"} {
+		version := version
+		route := runtime.RouteInfo{Method: "POST", ContentTypes: []string{"application/vnd.example." + version + "+json"}}
+		if version == "v1" {
+			route.ContentTypes = append(route.ContentTypes, "application/json")
+		}
+		mux.HandleRoute(route, pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			fmt.Fprint(w, version)
+		})
+	}
+	for _, spec := range []struct {
+		contentType string
+		wantStatus  int
+		wantBody    string
+	}{
+		{contentType: "application/json", wantStatus: http.StatusOK, wantBody: "v1"},
+		{contentType: "application/vnd.example.v1+json", wantStatus: http.StatusOK, wantBody: "v1"},
+		{contentType: "Application/Vnd.Example.V2+JSON; charset=utf-8", wantStatus: http.StatusOK, wantBody: "v2"},
+		{contentType: "application/vnd.example.v3+json", wantStatus: http.StatusUnsupportedMediaType},
+		{contentType: "", wantStatus: http.StatusUnsupportedMediaType},
+	} {
+		req := httptest.NewRequest("POST", "http://host.example/foo", strings.NewReader("{}"))
+		if spec.contentType != "" {
+			req.Header.Set("Content-Type", spec.contentType)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != spec.wantStatus {
+			t.Errorf("w.Code = %d for %q; want %d", w.Code, spec.contentType, spec.wantStatus)
+		}
+		if spec.wantBody != "" && w.Body.String() != spec.wantBody {
+			t.Errorf("w.Body = %q for %q; want %q", w.Body, spec.contentType, spec.wantBody)
+		}
+	}
+
+	mux.Handle("POST", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		fmt.Fprint(w, "any")
+	})
+	req := httptest.NewRequest("POST", "http://host.example/foo", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if got, want := w.Body.String(), "any"; got != want {
+		t.Errorf("w.Body = %q with a route for any content type; want %q", got, want)
+	}
+}
+
+func TestServeMuxRecovery(t *testing.T) {
+	for _, spec := range []struct {
+		name       string
+		opts       []runtime.ServeMuxOption
+		wantStatus int
+	}{
+		{
+			name:       "default",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "custom",
+			opts: []runtime.ServeMuxOption{
+				runtime.WithRecovery(func(_ context.Context, p interface{}) error {
+					return status.Errorf(codes.Unavailable, "recovered %v", p)
+				}),
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			opts := append([]runtime.ServeMuxOption{runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler)}, spec.opts...)
+			mux := runtime.NewServeMux(opts...)
+			pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+			if err != nil {
+				t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+			}
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				panic("boom")
+			})
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/foo", nil))
+			if got := w.Code; got != spec.wantStatus {
+				t.Errorf("w.Code = %d; want %d", got, spec.wantStatus)
+			}
+		})
+	}
+}
+
+// recordingLogger is a runtime.Logger which records the messages logged to it.
+type recordingLogger struct {
+	infos, errors []string
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServeMuxLogger(t *testing.T) {
+	logger := new(recordingLogger)
+	mux := runtime.NewServeMux(runtime.WithLogger(logger))
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		if _, err := runtime.AnnotateContext(r.Context(), mux, r); err != nil {
+			t.Errorf("runtime.AnnotateContext failed with %v; want success", err)
+		}
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "http://host.example/foo", nil)
+	req.RemoteAddr = "invalid"
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	if !strings.Contains(strings.Join(logger.infos, "\n"), "invalid remote addr") {
+		t.Errorf("logger.infos = %q; want the invalid remote addr", logger.infos)
+	}
+	if len(logger.errors) != 1 || !strings.Contains(logger.errors[0], "boom") {
+		t.Errorf("logger.errors = %q; want the recovered panic", logger.errors)
+	}
+}
+
+func TestServeMuxHeadFallsBackToGet(t *testing.T) {
+	mux := runtime.NewServeMux()
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Grpc-Metadata-Foo", "bar")
+		fmt.Fprint(w, "hello")
+	})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("HEAD", "http://host.example/foo", nil))
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Errorf("w.Code = %d; want %d", got, want)
+	}
+	if got := w.Body.Len(); got != 0 {
+		t.Errorf("w.Body.Len() = %d; want 0", got)
+	}
+	if got, want := w.Header().Get("Content-Length"), "5"; got != want {
+		t.Errorf(`w.Header().Get("Content-Length") = %q; want %q`, got, want)
+	}
+	if got, want := w.Header().Get("Grpc-Metadata-Foo"), "bar"; got != want {
+		t.Errorf(`w.Header().Get("Grpc-Metadata-Foo") = %q; want %q`, got, want)
+	}
+}
+
+func TestServeMuxHeadWithoutBodyStatus(t *testing.T) {
+	for _, code := range []int{http.StatusNoContent, http.StatusNotModified} {
+		mux := runtime.NewServeMux()
+		pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+		if err != nil {
+			t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+		}
+		mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			w.WriteHeader(code)
+		})
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("HEAD", "http://host.example/foo", nil))
+		if got := w.Code; got != code {
+			t.Errorf("w.Code = %d; want %d", got, code)
+		}
+		if got, ok := w.Header()["Content-Length"]; ok {
+			t.Errorf(`w.Header()["Content-Length"] = %q for status %d; want none`, got, code)
+		}
+	}
+}
+
+func TestServeMuxRequestValidator(t *testing.T) {
+	errInvalid := fmt.Errorf("id is required")
+	for _, spec := range []struct {
+		name     string
+		opts     []runtime.ServeMuxOption
+		validate error
+		wantCode codes.Code
+	}{
+		{
+			name:     "no validator",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "plain error",
+			validate: errInvalid,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "status error",
+			validate: status.Error(codes.OutOfRange, "too big"),
+			wantCode: codes.OutOfRange,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			var opts []runtime.ServeMuxOption
+			if spec.validate != nil {
+				opts = append(opts, runtime.WithRequestValidator(func(proto.Message) error {
+					return spec.validate
+				}))
+			}
+			mux := runtime.NewServeMux(opts...)
+			pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+			if err != nil {
+				t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+			}
+			var got error
+			mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				got = runtime.ValidateRequest(r.Context(), &empty.Empty{})
+			})
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://host.example/foo", nil))
+			if code := status.Code(got); code != spec.wantCode {
+				t.Errorf("runtime.ValidateRequest(ctx, msg) = %v; want code %v", got, spec.wantCode)
+			}
+		})
+	}
+}
+
+func TestServeMuxRequireContentType(t *testing.T) {
+	for _, spec := range []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{
+			name:        "registered",
+			method:      "POST",
+			contentType: "application/json",
+			body:        "{}",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "unregistered",
+			method:      "POST",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "a=b",
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "missing",
+			method:     "POST",
+			body:       "{}",
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "no body",
+			method:     "GET",
+			wantStatus: http.StatusOK,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(
+				runtime.WithMarshalerOption("application/json", &runtime.JSONPb{}),
+				runtime.WithRequireContentType(),
+			)
+			pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+			if err != nil {
+				t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+			}
+			for _, m := range []string{"GET", "POST"} {
+				mux.Handle(m, pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {})
+			}
+
+			var body io.Reader
+			if spec.body != "" {
+				body = strings.NewReader(spec.body)
+			}
+			r := httptest.NewRequest(spec.method, "http://host.example/foo", body)
+			if spec.contentType != "" {
+				r.Header.Set("Content-Type", spec.contentType)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			if got := w.Code; got != spec.wantStatus {
+				t.Errorf("w.Code = %d; want %d", got, spec.wantStatus)
+			}
+		})
+	}
+}
+
+func TestServeMuxClientConn(t *testing.T) {
+	mux := runtime.NewServeMux()
+	if _, err := mux.ClientConn(); status.Code(err) != codes.Unavailable {
+		t.Errorf("mux.ClientConn() failed with %v; want Unavailable", err)
+	}
+
+	for _, target := range []string{"passthrough:///blue", "passthrough:///green"} {
+		cc, err := grpc.Dial(target, grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("grpc.Dial(%q) failed with %v; want success", target, err)
+		}
+		defer cc.Close()
+		mux.SetClientConn(cc)
+		got, err := mux.ClientConn()
+		if err != nil {
+			t.Fatalf("mux.ClientConn() failed with %v; want success", err)
+		}
+		if got != cc {
+			t.Errorf("mux.ClientConn() = %v; want the connection to %q", got.Target(), target)
+		}
+	}
+}
+
+func TestServeMuxResponseHeaderAnnotator(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithResponseHeaderAnnotator(func(_ context.Context, r *http.Request) http.Header {
+			return http.Header{"X-Path": []string{r.URL.Path}}
+		}),
+		runtime.WithResponseHeaderAnnotator(func(context.Context, *http.Request) http.Header {
+			return http.Header{"X-Path": []string{"second"}, "Server-Timing": []string{"annotate;dur=1"}}
+		}),
+	)
+	pat, err := runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("runtime.NewPattern failed with %v; want success", err)
+	}
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Server-Timing", "handler;dur=2")
+		fmt.Fprint(w, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/foo", nil))
+	if got, want := w.Header()["X-Path"], []string{"/foo", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`w.Header()["X-Path"] = %q; want %q`, got, want)
+	}
+	if got, want := w.Header().Get("Server-Timing"), "handler;dur=2"; got != want {
+		t.Errorf(`w.Header().Get("Server-Timing") = %q; want %q`, got, want)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/bar", nil))
+	if got := w.Header().Get("X-Path"); got != "" {
+		t.Errorf(`w.Header().Get("X-Path") = %q for an unmatched path; want ""`, got)
+	}
+}
+
+func TestServeMuxInboundMarshalerContentType(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption("application/x-protobuf", &runtime.ProtoMarshaller{}))
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("POST", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ct, ok := runtime.InboundMarshalerContentType(r.Context())
+		fmt.Fprintf(w, "%s %t", ct, ok)
+	})
+
+	for _, spec := range []struct {
+		contentType string
+		want        string
+	}{
+		{want: "application/json true"},
+		{contentType: "application/x-protobuf", want: "application/octet-stream true"},
+	} {
+		req := httptest.NewRequest("POST", "http://host.example/foo", nil)
+		if spec.contentType != "" {
+			req.Header.Set("Content-Type", spec.contentType)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if got := w.Body.String(); got != spec.want {
+			t.Errorf("Content-Type %q: w.Body = %q; want %q", spec.contentType, got, spec.want)
+		}
+	}
+
+	if _, ok := runtime.InboundMarshalerContentType(context.Background()); ok {
+		t.Errorf("runtime.InboundMarshalerContentType(context.Background()) succeeded; want false")
+	}
+}
+
+func TestServeMuxOutboundMarshalerContentType(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption("application/x-protobuf", &runtime.ProtoMarshaller{}))
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ct, ok := runtime.OutboundMarshalerContentType(r.Context())
+		fmt.Fprintf(w, "%s %t", ct, ok)
+	})
+
+	for _, spec := range []struct {
+		accept string
+		want   string
+	}{
+		{want: "application/json true"},
+		{accept: "application/x-protobuf", want: "application/octet-stream true"},
+	} {
+		req := httptest.NewRequest("GET", "http://host.example/foo", nil)
+		if spec.accept != "" {
+			req.Header.Set("Accept", spec.accept)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if got := w.Body.String(); got != spec.want {
+			t.Errorf("Accept %q: w.Body = %q; want %q", spec.accept, got, spec.want)
+		}
+	}
+
+	if _, ok := runtime.OutboundMarshalerContentType(context.Background()); ok {
+		t.Errorf("runtime.OutboundMarshalerContentType(context.Background()) succeeded; want false")
+	}
+}
+
+type charsetMarshaler struct {
+	runtime.JSONPb
+}
+
+func (*charsetMarshaler) ContentType() string { return "text/plain; charset=utf-8" }
+func (*charsetMarshaler) Charsets() []string  { return []string{"utf-8", "iso-8859-1"} }
+func (*charsetMarshaler) WithCharset(cs string) runtime.Marshaler {
+	return &runtime.ContentTypeMarshaler{Marshaler: &runtime.JSONPb{}, Type: "text/plain; charset=" + cs}
+}
+
+func TestServeMuxAcceptCharset(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption("text/plain", &charsetMarshaler{}),
+		runtime.WithMarshalerOption("application/x-protobuf", &runtime.ProtoMarshaller{}),
+		runtime.WithAcceptCharset(),
+	)
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ct, _ := runtime.OutboundMarshalerContentType(r.Context())
+		fmt.Fprint(w, ct)
+	})
+
+	for _, spec := range []struct {
+		accept, acceptCharset string
+		wantStatus            int
+		wantBody              string
+	}{
+		{wantStatus: http.StatusOK, wantBody: "application/json"},
+		{acceptCharset: "iso-8859-1, utf-8;q=0.5", wantStatus: http.StatusOK, wantBody: "application/json"},
+		{acceptCharset: "iso-8859-1", wantStatus: http.StatusNotAcceptable},
+		{acceptCharset: "*;q=0.1", wantStatus: http.StatusOK, wantBody: "application/json"},
+		{accept: "text/plain", wantStatus: http.StatusOK, wantBody: "text/plain; charset=utf-8"},
+		{accept: "text/plain", acceptCharset: "ISO-8859-1, utf-8;q=0.5", wantStatus: http.StatusOK, wantBody: "text/plain; charset=iso-8859-1"},
+		{accept: "text/plain", acceptCharset: "utf-8;q=0, *", wantStatus: http.StatusOK, wantBody: "text/plain; charset=iso-8859-1"},
+		{accept: "text/plain", acceptCharset: "utf-16", wantStatus: http.StatusNotAcceptable},
+		{accept: "application/x-protobuf", acceptCharset: "utf-16", wantStatus: http.StatusOK, wantBody: "application/octet-stream"},
+	} {
+		req := httptest.NewRequest("GET", "http://host.example/foo", nil)
+		if spec.accept != "" {
+			req.Header.Set("Accept", spec.accept)
+		}
+		if spec.acceptCharset != "" {
+			req.Header.Set("Accept-Charset", spec.acceptCharset)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if got := w.Code; got != spec.wantStatus {
+			t.Errorf("Accept %q, Accept-Charset %q: w.Code = %d; want %d", spec.accept, spec.acceptCharset, got, spec.wantStatus)
+			continue
+		}
+		if spec.wantStatus == http.StatusOK {
+			if got := w.Body.String(); got != spec.wantBody {
+				t.Errorf("Accept %q, Accept-Charset %q: w.Body = %q; want %q", spec.accept, spec.acceptCharset, got, spec.wantBody)
+			}
+		}
+	}
+}
+
+func TestServeMuxLivenessEndpoint(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithLivenessEndpoint("/healthz"))
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		t.Errorf("handler called for %s", r.URL.Path)
+	})
+
+	for _, spec := range []struct {
+		method   string
+		wantCode int
+		wantBody string
+	}{
+		{method: "GET", wantCode: http.StatusOK, wantBody: "ok\n"},
+		{method: "HEAD", wantCode: http.StatusOK},
+		{method: "POST", wantCode: http.StatusMethodNotAllowed, wantBody: "Method Not Allowed\n"},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(spec.method, "http://host.example/healthz", nil))
+		if got := w.Code; got != spec.wantCode {
+			t.Errorf("w.Code = %d for %s; want %d", got, spec.method, spec.wantCode)
+		}
+		if got := w.Body.String(); got != spec.wantBody {
+			t.Errorf("w.Body = %q for %s; want %q", got, spec.method, spec.wantBody)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/healthz/", nil))
+	if w.Code == http.StatusOK {
+		t.Errorf("w.Code = %d for /healthz/; want it to be routed as usual", w.Code)
+	}
+}
+
+func TestServeMuxRouteMetadata(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithRouteMetadata("/admin", func(context.Context, *http.Request) metadata.MD {
+		return metadata.Pairs("x-internal", "yes")
+	}))
+	h := func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r)
+		if err != nil {
+			t.Fatalf("runtime.AnnotateContext(ctx, mux, r) failed with %v; want success", err)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		fmt.Fprint(w, md.Get("x-internal"))
+	}
+	mux.Handle("GET", runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"admin"}, "")), h)
+	mux.Handle("GET", runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, "")), h)
+
+	for _, spec := range []struct {
+		path string
+		want string
+	}{
+		{path: "/admin", want: "[yes]"},
+		{path: "/foo", want: "[]"},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example"+spec.path, nil))
+		if got := w.Body.String(); got != spec.want {
+			t.Errorf("w.Body = %q for %s; want %q", got, spec.path, spec.want)
+		}
+	}
+}
+
+func TestServeMuxShutdown(t *testing.T) {
+	mux := runtime.NewServeMux()
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"foo"}, ""))
+	started, release := make(chan struct{}), make(chan struct{})
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		close(started)
+		<-release
+		fmt.Fprint(w, "done")
+	})
+
+	inFlight := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		mux.ServeHTTP(inFlight, httptest.NewRequest("GET", "http://host.example/foo", nil))
+		close(served)
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- mux.Shutdown(context.Background()) }()
+
+	// Wait until the mux rejects new requests.
+	for {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example/bar", nil))
+		if w.Header().Get("Connection") == "close" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("mux.Shutdown returned %v while a request was in flight", err)
+	default:
+	}
+
+	close(release)
+	<-served
+	if err := <-shutdown; err != nil {
+		t.Errorf("mux.Shutdown(ctx) failed with %v; want success", err)
+	}
+	if got, want := inFlight.Body.String(), "done"; got != want {
+		t.Errorf("in-flight response body = %q; want %q", got, want)
+	}
+}
+
+func TestServeMuxShutdownTimeout(t *testing.T) {
+	mux := runtime.NewServeMux()
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0}, []string{"stream"}, ""))
+	started := make(chan struct{})
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		close(started)
+		// A stream which only ends when its request is canceled.
+		<-r.Context().Done()
+	})
+
+	served := make(chan struct{})
+	go func() {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://host.example/stream", nil))
+		close(served)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := mux.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("mux.Shutdown(ctx) = %v; want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Errorf("the stream was not canceled after mux.Shutdown timed out")
+	}
+}
+
+// statusUnmatchedPath is the status DefaultHTTPProtoErrorHandler replies to unmatched paths with:
+// ErrUnknownURI is codes.Unimplemented, hence http.StatusNotImplemented.
+const statusUnmatchedPath = http.StatusNotImplemented
+
+func TestServeMuxTrailingSlashPolicy(t *testing.T) {
+	for _, spec := range []struct {
+		name         string
+		policy       runtime.TrailingSlashPolicy
+		method       string
+		url          string
+		wantCode     int
+		wantLocation string
+	}{
+		{
+			name:     "strict",
+			policy:   runtime.TrailingSlashStrict,
+			method:   "GET",
+			url:      "http://host.example/v1/books/",
+			wantCode: statusUnmatchedPath,
+		},
+		{
+			name:         "redirect GET",
+			policy:       runtime.TrailingSlashRedirect,
+			method:       "GET",
+			url:          "http://host.example/v1/books/?page=2",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "/v1/books?page=2",
+		},
+		{
+			name:         "redirect POST",
+			policy:       runtime.TrailingSlashRedirect,
+			method:       "POST",
+			url:          "http://host.example/v1/books/",
+			wantCode:     http.StatusPermanentRedirect,
+			wantLocation: "/v1/books",
+		},
+		{
+			name:     "redirect unknown path",
+			policy:   runtime.TrailingSlashRedirect,
+			method:   "GET",
+			url:      "http://host.example/v1/authors/",
+			wantCode: statusUnmatchedPath,
+		},
+		{
+			name:     "ignore",
+			policy:   runtime.TrailingSlashIgnore,
+			method:   "POST",
+			url:      "http://host.example/v1/books/",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "ignore without slash",
+			policy:   runtime.TrailingSlashIgnore,
+			method:   "GET",
+			url:      "http://host.example/v1/books",
+			wantCode: http.StatusOK,
+		},
+	} {
+		t.Run(spec.name, func(t *testing.T) {
+			mux := runtime.NewServeMux(runtime.WithTrailingSlashPolicy(spec.policy), runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler))
+			pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1}, []string{"v1", "books"}, ""))
+			for _, method := range []string{"GET", "POST"} {
+				mux.Handle(method, pat, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+					fmt.Fprint(w, "ok")
+				})
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(spec.method, spec.url, nil))
+			if w.Code != spec.wantCode {
+				t.Errorf("w.Code = %d; want %d; body %s", w.Code, spec.wantCode, w.Body)
+			}
+			if got := w.Header().Get("Location"); got != spec.wantLocation {
+				t.Errorf(`w.Header().Get("Location") = %q; want %q`, got, spec.wantLocation)
+			}
+		})
+	}
+}
+
+func TestServeMuxCaseInsensitivePaths(t *testing.T) {
+	for _, spec := range []struct {
+		opts     []runtime.ServeMuxOption
+		url      string
+		wantCode int
+		wantName string
+	}{
+		{
+			url:      "http://host.example/v1/books/Foo",
+			wantCode: http.StatusOK,
+			wantName: "Foo",
+		},
+		{
+			url:      "http://host.example/V1/Books/Foo",
+			wantCode: statusUnmatchedPath,
+		},
+		{
+			opts:     []runtime.ServeMuxOption{runtime.WithCaseInsensitivePaths()},
+			url:      "http://host.example/V1/Books/Foo",
+			wantCode: http.StatusOK,
+			wantName: "Foo",
+		},
+	} {
+		mux := runtime.NewServeMux(append(spec.opts, runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler))...)
+		pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 2}, []string{"v1", "books", "name"}, ""))
+		var gotName string
+		mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			gotName = pathParams["name"]
+		})
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", spec.url, nil))
+		if w.Code != spec.wantCode {
+			t.Errorf("w.Code = %d for %s; want %d", w.Code, spec.url, spec.wantCode)
+		}
+		if gotName != spec.wantName {
+			t.Errorf(`pathParams["name"] = %q for %s; want %q`, gotName, spec.url, spec.wantName)
+		}
+	}
+}
+
+func TestServeMuxDefaultOptionsHandler(t *testing.T) {
+	for _, spec := range []struct {
+		opts      []runtime.ServeMuxOption
+		url       string
+		wantCode  int
+		wantAllow string
+	}{
+		{
+			url:      "http://host.example/v1/books",
+			wantCode: statusUnmatchedPath,
+		},
+		{
+			opts:      []runtime.ServeMuxOption{runtime.WithDefaultOptionsHandler()},
+			url:       "http://host.example/v1/books",
+			wantCode:  http.StatusNoContent,
+			wantAllow: "GET, HEAD, OPTIONS, POST",
+		},
+		{
+			opts:     []runtime.ServeMuxOption{runtime.WithDefaultOptionsHandler()},
+			url:      "http://host.example/v1/authors",
+			wantCode: statusUnmatchedPath,
+		},
+	} {
+		mux := runtime.NewServeMux(append(spec.opts, runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler))...)
+		pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1}, []string{"v1", "books"}, ""))
+		noop := func(http.ResponseWriter, *http.Request, map[string]string) {}
+		mux.Handle("GET", pat, noop)
+		mux.Handle("POST", pat, noop)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("OPTIONS", spec.url, nil))
+		if w.Code != spec.wantCode {
+			t.Errorf("w.Code = %d for %s; want %d", w.Code, spec.url, spec.wantCode)
+		}
+		if got := w.Header().Get("Allow"); got != spec.wantAllow {
+			t.Errorf("w.Header().Get(%q) = %q for %s; want %q", "Allow", got, spec.url, spec.wantAllow)
+		}
+	}
+}
+
+func TestServeMuxRoutes(t *testing.T) {
+	mux := runtime.NewServeMux()
+	books := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1}, []string{"v1", "books"}, ""))
+	book := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 2}, []string{"v1", "books", "name"}, ""))
+	noop := func(http.ResponseWriter, *http.Request, map[string]string) {}
+	mux.Handle("GET", books, noop)
+	mux.HandleRoute(runtime.RouteInfo{Method: "POST", Body: "book"}, books, noop)
+	mux.HandleRoute(runtime.RouteInfo{Method: "PATCH", Body: "*"}, book, noop)
+
+	want := []runtime.RouteInfo{
+		{Method: "GET", Pattern: "/v1/books"},
+		{Method: "POST", Pattern: "/v1/books", Body: "book"},
+		{Method: "PATCH", Pattern: "/v1/books/{name=*}", Body: "*"},
+	}
+	if got := mux.Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mux.Routes() = %+v; want %+v", got, want)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "http://host.example/v1/books", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("w.Code = %d; want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestServeMuxHandlePatterns(t *testing.T) {
+	mux := runtime.NewServeMux()
+	book := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 2}, []string{"v1", "books", "name"}, ""))
+	legacy := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 1}, []string{"book", "name"}, ""))
+	mux.HandlePatterns("GET", []runtime.Pattern{book, legacy}, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		pat, _ := runtime.HTTPPathPattern(r.Context())
+		fmt.Fprintf(w, "%s %s", pat, pathParams["name"])
+	})
+
+	want := []runtime.RouteInfo{
+		{Method: "GET", Pattern: "/v1/books/{name=*}"},
+		{Method: "GET", Pattern: "/book/{name=*}"},
+	}
+	if got := mux.Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mux.Routes() = %+v; want %+v", got, want)
+	}
+
+	for _, spec := range []struct {
+		path string
+		want string
+	}{
+		{path: "/v1/books/foo", want: "/v1/books/{name=*} foo"},
+		{path: "/book/foo", want: "/book/{name=*} foo"},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "http://host.example"+spec.path, nil))
+		if got := w.Body.String(); got != spec.want {
+			t.Errorf("w.Body = %q for %s; want %q", got, spec.path, spec.want)
+		}
+	}
+}
+
+func TestServeMuxHandler(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithResponseHeaderAnnotator(func(context.Context, *http.Request) http.Header {
+			return http.Header{"X-Annotated": []string{"yes"}}
+		}),
+		runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler),
+	)
+	pat := runtime.MustPattern(runtime.NewPattern(1, []int{int(utilities.OpLitPush), 0, int(utilities.OpLitPush), 1, int(utilities.OpPush), 0, int(utilities.OpConcatN), 1, int(utilities.OpCapture), 2}, []string{"v1", "books", "name"}, ""))
+	mux.Handle("GET", pat, func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		fmt.Fprint(w, pathParams["name"])
+	})
+
+	if _, err := mux.Handler("POST", "/v1/books/{name=*}"); err == nil {
+		t.Errorf(`mux.Handler("POST", "/v1/books/{name=*}") did not fail; want error`)
+	}
+	h, err := mux.Handler("GET", "/v1/books/{name=*}")
+	if err != nil {
+		t.Fatalf(`mux.Handler("GET", "/v1/books/{name=*}") failed with %v; want success`, err)
+	}
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "http://host.example/v1/books/foo", nil))
+	if got, want := w.Body.String(), "foo"; got != want {
+		t.Errorf("w.Body = %q; want %q", got, want)
+	}
+	if got, want := w.Header().Get("X-Annotated"), "yes"; got != want {
+		t.Errorf(`w.Header().Get("X-Annotated") = %q; want %q`, got, want)
+	}
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "http://host.example/v1/authors/foo", nil))
+	if got, want := w.Code, statusUnmatchedPath; got != want {
+		t.Errorf("w.Code = %d for an unmatched path; want %d", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest("POST", "http://host.example/v1/books/foo", nil))
+	if got, want := w.Code, http.StatusMethodNotAllowed; got != want {
+		t.Errorf("w.Code = %d for a POST request; want %d; body %s", got, want, w.Body)
+	}
+	if got, want := w.Header().Get("Allow"), "GET"; got != want {
+		t.Errorf(`w.Header().Get("Allow") = %q; want %q`, got, want)
+	}
+}
+
+func TestServeMuxDefaultTimeout(t *testing.T) {
+	defer func(d time.Duration) { runtime.DefaultContextTimeout = d }(runtime.DefaultContextTimeout)
+	mux := runtime.NewServeMux()
+	for _, d := range []time.Duration{0, 3 * time.Second} {
+		runtime.DefaultContextTimeout = d
+		if got := mux.DefaultTimeout(); got != d {
+			t.Errorf("mux.DefaultTimeout() = %v; want %v", got, d)
+		}
+	}
+
+	runtime.DefaultContextTimeout = 3 * time.Second
+	for _, d := range []time.Duration{0, time.Second} {
+		mux := runtime.NewServeMux(runtime.WithDefaultTimeout(d))
+		if got := mux.DefaultTimeout(); got != d {
+			t.Errorf("mux.DefaultTimeout() = %v with WithDefaultTimeout(%v); want %v", got, d, d)
+		}
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		annotated, err := runtime.AnnotateContext(context.Background(), mux, req)
+		if err != nil {
+			t.Fatalf("runtime.AnnotateContext(ctx, mux, req) failed with %v; want success", err)
+		}
+		deadline, ok := annotated.Deadline()
+		if ok != (d != 0) {
+			t.Errorf("annotated.Deadline() = _, %t with WithDefaultTimeout(%v); want _, %t", ok, d, d != 0)
+		}
+		if ok && time.Until(deadline) > d {
+			t.Errorf("time.Until(deadline) = %v with WithDefaultTimeout(%v); want at most %v", time.Until(deadline), d, d)
+		}
+	}
+}
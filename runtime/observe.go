@@ -0,0 +1,274 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// AccessLogRecord describes a request served by a ServeMux.
+type AccessLogRecord struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request.
+	Path string
+	// Pattern is the path template of the matched route. It is empty if no
+	// route matched the request.
+	Pattern string
+	// Code is the gRPC status code the gateway responded with.
+	Code codes.Code
+	// HTTPStatus is the HTTP status code written to the response.
+	HTTPStatus int
+	// Duration is the time taken to serve the request.
+	Duration time.Duration
+	// RequestSize is the number of request body bytes read.
+	RequestSize int64
+	// ResponseSize is the number of response body bytes written, in all
+	// the messages of a streaming response.
+	ResponseSize int64
+}
+
+// WithAccessLogger returns a ServeMuxOption which calls logger once for every
+// request after it has been served.
+func WithAccessLogger(logger func(AccessLogRecord)) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.accessLogger = logger
+	}
+}
+
+// RequestObserverFunc is called after each request served by a ServeMux.
+//
+// pattern is the path template of the matched route, or empty if no route
+// matched, so that it can be used as a metrics label of bounded cardinality.
+type RequestObserverFunc func(ctx context.Context, method, pattern string, code codes.Code, d time.Duration)
+
+// WithRequestObserver returns a ServeMuxOption which calls observer once for
+// every request after it has been served.
+func WithRequestObserver(observer RequestObserverFunc) ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.requestObserver = observer
+	}
+}
+
+// BodySizes returns the number of request body bytes read and response body bytes
+// written so far for the request ctx comes from, e.g. for a RequestObserverFunc to
+// report them as metrics, in which case they are the totals of the request. They
+// are only counted for a mux created with WithAccessLogger, WithRequestObserver or
+// WithServerTiming.
+func BodySizes(ctx context.Context) (request, response int64) {
+	st := requestStateFromContext(ctx)
+	if st == nil {
+		return 0, 0
+	}
+	if st.body != nil {
+		request = st.body.n
+	}
+	if st.w != nil {
+		response = st.w.size
+	}
+	return request, response
+}
+
+// WithServerTiming returns a ServeMuxOption which adds a Server-Timing header to
+// every response. It reports the time until the header was written as "total",
+// the time spent in unary gRPC calls as "backend" and the time spent marshaling
+// the response message as "marshal", all in milliseconds.
+func WithServerTiming() ServeMuxOption {
+	return func(serveMux *ServeMux) {
+		serveMux.serverTiming = true
+	}
+}
+
+// instrument wraps w and r so that the outcome of the request can be reported
+// to the access logger and the request observer by calling the returned func.
+// It also adds the Server-Timing header if the mux is configured to.
+func (s *ServeMux) instrument(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	start := time.Now()
+	st := &requestState{}
+	rw := &responseWriter{ResponseWriter: w}
+	if s.serverTiming {
+		rw.beforeHeader = func() {
+			rw.Header().Add("Server-Timing", fmt.Sprintf("total;dur=%s, backend;dur=%s, marshal;dur=%s",
+				formatMillis(time.Since(start)), formatMillis(st.backend), formatMillis(st.marshal)))
+		}
+	}
+	st.w = rw
+	r = r.WithContext(context.WithValue(r.Context(), requestStateKey{}, st))
+	if r.Body != nil {
+		st.body = &countingBody{ReadCloser: r.Body}
+		r.Body = st.body
+	}
+	method, path := r.Method, r.URL.Path
+	return rw, r, func() {
+		d := time.Since(start)
+		code := st.codeFor(rw.statusCode())
+		requestSize, _ := BodySizes(r.Context())
+		if s.requestObserver != nil {
+			s.requestObserver(r.Context(), method, st.pattern, code, d)
+		}
+		if s.accessLogger != nil {
+			s.accessLogger(AccessLogRecord{
+				Method:       method,
+				Path:         path,
+				Pattern:      st.pattern,
+				Code:         code,
+				HTTPStatus:   rw.statusCode(),
+				Duration:     d,
+				RequestSize:  requestSize,
+				ResponseSize: rw.size,
+			})
+		}
+	}
+}
+
+type requestStateKey struct{}
+
+// requestState collects what a ServeMux learns about a request while it is
+// being served.
+type requestState struct {
+	pattern string
+	code    codes.Code
+	codeSet bool
+	backend time.Duration
+	marshal time.Duration
+	body    *countingBody
+	w       *responseWriter
+}
+
+func requestStateFromContext(ctx context.Context) *requestState {
+	st, _ := ctx.Value(requestStateKey{}).(*requestState)
+	return st
+}
+
+// recordCode records the gRPC status code the gateway responded to r with.
+func recordCode(r *http.Request, code codes.Code) {
+	if r == nil {
+		return
+	}
+	if st := requestStateFromContext(r.Context()); st != nil {
+		st.code = code
+		st.codeSet = true
+	}
+}
+
+// recordBackendTime adds d to the time spent in gRPC calls for r.
+func recordBackendTime(r *http.Request, d time.Duration) {
+	if st := requestStateFromContext(r.Context()); st != nil {
+		st.backend += d
+	}
+}
+
+// recordMarshalTime adds d to the time spent marshaling the response to r.
+func recordMarshalTime(r *http.Request, d time.Duration) {
+	if st := requestStateFromContext(r.Context()); st != nil {
+		st.marshal += d
+	}
+}
+
+// formatMillis formats d as a number of milliseconds for a Server-Timing header.
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// codeFor returns the recorded gRPC status code, or one derived from the HTTP
+// status if error handlers did not record any.
+func (st *requestState) codeFor(httpStatus int) codes.Code {
+	if st.codeSet {
+		return st.code
+	}
+	return codeFromHTTPStatus(httpStatus)
+}
+
+// codeFromHTTPStatus approximates the gRPC code for a response status written
+// without going through the gateway's error handlers.
+func codeFromHTTPStatus(st int) codes.Code {
+	switch st {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if st < http.StatusBadRequest {
+		return codes.OK
+	}
+	return codes.Unknown
+}
+
+// countingBody wraps a request body to count the bytes read from it.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// responseWriter wraps an http.ResponseWriter to record the status and the
+// size of the response.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+	// beforeHeader, if not nil, is called right before the header is written.
+	beforeHeader func()
+}
+
+// setStatus records the status of the response the first time it is called.
+func (w *responseWriter) setStatus(code int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	if w.beforeHeader != nil {
+		w.beforeHeader()
+	}
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.setStatus(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.setStatus(http.StatusOK)
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher so that streaming keeps working.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.setStatus(http.StatusOK)
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -161,6 +161,12 @@ func MustPattern(p Pattern, err error) Pattern {
 // If it matches, the function returns a mapping from field paths to their captured values.
 // If otherwise, the function returns an error.
 func (p Pattern) Match(components []string, verb string) (map[string]string, error) {
+	return p.match(components, verb, false)
+}
+
+// match is Match, but compares the literal segments of components case-insensitively if foldCase is true.
+// Captured values are returned verbatim.
+func (p Pattern) match(components []string, verb string, foldCase bool) (map[string]string, error) {
 	if p.verb != verb {
 		if p.assumeColonVerb || p.verb != "" {
 			return nil, ErrNotMatch
@@ -188,7 +194,7 @@ func (p Pattern) Match(components []string, verb string) (map[string]string, err
 			}
 			c := components[pos]
 			if op.code == utilities.OpLitPush {
-				if lit := p.pool[op.operand]; c != lit {
+				if lit := p.pool[op.operand]; c != lit && !(foldCase && strings.EqualFold(c, lit)) {
 					return nil, ErrNotMatch
 				}
 			}
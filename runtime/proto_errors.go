@@ -8,7 +8,6 @@ import (
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/ninnemana/grpc-gateway/internal"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/status"
 )
 
@@ -32,7 +31,7 @@ var _ ProtoErrorHandlerFunc = DefaultHTTPProtoErrorHandler
 // The response body returned by this function is a Status message marshaled by a Marshaler.
 //
 // Do not set this function to HTTPError variable directly, use WithProtoErrorHandler option instead.
-func DefaultHTTPProtoErrorHandler(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+func DefaultHTTPProtoErrorHandler(ctx context.Context, mux *ServeMux, marshaler Marshaler, w http.ResponseWriter, r *http.Request, err error) {
 	// return Internal when Marshal failed
 	const fallback = `{"code": 13, "message": "failed to marshal error message"}`
 
@@ -55,28 +54,32 @@ func DefaultHTTPProtoErrorHandler(ctx context.Context, mux *ServeMux, marshaler
 
 	buf, merr := marshaler.Marshal(s.Proto())
 	if merr != nil {
-		grpclog.Infof("Failed to marshal error message %q: %v", s.Proto(), merr)
+		loggerOf(mux).Infof("Failed to marshal error message %q: %v", s.Proto(), merr)
 		w.WriteHeader(http.StatusInternalServerError)
 		if _, err := io.WriteString(w, fallback); err != nil {
-			grpclog.Infof("Failed to write response: %v", err)
+			loggerOf(mux).Infof("Failed to write response: %v", err)
 		}
 		return
 	}
 
 	md, ok := ServerMetadataFromContext(ctx)
 	if !ok {
-		grpclog.Infof("Failed to extract ServerMetadata from context")
+		loggerOf(mux).Infof("Failed to extract ServerMetadata from context")
 	}
 
 	handleForwardResponseServerMetadata(w, mux, md)
-	handleForwardResponseTrailerHeader(w, md)
-	st := HTTPStatusFromCode(s.Code())
+	handleForwardResponseTrailerHeader(w, mux, md)
+	handleRetryAfter(w, s)
+	handleStatusDetails(w, mux, s)
+	handleDeadlineDiagnostics(ctx, w, mux, r, s.Code())
+	recordCode(r, s.Code())
+	st := httpStatusForError(mux, r, s.Code())
 	w.WriteHeader(st)
 	if _, err := w.Write(buf); err != nil {
-		grpclog.Infof("Failed to write response: %v", err)
+		loggerOf(mux).Infof("Failed to write response: %v", err)
 	}
 
-	handleForwardResponseTrailer(w, md)
+	handleForwardResponseTrailer(w, mux, md)
 }
 
 // DefaultHTTPStreamErrorHandler converts the given err into a *StreamError via
@@ -84,9 +87,10 @@ func DefaultHTTPProtoErrorHandler(ctx context.Context, mux *ServeMux, marshaler
 //
 // It extracts the gRPC status from err if possible. The fields of the status are
 // used to populate the returned StreamError, and the HTTP status code is derived
-// from the gRPC code via HTTPStatusFromCode. If the given err does not contain a
+// from the gRPC code via HTTPStatusFromCode, or the table set with WithErrorStatusTable
+// on the mux serving the request. If the given err does not contain a
 // gRPC status, an "Unknown" gRPC code is used and "Internal Server Error" HTTP code.
-func DefaultHTTPStreamErrorHandler(_ context.Context, err error) *StreamError {
+func DefaultHTTPStreamErrorHandler(ctx context.Context, err error) *StreamError {
 	grpcCode := codes.Unknown
 	grpcMessage := err.Error()
 	var grpcDetails []*any.Any
@@ -95,7 +99,8 @@ func DefaultHTTPStreamErrorHandler(_ context.Context, err error) *StreamError {
 		grpcMessage = s.Message()
 		grpcDetails = s.Proto().GetDetails()
 	}
-	httpCode := HTTPStatusFromCode(grpcCode)
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	httpCode := httpStatusForError(mux, nil, grpcCode)
 	return &StreamError{
 		GrpcCode:   int32(grpcCode),
 		HttpCode:   int32(httpCode),
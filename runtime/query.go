@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/url"
@@ -12,14 +13,30 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/ninnemana/grpc-gateway/utilities"
-	"google.golang.org/grpc/grpclog"
 )
 
 var valuesKeyRegexp = regexp.MustCompile("^(.*)\\[(.*)\\]$")
 
 // PopulateQueryParameters populates "values" into "msg".
 // A value is ignored if its key starts with one of the elements in "filter".
+//
+// Dotted keys traverse message fields, creating the intermediate messages as needed, e.g.
+// "filter.tags=a&filter.tags=b" sets the repeated field "tags" of the message field "filter".
+// Values of repeated fields are appended, so a field may be given under both its proto and JSON names.
+// It is an error for a key to traverse a scalar or repeated field.
+//
+// An empty value sets a wrapper field such as google.protobuf.Int32Value to its zero value, which is
+// present, while the field of an absent key stays nil.
+//
+// The filter is matched against the proto names of the fields on the path of the key, even if the
+// key uses their JSON names, so values from the path and the body always take precedence over
+// query parameters for the same field. See WithQueryOverridesBody to let them override the body.
 func PopulateQueryParameters(msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	return populateQueryParameters(msg, values, filter, nil)
+}
+
+// populateQueryParameters is PopulateQueryParameters with the options of mux, which may be nil.
+func populateQueryParameters(msg proto.Message, values url.Values, filter *utilities.DoubleArray, mux *ServeMux) error {
 	for key, values := range values {
 		match := valuesKeyRegexp.FindStringSubmatch(key)
 		if len(match) == 3 {
@@ -27,24 +44,202 @@ func PopulateQueryParameters(msg proto.Message, values url.Values, filter *utili
 			values = append([]string{match[2]}, values...)
 		}
 		fieldPath := strings.Split(key, ".")
-		if filter.HasCommonPrefix(fieldPath) {
+		if filter.HasCommonPrefix(protoFieldPath(msg, fieldPath)) {
 			continue
 		}
-		if err := populateFieldValueFromPath(msg, fieldPath, values); err != nil {
+		if err := populateFieldValueFromPath(msg, fieldPath, values, mux); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// PopulateQueryParametersContext is like PopulateQueryParameters, but also honors the options the mux
+// of the request ctx comes from was created with, such as WithBracketQueryNotation,
+// WithCustomTypeDecoder and WithCaseInsensitiveEnums.
+func PopulateQueryParametersContext(ctx context.Context, msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	values, err := queryValuesForMux(ctx, msg, values)
+	if err != nil {
+		return err
+	}
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	return populateQueryParameters(msg, values, filter, mux)
+}
+
+// queryValuesForMux returns values with the keys in bracket notation rewritten to dotted field paths
+// of msg if the mux of the request ctx comes from was created with WithBracketQueryNotation, or values
+// otherwise.
+func queryValuesForMux(ctx context.Context, msg proto.Message, values url.Values) (url.Values, error) {
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	if mux == nil || !mux.bracketQueryNotation {
+		return values, nil
+	}
+	result := make(url.Values, len(values))
+	for key, vs := range values {
+		key, err := bracketQueryKey(msg, key)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = append(result[key], vs...)
+	}
+	return result, nil
+}
+
+// bracketQueryKey rewrites the query parameter key in bracket notation, e.g. "filter[tags][]", to the
+// dotted field path of msg it names, e.g. "filter.tags". A bracket after a map field is kept, as the
+// key of the map entry.
+func bracketQueryKey(msg proto.Message, key string) (string, error) {
+	i := strings.IndexByte(key, '[')
+	if i < 0 {
+		if strings.IndexByte(key, ']') >= 0 {
+			return "", fmt.Errorf("malformed query parameter %q: unexpected ]", key)
+		}
+		return key, nil
+	}
+	if i == 0 {
+		return "", fmt.Errorf("malformed query parameter %q: missing field name before [", key)
+	}
+	fieldPath := strings.Split(key[:i], ".")
+	var segments []string
+	for rest := key[i:]; rest != ""; {
+		if rest[0] != '[' {
+			return "", fmt.Errorf("malformed query parameter %q: unexpected %q after ]", key, rest)
+		}
+		j := strings.IndexByte(rest, ']')
+		if j < 0 {
+			return "", fmt.Errorf("malformed query parameter %q: unclosed [", key)
+		}
+		segment := rest[1:j]
+		if strings.IndexByte(segment, '[') >= 0 {
+			return "", fmt.Errorf("malformed query parameter %q: nested [", key)
+		}
+		rest = rest[j+1:]
+		if segment == "" {
+			if rest != "" {
+				return "", fmt.Errorf("malformed query parameter %q: [] must come last", key)
+			}
+			break
+		}
+		segments = append(segments, segment)
+	}
+
+	t := reflect.TypeOf(msg)
+	for _, name := range fieldPath {
+		_, t = lookupField(t, name)
+	}
+	for n, segment := range segments {
+		if t != nil && t.Kind() == reflect.Map {
+			if n != len(segments)-1 {
+				return "", fmt.Errorf("malformed query parameter %q: map key %q must come last", key, segment)
+			}
+			return fmt.Sprintf("%s[%s]", strings.Join(fieldPath, "."), segment), nil
+		}
+		fieldPath = append(fieldPath, segment)
+		_, t = lookupField(t, segment)
+	}
+	return strings.Join(fieldPath, "."), nil
+}
+
+// PopulateBodyQueryParameters populates the query parameters in values which target fields inside
+// the message field bodyFieldPath of msg, which the request body was decoded into, if the mux of
+// the request ctx comes from was created with WithQueryOverridesBody. Otherwise it does nothing,
+// and PopulateQueryParameters ignores these parameters.
+func PopulateBodyQueryParameters(ctx context.Context, msg proto.Message, values url.Values, bodyFieldPath string) error {
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	if mux == nil || !mux.queryOverridesBody {
+		return nil
+	}
+	values, err := queryValuesForMux(ctx, msg, values)
+	if err != nil {
+		return err
+	}
+	bodyPath := strings.Split(bodyFieldPath, ".")
+	for key, values := range values {
+		match := valuesKeyRegexp.FindStringSubmatch(key)
+		if len(match) == 3 {
+			key = match[1]
+			values = append([]string{match[2]}, values...)
+		}
+		fieldPath := strings.Split(key, ".")
+		if !hasFieldPathPrefix(protoFieldPath(msg, fieldPath), bodyPath) {
+			continue
+		}
+		if err := populateFieldValueFromPath(msg, fieldPath, values, mux); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasFieldPathPrefix reports whether fieldPath is a field inside the field prefix.
+func hasFieldPathPrefix(fieldPath, prefix []string) bool {
+	if len(fieldPath) <= len(prefix) {
+		return false
+	}
+	for i, name := range prefix {
+		if fieldPath[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// protoFieldPath returns fieldPath with the JSON names of the fields of msg on it replaced by
+// their proto names. Names of unknown fields, and those after them, are returned as they are.
+func protoFieldPath(msg proto.Message, fieldPath []string) []string {
+	t := reflect.TypeOf(msg)
+	result := make([]string, len(fieldPath))
+	copy(result, fieldPath)
+	for i, fieldName := range fieldPath {
+		origName, next := lookupField(t, fieldName)
+		if next == nil {
+			break
+		}
+		result[i] = origName
+		t = next
+	}
+	return result
+}
+
+// lookupField returns the proto name and the Go type of the field of the message type t with the
+// proto or JSON name fieldName, or a nil type if t is not a message type or has no such field.
+func lookupField(t reflect.Type, fieldName string) (string, reflect.Type) {
+	if t == nil {
+		return "", nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", nil
+	}
+	props := proto.GetProperties(t)
+	for _, p := range props.Prop {
+		if p.OrigName == fieldName || p.JSONName == fieldName {
+			if f, ok := t.FieldByName(p.Name); ok {
+				return p.OrigName, f.Type
+			}
+			return "", nil
+		}
+	}
+	for name, op := range props.OneofTypes {
+		if name == fieldName || op.Prop.JSONName == fieldName {
+			return name, op.Type.Elem().Field(0).Type
+		}
+	}
+	return "", nil
+}
+
 // PopulateFieldFromPath sets a value in a nested Protobuf structure.
-// It instantiates missing protobuf fields as it goes.
+// It instantiates missing protobuf fields as it goes, like PopulateQueryParameters, so a path
+// parameter "parent.id" sets the field "id" of the message field "parent".
+// It is an error for the path to traverse a field which is not a singular message.
 func PopulateFieldFromPath(msg proto.Message, fieldPathString string, value string) error {
 	fieldPath := strings.Split(fieldPathString, ".")
-	return populateFieldValueFromPath(msg, fieldPath, []string{value})
+	return populateFieldValueFromPath(msg, fieldPath, []string{value}, nil)
 }
 
-func populateFieldValueFromPath(msg proto.Message, fieldPath []string, values []string) error {
+func populateFieldValueFromPath(msg proto.Message, fieldPath []string, values []string, mux *ServeMux) error {
 	m := reflect.ValueOf(msg)
 	if m.Kind() != reflect.Ptr {
 		return fmt.Errorf("unexpected type %T: %v", msg, msg)
@@ -62,7 +257,7 @@ func populateFieldValueFromPath(msg proto.Message, fieldPath []string, values []
 		if err != nil {
 			return err
 		} else if !f.IsValid() {
-			grpclog.Infof("field not found in %T: %s", msg, strings.Join(fieldPath, "."))
+			loggerOf(mux).Infof("field not found in %T: %s", msg, strings.Join(fieldPath, "."))
 			return nil
 		}
 
@@ -74,15 +269,18 @@ func populateFieldValueFromPath(msg proto.Message, fieldPath []string, values []
 			m = f
 		case reflect.Slice:
 			if !isLast {
-				return fmt.Errorf("unexpected repeated field in %s", strings.Join(fieldPath, "."))
+				return fmt.Errorf("cannot set %s: %s is a repeated field", strings.Join(fieldPath, "."), strings.Join(fieldPath[:i+1], "."))
 			}
 			// Handle []byte
 			if f.Type().Elem().Kind() == reflect.Uint8 {
 				m = f
 				break
 			}
-			return populateRepeatedField(f, values, props)
+			return populateRepeatedField(f, values, props, mux)
 		case reflect.Ptr:
+			if !isLast && f.Type().Elem().Kind() != reflect.Struct {
+				return fmt.Errorf("unexpected nested field %s in %s", fieldPath[i+1], strings.Join(fieldPath[:i+1], "."))
+			}
 			if f.IsNil() {
 				m = reflect.New(f.Type().Elem())
 				f.Set(m.Convert(f.Type()))
@@ -106,9 +304,72 @@ func populateFieldValueFromPath(msg proto.Message, fieldPath []string, values []
 		return fmt.Errorf("no value of field: %s", strings.Join(fieldPath, "."))
 	case 1:
 	default:
-		grpclog.Infof("too many field values: %s", strings.Join(fieldPath, "."))
+		loggerOf(mux).Infof("too many field values: %s", strings.Join(fieldPath, "."))
+	}
+	return populateField(m, values[0], props, mux)
+}
+
+// ApplyQueryDefaults sets the unset fields of msg to the defaults configured with WithQueryDefaults
+// for the gRPC method of the request ctx comes from. It does nothing if ctx does not come from a
+// request dispatched by ServeMux.
+func ApplyQueryDefaults(ctx context.Context, msg proto.Message) error {
+	mux, _ := ctx.Value(serveMuxKey{}).(*ServeMux)
+	method, ok := RPCMethod(ctx)
+	if mux == nil || !ok {
+		return nil
+	}
+	for key, value := range mux.queryDefaults[method] {
+		fieldPath := strings.Split(key, ".")
+		if isFieldSet(msg, fieldPath) {
+			continue
+		}
+		if err := populateFieldValueFromPath(msg, fieldPath, []string{value}, mux); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFieldSet reports whether the field at fieldPath in msg and the messages containing it are set
+// to values other than their zero values, or if another field of a oneof on fieldPath is set. Unlike populateFieldValueFromPath, it does not modify msg.
+func isFieldSet(msg proto.Message, fieldPath []string) bool {
+	m := reflect.ValueOf(msg)
+	for _, fieldName := range fieldPath {
+		for m.Kind() == reflect.Ptr || m.Kind() == reflect.Interface {
+			if m.IsNil() {
+				return false
+			}
+			m = m.Elem()
+		}
+		if m.Kind() != reflect.Struct {
+			return false
+		}
+		props := proto.GetProperties(m.Type())
+		if op, ok := props.OneofTypes[fieldName]; ok {
+			field := m.Field(op.Field)
+			if field.IsNil() {
+				return false
+			}
+			if field.Elem().Type() != op.Type {
+				// another field of the oneof is set
+				return true
+			}
+			m = field.Elem().Elem().Field(0)
+			continue
+		}
+		var f reflect.Value
+		for _, p := range props.Prop {
+			if p.OrigName == fieldName || p.JSONName == fieldName {
+				f = m.FieldByName(p.Name)
+				break
+			}
+		}
+		if !f.IsValid() || f.IsZero() {
+			return false
+		}
+		m = f
 	}
-	return populateField(m, values[0], props)
+	return true
 }
 
 // fieldByProtoName looks up a field whose corresponding protobuf field name is "name".
@@ -173,32 +434,60 @@ func populateMapField(f reflect.Value, values []string, props *proto.Properties)
 	return nil
 }
 
-func populateRepeatedField(f reflect.Value, values []string, props *proto.Properties) error {
+func populateRepeatedField(f reflect.Value, values []string, props *proto.Properties, mux *ServeMux) error {
 	elemType := f.Type().Elem()
 
 	// is the destination field a slice of an enumeration type?
 	if enumValMap := proto.EnumValueMap(props.Enum); enumValMap != nil {
-		return populateFieldEnumRepeated(f, values, enumValMap)
+		return populateFieldEnumRepeated(f, values, enumValMap, mux)
 	}
 
 	conv, ok := convFromType[elemType.Kind()]
 	if !ok {
 		return fmt.Errorf("unsupported field type %s", elemType)
 	}
-	f.Set(reflect.MakeSlice(f.Type(), len(values), len(values)).Convert(f.Type()))
+	n := f.Len()
+	f.Set(reflect.AppendSlice(f, reflect.MakeSlice(f.Type(), len(values), len(values))))
 	for i, v := range values {
 		result := conv.Call([]reflect.Value{reflect.ValueOf(v)})
 		if err := result[1].Interface(); err != nil {
 			return err.(error)
 		}
-		f.Index(i).Set(result[0].Convert(f.Index(i).Type()))
+		f.Index(n + i).Set(result[0].Convert(f.Index(n + i).Type()))
 	}
 	return nil
 }
 
-func populateField(f reflect.Value, value string, props *proto.Properties) error {
+// wrapperTypes are the names of the wrapper types in google/protobuf/wrappers.proto.
+var wrapperTypes = map[string]bool{
+	"DoubleValue": true,
+	"FloatValue":  true,
+	"Int64Value":  true,
+	"UInt64Value": true,
+	"Int32Value":  true,
+	"UInt32Value": true,
+	"BoolValue":   true,
+	"StringValue": true,
+	"BytesValue":  true,
+}
+
+func populateField(f reflect.Value, value string, props *proto.Properties, mux *ServeMux) error {
 	i := f.Addr().Interface()
 
+	if m, ok := i.(proto.Message); ok && mux != nil {
+		if decoder, ok := mux.customTypeDecoders[proto.MessageName(m)]; ok {
+			decoded, err := decoder(value)
+			if err != nil {
+				return fmt.Errorf("bad %s: %v", proto.MessageName(m), err)
+			}
+			if reflect.TypeOf(decoded) != f.Addr().Type() {
+				return fmt.Errorf("decoder for %s returned %T", proto.MessageName(m), decoded)
+			}
+			f.Set(reflect.ValueOf(decoded).Elem())
+			return nil
+		}
+	}
+
 	// Handle protobuf well known types
 	var name string
 	switch m := i.(type) {
@@ -210,6 +499,12 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 			name = fullName[len(wktPrefix):]
 		}
 	}
+	if wrapperTypes[name] && value == "" {
+		// An empty value sets the wrapper to its zero value, which unlike an absent
+		// parameter is present, for filters telling null, zero and other values apart.
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
 	switch name {
 	case "Timestamp":
 		if value == "null" {
@@ -247,7 +542,7 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 	case "FloatValue":
 		float64Val, err := strconv.ParseFloat(value, 64)
 		if err != nil {
-			return fmt.Errorf("bad DoubleValue: %s", value)
+			return fmt.Errorf("bad %s: %s", name, value)
 		}
 		f.FieldByName("Value").SetFloat(float64Val)
 		return nil
@@ -256,7 +551,7 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 	case "Int32Value":
 		int64Val, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			return fmt.Errorf("bad DoubleValue: %s", value)
+			return fmt.Errorf("bad %s: %s", name, value)
 		}
 		f.FieldByName("Value").SetInt(int64Val)
 		return nil
@@ -265,7 +560,7 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 	case "UInt32Value":
 		uint64Val, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
-			return fmt.Errorf("bad DoubleValue: %s", value)
+			return fmt.Errorf("bad %s: %s", name, value)
 		}
 		f.FieldByName("Value").SetUint(uint64Val)
 		return nil
@@ -318,7 +613,7 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 
 	// is the destination field an enumeration type?
 	if enumValMap := proto.EnumValueMap(props.Enum); enumValMap != nil {
-		return populateFieldEnum(f, value, enumValMap)
+		return populateFieldEnum(f, value, enumValMap, mux)
 	}
 
 	conv, ok := convFromType[f.Kind()]
@@ -333,27 +628,17 @@ func populateField(f reflect.Value, value string, props *proto.Properties) error
 	return nil
 }
 
-func convertEnum(value string, t reflect.Type, enumValMap map[string]int32) (reflect.Value, error) {
-	// see if it's an enumeration string
-	if enumVal, ok := enumValMap[value]; ok {
-		return reflect.ValueOf(enumVal).Convert(t), nil
-	}
-
-	// check for an integer that matches an enumeration value
-	eVal, err := strconv.Atoi(value)
+// convertEnum returns the value of t for the name or the number of an enum value in enumValMap.
+func convertEnum(value string, t reflect.Type, enumValMap map[string]int32, mux *ServeMux) (reflect.Value, error) {
+	enumVal, err := resolveEnum(value, enumValMap, mux != nil && mux.caseInsensitiveEnums)
 	if err != nil {
-		return reflect.Value{}, fmt.Errorf("%s is not a valid %s", value, t)
-	}
-	for _, v := range enumValMap {
-		if v == int32(eVal) {
-			return reflect.ValueOf(eVal).Convert(t), nil
-		}
+		return reflect.Value{}, err
 	}
-	return reflect.Value{}, fmt.Errorf("%s is not a valid %s", value, t)
+	return reflect.ValueOf(enumVal).Convert(t), nil
 }
 
-func populateFieldEnum(f reflect.Value, value string, enumValMap map[string]int32) error {
-	cval, err := convertEnum(value, f.Type(), enumValMap)
+func populateFieldEnum(f reflect.Value, value string, enumValMap map[string]int32, mux *ServeMux) error {
+	cval, err := convertEnum(value, f.Type(), enumValMap, mux)
 	if err != nil {
 		return err
 	}
@@ -361,15 +646,16 @@ func populateFieldEnum(f reflect.Value, value string, enumValMap map[string]int3
 	return nil
 }
 
-func populateFieldEnumRepeated(f reflect.Value, values []string, enumValMap map[string]int32) error {
+func populateFieldEnumRepeated(f reflect.Value, values []string, enumValMap map[string]int32, mux *ServeMux) error {
 	elemType := f.Type().Elem()
-	f.Set(reflect.MakeSlice(f.Type(), len(values), len(values)).Convert(f.Type()))
+	n := f.Len()
+	f.Set(reflect.AppendSlice(f, reflect.MakeSlice(f.Type(), len(values), len(values))))
 	for i, v := range values {
-		result, err := convertEnum(v, elemType, enumValMap)
+		result, err := convertEnum(v, elemType, enumValMap, mux)
 		if err != nil {
 			return err
 		}
-		f.Index(i).Set(result)
+		f.Index(n + i).Set(result)
 	}
 	return nil
 }
@@ -3,8 +3,11 @@ package runtime_test
 import (
 	"errors"
 	"fmt"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
+	pb "github.com/ninnemana/grpc-gateway/examples/proto/examplepb"
 	"github.com/ninnemana/grpc-gateway/runtime"
 	"github.com/ninnemana/grpc-gateway/utilities"
 	"google.golang.org/genproto/protobuf/field_mask"
@@ -639,6 +643,166 @@ func TestPopulateQueryParametersWithInvalidNestedParameters(t *testing.T) {
 	}
 }
 
+func TestPopulateQueryParametersNestedRepeated(t *testing.T) {
+	msg := &proto3Message{}
+	values := url.Values{
+		"nested.repeated_value":             {"a", "b"},
+		"nested.repeatedValue":              {"c"},
+		"nested.nested.repeated_enum":       {"EnumValue_Y", "EnumValue_Z"},
+		"nested_non_null.repeated_value":    {"d"},
+		"nested.nested.nested.string_value": {"e"},
+	}
+	if err := runtime.PopulateQueryParameters(msg, values, utilities.NewDoubleArray(nil)); err != nil {
+		t.Fatalf("runtime.PopulateQueryParameters(msg, %v, nil) failed with %v; want success", values, err)
+	}
+
+	got := append([]string(nil), msg.GetNested().RepeatedValue...)
+	sort.Strings(got)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("msg.Nested.RepeatedValue = %q; want %q", got, want)
+	}
+	if got, want := msg.GetNested().GetNested().RepeatedEnum, []EnumValue{EnumValue_Y, EnumValue_Z}; !reflect.DeepEqual(got, want) {
+		t.Errorf("msg.Nested.Nested.RepeatedEnum = %v; want %v", got, want)
+	}
+	if got, want := msg.NestedNonNull.RepeatedValue, []string{"d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("msg.NestedNonNull.RepeatedValue = %q; want %q", got, want)
+	}
+	if got, want := msg.GetNested().GetNested().GetNested().GetStringValue(), "e"; got != want {
+		t.Errorf("msg.Nested.Nested.Nested.StringValue = %q; want %q", got, want)
+	}
+
+	for _, key := range []string{"nested.string_value.foo", "nested.repeated_value.foo", "nested.nested.enum_value.foo"} {
+		msg := &proto3Message{}
+		values := url.Values{key: {"a"}}
+		if err := runtime.PopulateQueryParameters(msg, values, utilities.NewDoubleArray(nil)); err == nil {
+			t.Errorf("runtime.PopulateQueryParameters(msg, %v, nil) did not fail; want error", values)
+		}
+		if nested := msg.GetNested(); nested != nil && nested.StringValue != nil {
+			t.Errorf("msg.Nested.StringValue = %q after traversing it; want nil", nested.GetStringValue())
+		}
+	}
+}
+
+func TestPopulateFieldFromPathNested(t *testing.T) {
+	for _, spec := range []struct {
+		path  string
+		value string
+		want  proto.Message
+	}{
+		{
+			path:  "single_nested.name",
+			value: "foo",
+			want:  &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Name: "foo"}},
+		},
+		{
+			path:  "singleNested.amount",
+			value: "5",
+			want:  &pb.ABitOfEverything{SingleNested: &pb.ABitOfEverything_Nested{Amount: 5}},
+		},
+	} {
+		msg := &pb.ABitOfEverything{}
+		if err := runtime.PopulateFieldFromPath(msg, spec.path, spec.value); err != nil {
+			t.Errorf("runtime.PopulateFieldFromPath(msg, %q, %q) failed with %v; want success", spec.path, spec.value, err)
+			continue
+		}
+		if !proto.Equal(msg, spec.want) {
+			t.Errorf("runtime.PopulateFieldFromPath(msg, %q, %q): msg = %v; want %v", spec.path, spec.value, msg, spec.want)
+		}
+	}
+
+	msg := &pb.ABitOfEverything{}
+	err := runtime.PopulateFieldFromPath(msg, "nested.name", "foo")
+	if want := "cannot set nested.name: nested is a repeated field"; err == nil || err.Error() != want {
+		t.Errorf("runtime.PopulateFieldFromPath(msg, %q, %q) failed with %v; want %q", "nested.name", "foo", err, want)
+	}
+}
+
+func TestPopulateParametersEnum(t *testing.T) {
+	for _, spec := range []struct {
+		value           string
+		caseInsensitive bool
+		want            EnumValue
+		wantErr         bool
+	}{
+		{value: "EnumValue_Z", want: EnumValue_Z},
+		{value: "1", want: EnumValue_Y},
+		{value: "enumvalue_z", wantErr: true},
+		{value: "enumvalue_z", caseInsensitive: true, want: EnumValue_Z},
+		{value: "3", wantErr: true},
+		{value: "EnumValue_W", caseInsensitive: true, wantErr: true},
+	} {
+		var opts []runtime.ServeMuxOption
+		if spec.caseInsensitive {
+			opts = append(opts, runtime.WithCaseInsensitiveEnums())
+		}
+		ctx, err := annotateThroughMux(t, httptest.NewRequest("GET", "http://example.com/foo", nil), opts...)
+		if err != nil {
+			t.Fatalf("annotateThroughMux failed with %v; want success", err)
+		}
+		msg := &proto3Message{}
+		values := url.Values{"enum_value": {spec.value}, "repeated_enum": {spec.value}}
+		err = runtime.PopulateQueryParametersContext(ctx, msg, values, utilities.NewDoubleArray(nil))
+		if spec.wantErr {
+			if want := fmt.Sprintf("%s is not valid, want one of EnumValue_X, EnumValue_Y, EnumValue_Z or their numbers", spec.value); err == nil || err.Error() != want {
+				t.Errorf("runtime.PopulateQueryParametersContext(ctx, msg, %v, nil) failed with %v; want %q", values, err, want)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("runtime.PopulateQueryParametersContext(ctx, msg, %v, nil) failed with %v; want success", values, err)
+			continue
+		}
+		if msg.EnumValue != spec.want {
+			t.Errorf("msg.EnumValue = %v; want %v", msg.EnumValue, spec.want)
+		}
+		if got, want := msg.RepeatedEnum, []EnumValue{spec.want}; !reflect.DeepEqual(got, want) {
+			t.Errorf("msg.RepeatedEnum = %v; want %v", got, want)
+		}
+
+		if spec.caseInsensitive {
+			// Path parameters honor WithCaseInsensitiveEnums through runtime.EnumContext.
+			continue
+		}
+		msg = &proto3Message{}
+		if err := runtime.PopulateFieldFromPath(msg, "enum_value", spec.value); err != nil {
+			t.Errorf("runtime.PopulateFieldFromPath(msg, %q, %q) failed with %v; want success", "enum_value", spec.value, err)
+		} else if msg.EnumValue != spec.want {
+			t.Errorf("msg.EnumValue = %v; want %v", msg.EnumValue, spec.want)
+		}
+	}
+}
+
+func TestPopulateParametersWrapperPresence(t *testing.T) {
+	msg := &proto3Message{}
+	values := url.Values{
+		"wrapper_int32_value":  {""},
+		"wrapper_bool_value":   {""},
+		"wrapper_string_value": {""},
+		"wrapper_double_value": {"0"},
+	}
+	if err := runtime.PopulateQueryParameters(msg, values, utilities.NewDoubleArray(nil)); err != nil {
+		t.Fatalf("runtime.PopulateQueryParameters(msg, %v, nil) failed with %v; want success", values, err)
+	}
+	want := &proto3Message{
+		WrapperInt32Value:  &wrappers.Int32Value{},
+		WrapperBoolValue:   &wrappers.BoolValue{},
+		WrapperStringValue: &wrappers.StringValue{},
+		WrapperDoubleValue: &wrappers.DoubleValue{},
+	}
+	if !proto.Equal(msg, want) {
+		t.Errorf("msg = %v; want %v", msg, want)
+	}
+	if msg.WrapperInt64Value != nil {
+		t.Errorf("msg.WrapperInt64Value = %v; want nil for an absent parameter", msg.WrapperInt64Value)
+	}
+
+	values = url.Values{"wrapper_int32_value": {"x"}}
+	err := runtime.PopulateQueryParameters(&proto3Message{}, values, utilities.NewDoubleArray(nil))
+	if err == nil || !strings.Contains(err.Error(), "bad Int32Value") {
+		t.Errorf("runtime.PopulateQueryParameters(msg, %v, nil) failed with %v; want a bad Int32Value error", values, err)
+	}
+}
+
 type proto3Message struct {
 	Nested             *proto2Message           `protobuf:"bytes,1,opt,name=nested,json=nested" json:"nested,omitempty"`
 	NestedNonNull      proto2Message            `protobuf:"bytes,15,opt,name=nested_non_null,json=nestedNonNull" json:"nested_non_null,omitempty"`
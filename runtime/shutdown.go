@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requestTracker keeps track of the requests a ServeMux is serving so that
+// Shutdown can wait for them to finish.
+type requestTracker struct {
+	mu       sync.Mutex
+	shutdown bool
+	nextID   uint64
+	active   map[uint64]context.CancelFunc
+	// idle is closed once no request is active after Shutdown was called.
+	idle chan struct{}
+}
+
+// begin registers r as active and returns it with a context which is canceled
+// if Shutdown times out, along with the func to call when r is done.
+// ok is false if the mux is shutting down.
+func (t *requestTracker) begin(r *http.Request) (_ *http.Request, done func(), ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shutdown {
+		return r, nil, false
+	}
+	if t.active == nil {
+		t.active = make(map[uint64]context.CancelFunc)
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	id := t.nextID
+	t.nextID++
+	t.active[id] = cancel
+	return r.WithContext(ctx), func() { t.end(id) }, true
+}
+
+func (t *requestTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cancel, ok := t.active[id]; ok {
+		cancel()
+		delete(t.active, id)
+	}
+	if t.shutdown && len(t.active) == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// Shutdown gracefully shuts down the mux: it replies to new requests with
+// codes.Unavailable and waits for the requests in flight, including streams,
+// to finish. If ctx is done first, the contexts of the remaining requests are
+// canceled, which ends their streams, and ctx.Err() is returned.
+//
+// Shutdown does not close listeners or connections; call it before or along with
+// http.Server.Shutdown, which does not wait for hijacked or streaming responses
+// to end on their own.
+func (s *ServeMux) Shutdown(ctx context.Context) error {
+	t := &s.requests
+	t.mu.Lock()
+	t.shutdown = true
+	if len(t.active) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		for _, cancel := range t.active {
+			cancel()
+		}
+		t.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// replyShuttingDown replies to a request received after Shutdown was called.
+func (s *ServeMux) replyShuttingDown(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Connection", "close")
+	if s.protoErrorHandler != nil {
+		_, outboundMarshaler := MarshalerForRequest(s, r)
+		sterr := status.Error(codes.Unavailable, "server is shutting down")
+		s.protoErrorHandler(r.Context(), s, outboundMarshaler, w, r, sterr)
+		return
+	}
+	OtherErrorHandler(w, r, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}